@@ -414,9 +414,8 @@ var fmtTests = []struct {
 
 	// go syntax
 	{"%#v", A{1, 2, "a", []int{1, 2}}, `fmt_test.A{i:1, j:0x2, s:"a", x:[]int{1, 2}}`},
-	{"%#v", &b, "(*uint8)(0xPTR)"}, // intermittent failure on tardisgo ... randomly byte/uint8 - TODO
-	//{"%#v", TestFmtInterface, "(func(*testing.T))(0xPTR)"},
-	{"%#v", TestFmtInterface, "(func(t *testing.T))(0xPTR)"}, // tardisgo replacement line
+	{"%#v", &b, "(*uint8)(0xPTR)"},
+	{"%#v", TestFmtInterface, "(func(*testing.T))(0xPTR)"},
 	{"%#v", make(chan int), "(chan int)(0xPTR)"},
 	//{"%#v", make(chan int), "(chan int)(nil)"}, // tardisgo replacement line
 	{"%#v", uint64(1<<64 - 1), "0xffffffffffffffff"},
@@ -424,21 +423,15 @@ var fmtTests = []struct {
 	{"%#v", map[string]int{"a": 1}, `map[string]int{"a":1}`},
 	{"%#v", map[string]B{"a": {1, 2}}, `map[string]fmt_test.B{"a":fmt_test.B{I:1, j:2}}`},
 	{"%#v", []string{"a", "b"}, `[]string{"a", "b"}`},
-	//{"%#v", SI{}, `fmt_test.SI{I:interface {}(nil)}`},
-	{"%#v", SI{}, `fmt_test.SI{I:interface{}(nil)}`}, // tardisgo replacement line
+	{"%#v", SI{}, `fmt_test.SI{I:interface {}(nil)}`},
 	{"%#v", []int(nil), `[]int(nil)`},
 	{"%#v", []int{}, `[]int{}`},
 	{"%#v", array, `[5]int{1, 2, 3, 4, 5}`},
 	{"%#v", &array, `&[5]int{1, 2, 3, 4, 5}`},
-	//{"%#v", iarray, `[4]interface {}{1, "hello", 2.5, interface {}(nil)}`},
-	{"%#v", iarray, `[4]interface{}{1, "hello", 2.5, interface{}(nil)}`}, // tardisgo replacement line
-	//{"%#v", &iarray, `&[4]interface {}{1, "hello", 2.5, interface {}(nil)}`},
-	{"%#v", &iarray, `&[4]interface{}{1, "hello", 2.5, interface{}(nil)}`}, // tardisgo replacement line
-	//{"%#v", map[int]byte(nil), `map[int]uint8(nil)`},
-	//{"%#v", map[int]byte(nil), `map[int]byte{}`}, // tardisgo replacement line
-	{"%#v", map[int]byte(nil), `map[int]byte(nil)`}, // 2nd tardisgo replacement line
-	//{"%#v", map[int]byte{}, `map[int]uint8{}`},
-	{"%#v", map[int]byte{}, `map[int]byte{}`}, // tardisgo replacement line
+	{"%#v", iarray, `[4]interface {}{1, "hello", 2.5, interface {}(nil)}`},
+	{"%#v", &iarray, `&[4]interface {}{1, "hello", 2.5, interface {}(nil)}`},
+	{"%#v", map[int]byte(nil), `map[int]uint8(nil)`},
+	{"%#v", map[int]byte{}, `map[int]uint8{}`},
 	{"%#v", "foo", `"foo"`},
 	{"%#v", barray, `[5]fmt_test.renamedUint8{0x1, 0x2, 0x3, 0x4, 0x5}`},
 	{"%#v", bslice, `[]fmt_test.renamedUint8{0x1, 0x2, 0x3, 0x4, 0x5}`},
@@ -15,6 +15,7 @@ var JScallbackOK = false
 func BrowserMain(mainFN func(), msInvocationInterval, runLimit int) {
 	if runtime.GOARCH == "js" { // probably only want to do this in a browser
 		JScallbackOK = true
+		hx.SetBool("js", "BrowserStore.eventLoop", true)
 		ht := hx.New("js", "haxe.Timer", 1, msInvocationInterval)
 		go func() { // put the main program into a goroutine
 			mainFN()
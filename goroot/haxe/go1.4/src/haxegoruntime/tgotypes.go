@@ -302,11 +302,6 @@ func init() {
 	//println("DEBUG sizeof(funcType{}) = ", unsafe.Sizeof(funcType{}))
 }
 
-func AddHaxeType(ptr unsafe.Pointer) {
-	TypeTable = append(TypeTable, (*rtype)(ptr))
-	hx.SetInt("", "TypeInfo.nextTypeID", len(TypeTable))
-}
-
 func typetest() {
 	for i, tp := range TypeTable {
 		if tp != nil {
@@ -315,19 +310,22 @@ func typetest() {
 	}
 }
 
-func getTypeString(id int) string {
-	if id < 1 || id >= len(TypeTable) { // entry 0 is always nil
+// The type id functions are called from Haxe, so use int32 whatever the size of int.
+func getTypeString(id int32) string {
+	if id < 1 || int(id) >= len(TypeTable) { // entry 0 is always nil
 		return "<Type Not Found!>"
 	}
 	rt := TypeTable[id]
 	return *(rt.string)
 }
 
-func getTypeID(s string) int {
+// getTypeID finds a type by its reflect string, for the types created at run time,
+// as TypeInfo.getId finds the compiled types by their names with full package paths.
+func getTypeID(s string) int32 {
 again:
 	for id := 1; id < len(TypeTable); id++ { // TODO optimise this runtime loop to use a map
 		if s == *(TypeTable[id].string) {
-			return id
+			return int32(id)
 		}
 	}
 	switch s {
@@ -345,9 +343,9 @@ again:
 	goto again
 }
 
-func getMethod(tid int, path, name string) uintptr {
+func getMethod(tid int32, path, name string) uintptr {
 	//println("DEBUG getMethod:", tid, path, name)
-	if tid < 1 || tid >= len(TypeTable) { // entry 0 is always nil
+	if tid < 1 || int(tid) >= len(TypeTable) { // entry 0 is always nil
 		hx.Call("", "Scheduler.panicFromHaxe", 1, "haxegoruntime.method() type id out of range")
 	}
 	rt := TypeTable[tid]
@@ -423,13 +421,13 @@ func getMethod(tid int, path, name string) uintptr {
 	return 0
 }
 
-func assertableTo(vid, tid int) bool {
+func assertableTo(vid, tid int32) bool {
 	// id equality test done in Haxe
-	if vid < 1 || vid >= len(TypeTable) { // entry 0 is always nil
+	if vid < 1 || int(vid) >= len(TypeTable) { // entry 0 is always nil
 		hx.Call("", "Scheduler.panicFromHaxe", 1, "haxegoruntime.assertableTo() tested type id out of range")
 	}
 	V := TypeTable[vid]
-	if tid < 1 || tid >= len(TypeTable) { // entry 0 is always nil
+	if tid < 1 || int(tid) >= len(TypeTable) { // entry 0 is always nil
 		hx.Call("", "Scheduler.panicFromHaxe", 1, "haxegoruntime.assertableTo() interface type id out of range")
 	}
 	T := TypeTable[tid]
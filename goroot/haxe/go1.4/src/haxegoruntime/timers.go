@@ -9,8 +9,8 @@ import (
 
 // TODO optimize to use the Timer call-back methods for the targets - flash, java, js, python
 func HaxeWait(target *int64, whileTrue *bool) {
-	fNow := hx.CallFloat("", "haxe.Timer.stamp", 0)
-	fTarget := reverseNano(*target)
+	fNow := RuntimeNano()
+	fTarget := *target
 	//println("DEBUG haxeWait:start now, target, *whileTrue diff = ", fNow, *target, *whileTrue, fTarget-fNow)
 	/* this "optimization" is not working, and may not be better anyway
 	useCallback := false
@@ -24,7 +24,7 @@ func HaxeWait(target *int64, whileTrue *bool) {
 	}
 	if useCallback {
 		wait := true
-		ms := int(1000 * (fTarget - fNow))
+		ms := int((fTarget - fNow) / 1000000)
 		println("DEBUG TIMER MS DELAY=", ms)
 		if ms > 0 {
 			tmr := hx.New("flash||java||js||python", "haxe.Timer", 1, ms)
@@ -43,7 +43,7 @@ func HaxeWait(target *int64, whileTrue *bool) {
 	*/
 	for fNow < fTarget && *whileTrue {
 		runtime.Gosched() // let other code run
-		fNow = hx.CallFloat("", "haxe.Timer.stamp", 0)
+		fNow = RuntimeNano()
 		//println("DEBUG haxeWait:loop now, target, *whileTrue diff = ", fNow, *target, *whileTrue, fTarget-fNow)
 	}
 	/*}*/
@@ -51,20 +51,8 @@ func HaxeWait(target *int64, whileTrue *bool) {
 
 // RuntimeNano returns the current value of the runtime clock in nanoseconds.
 func RuntimeNano() int64 { // function body is an Haxe addition
-	fv := hx.CallFloat("", "haxe.Timer.stamp", 0)
-	// cs and maybe Java have stamp values too large for int64, so set a baseline
-	if runtimeNanoBase == 0 {
-		//println("DEBUG set runtimeNanoBase")
-		runtimeNanoBase = fv
-	}
-	fv -= runtimeNanoBase
-	return int64(fv * 1000000000) // haxe.Timer.stamp is in seconds
-}
-
-var runtimeNanoBase float64
-
-func reverseNano(i int64) float64 { // reverse of the above
-	return runtimeNanoBase + float64(i)/1000000000
+	// the host monotonic clock, with a baseline set on first use, as the values for cs and java are too large for int64
+	return int64(hx.CallFloat("", "HostClock.monoNano", 0))
 }
 
 // Interface to timers implemented in package runtime.
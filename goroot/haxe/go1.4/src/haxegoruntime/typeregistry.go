@@ -0,0 +1,93 @@
+// Copyright 2014 Elliott Stoneham and The TARDIS Go Authors
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package haxegoruntime
+
+import (
+	"unsafe"
+
+	"github.com/tardisgo/tardisgo/haxe/hx"
+)
+
+// The type registry gives each type created at run time (by reflect.PtrTo, ChanOf, MapOf, SliceOf or funcOf)
+// the next type id after those in the compile-time TypeTable, so that it can be used in a Haxe Interface.
+
+var typeIDs map[*rtype]int // the id of each type in TypeTable, built on first use
+
+var typeLinks []*rtype // the types that may be looked up by reflect, sorted by string, built on first use
+
+// AddHaxeType registers a type created at run time, returning its type id.
+func AddHaxeType(ptr unsafe.Pointer) int {
+	if id := TypeID(ptr); id != 0 {
+		return id // already registered
+	}
+	rt := (*rtype)(ptr)
+	TypeTable = append(TypeTable, rt)
+	id := len(TypeTable) - 1
+	typeIDs[rt] = id
+	hx.SetInt("", "TypeInfo.nextTypeID", len(TypeTable))
+	if typeLinks != nil && isTypeLink(rt) {
+		i := len(typeLinks)
+		typeLinks = append(typeLinks, rt)
+		for i > 0 && *typeLinks[i-1].string > *rt.string { // keep in string order
+			typeLinks[i] = typeLinks[i-1]
+			i--
+		}
+		typeLinks[i] = rt
+	}
+	return id
+}
+
+// TypeID returns the type id of the type at ptr, or 0 if it is not known.
+func TypeID(ptr unsafe.Pointer) int {
+	if typeIDs == nil {
+		typeIDs = make(map[*rtype]int, len(TypeTable))
+		for id := 1; id < len(TypeTable); id++ { // entry 0 is always nil
+			if TypeTable[id] != nil {
+				typeIDs[TypeTable[id]] = id
+			}
+		}
+	}
+	return typeIDs[(*rtype)(ptr)]
+}
+
+// isTypeLink returns true for the kinds of type that reflect looks up by string before creating one.
+func isTypeLink(rt *rtype) bool {
+	switch rt.kind & kindMask {
+	case Array, Chan, Func, Map, Slice:
+		return true
+	}
+	return false
+}
+
+// TypeLinks returns the types that may be looked up by reflect, sorted by string, for reflect.typelinks().
+func TypeLinks() []unsafe.Pointer {
+	if typeLinks == nil {
+		typeLinks = make([]*rtype, 0, len(TypeTable))
+		for id := 1; id < len(TypeTable); id++ {
+			if TypeTable[id] != nil && isTypeLink(TypeTable[id]) &&
+				!hx.CallBool("", "TypeInfo.isMinimal", 1, id) { // reflect cannot use a type without its full information
+				typeLinks = append(typeLinks, TypeTable[id])
+			}
+		}
+		// Shell sort, to avoid importing sort.
+		for gap := len(typeLinks) / 2; gap > 0; gap /= 2 {
+			for i := gap; i < len(typeLinks); i++ {
+				for j := i; j >= gap && *typeLinks[j-gap].string > *typeLinks[j].string; j -= gap {
+					typeLinks[j], typeLinks[j-gap] = typeLinks[j-gap], typeLinks[j]
+				}
+			}
+		}
+	}
+	ret := make([]unsafe.Pointer, len(typeLinks))
+	for i, rt := range typeLinks {
+		ret[i] = unsafe.Pointer(rt)
+	}
+	return ret
+}
+
+// Comparable returns true if values of the type at ptr can be compared with ==, so it can be a map key.
+func Comparable(ptr unsafe.Pointer) bool {
+	return (*rtype)(ptr).alg != nil // only set by newRtype for comparable types, and copied with the prototype for created types
+}
@@ -0,0 +1,46 @@
+// Copyright 2014 Elliott Stoneham and The TARDIS Go Authors
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// +build haxe
+
+package http_test
+
+import (
+	"io/ioutil"
+	"net"
+	. "net/http"
+	"testing"
+)
+
+// TestHaxeLocalhost is a Haxe addition, checking that a server and client in the same program can talk via localhost,
+// which uses the host network for the sys targets and node, see syscall/net_host_haxe.go,
+// so it checks that goroutines waiting for host sockets let each other run.
+func TestHaxeLocalhost(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	defer ln.Close()
+	go Serve(ln, HandlerFunc(func(w ResponseWriter, r *Request) {
+		w.Header().Set("X-Path", r.URL.Path)
+		w.Write([]byte("hello from " + r.URL.Path))
+	}))
+
+	url := "http://" + ln.Addr().String()
+	for _, path := range []string{"/a", "/bb"} { // the second request may reuse the connection
+		res, err := Get(url + path)
+		if err != nil {
+			t.Fatalf("Get(%q): %v", path, err)
+		}
+		body, err := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			t.Fatalf("reading the body for %q: %v", path, err)
+		}
+		if string(body) != "hello from "+path || res.Header.Get("X-Path") != path {
+			t.Errorf("Get(%q) = %q with X-Path %q", path, body, res.Header.Get("X-Path"))
+		}
+	}
+	DefaultTransport.(*Transport).CloseIdleConnections()
+}
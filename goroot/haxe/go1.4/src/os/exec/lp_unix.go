@@ -10,6 +10,9 @@ import (
 	"errors"
 	"os"
 	"strings"
+	"syscall"
+
+	"github.com/tardisgo/tardisgo/haxe/hx"
 )
 
 // ErrNotFound is the error resulting if a path search failed to find an executable file.
@@ -26,6 +29,18 @@ func findExecutable(file string) error {
 	return os.ErrPermission
 }
 
+// hostExecutable reports whether path is an executable file in the host file system, for when host processes can be started.
+// The path is resolved as StartProcess resolves it, using the current directory and the host mounts.
+// Some hosts do not give the mode of a file, in which case any file is taken to be executable.
+// This function is a Haxe addition.
+func hostExecutable(path string) bool {
+	if !hx.CallBool("", "HostFS.stat", 1, syscall.HostExecPath(path)) || hx.GetBool("", "HostFS.statIsDir") {
+		return false
+	}
+	m := hx.GetInt("", "HostFS.statMode")
+	return m == 0 || m&0111 != 0
+}
+
 // LookPath searches for an executable binary named file
 // in the directories named by the PATH environment variable.
 // If file contains a slash, it is tried directly and the PATH is not consulted.
@@ -35,9 +50,13 @@ func LookPath(file string) (string, error) {
 	// (only bypass the path if file begins with / or ./ or ../)
 	// but that would not match all the Unix shells.
 
+	// Haxe addition: when host processes can be started, the executable files may be in the host file system,
+	// where PATH is that of the host, as well as in the simulated one, see syscall/exec_host_haxe.go.
+	onHost := hx.CallBool("", "HostProc.available", 0)
+
 	if strings.Contains(file, "/") {
 		err := findExecutable(file)
-		if err == nil {
+		if err == nil || onHost && hostExecutable(file) {
 			return file, nil
 		}
 		return "", &Error{file, err}
@@ -52,7 +71,7 @@ func LookPath(file string) (string, error) {
 			dir = "."
 		}
 		path := dir + "/" + file
-		if err := findExecutable(path); err == nil {
+		if err := findExecutable(path); err == nil || onHost && hostExecutable(path) {
 			return path, nil
 		}
 	}
@@ -0,0 +1,75 @@
+// Copyright 2014 Elliott Stoneham and The TARDIS Go Authors
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// +build haxe
+
+package signal
+
+import (
+	"os"
+	"syscall"
+
+	"github.com/tardisgo/tardisgo/haxe/hx"
+)
+
+// Haxe change: the runtime functions below are implemented using the HostOS Haxe class,
+// which can only catch SIGINT and SIGTERM, and only on some targets,
+// so Notify for other signals, or on other targets, has no effect.
+
+func signal_disable(sig uint32) {
+	hx.CallBool("", "HostOS.notify", 2, int(sig), false)
+}
+
+var looping bool
+
+var signalReady = make(chan bool, 1) // sent a value by HostOS when a signal arrives
+
+func signal_enable(sig uint32) {
+	if hx.CallBool("", "HostOS.notify", 2, int(sig), true) && !looping {
+		looping = true // only wait for signals once one can arrive
+		hx.Code("", "HostOS.ready=_a.param(0).val;", signalReady)
+		go loop()
+	}
+}
+
+// signal_recv waits for the host to deliver a signal, parked until HostOS reports that one has arrived.
+func signal_recv() uint32 {
+	for {
+		if s := hx.CallInt("", "HostOS.nextSignal", 0); s >= 0 {
+			return uint32(s)
+		}
+		<-signalReady
+	}
+}
+
+func loop() {
+	for {
+		process(syscall.Signal(signal_recv()))
+	}
+}
+
+const (
+	numSig = 65 // max across all systems
+)
+
+func signum(sig os.Signal) int {
+	switch sig := sig.(type) {
+	case syscall.Signal:
+		i := int(sig)
+		if i < 0 || i >= numSig {
+			return -1
+		}
+		return i
+	default:
+		return -1
+	}
+}
+
+func enableSignal(sig int) {
+	signal_enable(uint32(sig))
+}
+
+func disableSignal(sig int) {
+	signal_disable(uint32(sig))
+}
@@ -3,6 +3,7 @@
 // license that can be found in the LICENSE file.
 
 // +build darwin dragonfly freebsd linux nacl netbsd openbsd solaris windows
+// +build !haxe
 
 package signal
 
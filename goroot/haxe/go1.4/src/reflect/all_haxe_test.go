@@ -408,11 +408,9 @@ func TestAll(t *testing.T) {
 		c chan *int32
 		d float32
 	})(nil))
-	//testType(t, 3, typ, "*struct { c chan *int32; d float32 }")
-	testType(t, 3, typ, "*struct{c chan *int32; d float32}") // tardisgo replacement line
+	testType(t, 3, typ, "*struct { c chan *int32; d float32 }")
 	etyp := typ.Elem()
-	//testType(t, 4, etyp, "struct { c chan *int32; d float32 }")
-	testType(t, 4, etyp, "struct{c chan *int32; d float32}") // tardisgo replacement line
+	testType(t, 4, etyp, "struct { c chan *int32; d float32 }")
 	styp := etyp
 	f := styp.Field(0)
 	testType(t, 5, f.Type, "chan *int32")
@@ -1003,6 +1001,81 @@ func TestNilMap(t *testing.T) {
 	mv.SetMapIndex(ValueOf("hi"), Value{})
 }
 
+// TestTypeStrings is a Haxe addition, as type strings are generated by the Haxe compiler.
+func TestTypeStrings(t *testing.T) {
+	testType(t, 1, TypeOf([]byte(nil)), "[]uint8")
+	testType(t, 2, TypeOf(map[rune]interface{}(nil)), "map[int32]interface {}")
+	testType(t, 3, TypeOf(new(bytes.Buffer)), "*bytes.Buffer")
+	testType(t, 4, TypeOf(fmt.Fprintf), "func(io.Writer, string, ...interface {}) (int, error)")
+	testType(t, 5, TypeOf((<-chan error)(nil)), "<-chan error")
+	if p := TypeOf(new(bytes.Buffer)).PkgPath(); p != "" {
+		t.Errorf("(*bytes.Buffer).PkgPath() = %q, want \"\"", p)
+	}
+	if p := TypeOf(bytes.Buffer{}).PkgPath(); p != "bytes" {
+		t.Errorf("bytes.Buffer.PkgPath() = %q, want \"bytes\"", p)
+	}
+	type embed struct {
+		*bytes.Buffer
+		integer `tag:"value"`
+	}
+	f := TypeOf(embed{}).Field(1)
+	if !f.Anonymous || f.Name != "integer" || f.PkgPath != "reflect_test" || f.Tag.Get("tag") != "value" {
+		t.Errorf("embedded field = %+v", f)
+	}
+}
+
+// TestMapPointerEntries is a Haxe addition, as pointers are held directly in a Haxe map.
+func TestMapPointerEntries(t *testing.T) {
+	one := 1
+	m := map[*int]*int{nil: &one, &one: nil}
+	mv := ValueOf(m)
+	if keys := mv.MapKeys(); len(keys) != 2 {
+		t.Errorf("MapKeys() returned %d keys, want 2", len(keys))
+	}
+	if v := mv.MapIndex(ValueOf((*int)(nil))); !v.IsValid() || v.Elem().Int() != 1 {
+		t.Errorf("MapIndex(nil) = %v, want &1", v)
+	}
+	if v := mv.MapIndex(ValueOf(&one)); !v.IsValid() || !v.IsNil() {
+		t.Errorf("MapIndex(&one) = %v, want nil", v)
+	}
+	mv.SetMapIndex(ValueOf((*int)(nil)), Value{})
+	if _, ok := m[nil]; ok || len(m) != 1 {
+		t.Errorf("after SetMapIndex(nil, Value{}), len(m) = %d, m[nil] present = %v", len(m), ok)
+	}
+}
+
+// TestMapKeysDelete is a Haxe addition, checking that MapKeys and deletion via SetMapIndex agree with ranging over the map.
+func TestMapKeysDelete(t *testing.T) {
+	m := map[string]int{"a": 1, "b": 2, "c": 3, "d": 4}
+	mv := ValueOf(m)
+	seen := map[string]bool{}
+	for _, k := range mv.MapKeys() {
+		if seen[k.String()] {
+			t.Errorf("MapKeys() returned %q twice", k.String())
+		}
+		seen[k.String()] = true
+		if k.String() == "b" || k.String() == "d" {
+			mv.SetMapIndex(k, Value{})
+		}
+	}
+	if len(seen) != 4 {
+		t.Errorf("MapKeys() returned %d distinct keys, want 4", len(seen))
+	}
+	n := 0
+	for k, v := range m {
+		if k == "b" || k == "d" || m[k] != v {
+			t.Errorf("after deletion, ranging over the map gave %q:%d", k, v)
+		}
+		n++
+	}
+	if n != 2 || mv.Len() != 2 || len(mv.MapKeys()) != 2 {
+		t.Errorf("after deletion, range gave %d entries, Len() = %d, len(MapKeys()) = %d, want 2", n, mv.Len(), len(mv.MapKeys()))
+	}
+	if v := mv.MapIndex(ValueOf("b")); v.IsValid() {
+		t.Errorf("MapIndex(deleted key) = %v, want the zero Value", v)
+	}
+}
+
 func TestZChan(t *testing.T) {
 	for loop := 0; loop < 2; loop++ {
 		var c chan int
@@ -3632,6 +3705,53 @@ func TestMapOfGCValues(t *testing.T) {
 	}
 }
 
+// TestFuncOf is from Go 1.5, FuncOf is a Haxe addition, exported only for the tests.
+func TestFuncOf(t *testing.T) {
+	// check construction and use of type not in binary
+	type K string
+	type V float64
+
+	fn := func(args []Value) []Value {
+		if len(args) != 1 {
+			t.Errorf("args == %v, want exactly one arg", args)
+		} else if args[0].Type() != TypeOf(K("")) {
+			t.Errorf("args[0] is type %v, want %v", args[0].Type(), TypeOf(K("")))
+		} else if args[0].String() != "gopher" {
+			t.Errorf("args[0] = %q, want %q", args[0].String(), "gopher")
+		}
+		return []Value{ValueOf(V(3.14))}
+	}
+	v := MakeFunc(FuncOf([]Type{TypeOf(K(""))}, []Type{TypeOf(V(0))}, false), fn)
+
+	outs := v.Call([]Value{ValueOf(K("gopher"))})
+	if len(outs) != 1 {
+		t.Fatalf("v.Call returned %v, want exactly one result", outs)
+	} else if outs[0].Type() != TypeOf(V(0)) {
+		t.Fatalf("c.Call[0] is type %v, want %v", outs[0].Type(), TypeOf(V(0)))
+	}
+	f := outs[0].Float()
+	if f != 3.14 {
+		t.Errorf("constructed func returned %f, want %f", f, 3.14)
+	}
+
+	// check that types already in binary are found
+	type T1 int
+	testCases := []struct {
+		in, out  []Type
+		variadic bool
+		want     interface{}
+	}{
+		{in: []Type{TypeOf(T1(0))}, want: (func(T1))(nil)},
+		{in: []Type{TypeOf(int(0))}, want: (func(int))(nil)},
+		{in: []Type{SliceOf(TypeOf(int(0)))}, variadic: true, want: (func(...int))(nil)},
+		{in: []Type{TypeOf(int(0))}, out: []Type{TypeOf(false)}, want: (func(int) bool)(nil)},
+		{in: []Type{TypeOf(int(0))}, out: []Type{TypeOf(false), TypeOf("")}, want: (func(int) (bool, string))(nil)},
+	}
+	for _, tt := range testCases {
+		checkSameType(t, Zero(FuncOf(tt.in, tt.out, tt.variadic)).Interface(), tt.want)
+	}
+}
+
 type B1 struct {
 	X int
 	Y int
@@ -16,6 +16,7 @@ func IsRO(v Value) bool {
 }
 
 var ArrayOf = arrayOf
+var FuncOf = funcOf
 var CallGC = &callGC
 
 const PtrSize = ptrSize
@@ -126,12 +126,7 @@ func haxe2go(ret *emptyInterface, i interface{}) {
 }
 
 func typeIdFromPtr(ptr *rtype) int {
-	for typ := 0; typ < len(haxegoruntime.TypeTable); typ++ {
-		if unsafe.Pointer(ptr) == unsafe.Pointer(haxegoruntime.TypeTable[typ]) {
-			return typ
-		}
-	}
-	return 0
+	return haxegoruntime.TypeID(unsafe.Pointer(ptr))
 }
 func haxeInterfacePack(ei *emptyInterface) interface{} {
 	i := haxeInterfacePackB(ei)
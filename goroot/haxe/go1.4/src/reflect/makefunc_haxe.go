@@ -43,7 +43,6 @@ type makeFuncImpl struct {
 // of how to use MakeFunc to build a swap function for different types.
 //
 func MakeFunc(typ Type, fn func(args []Value) (results []Value)) Value {
-	panic("reflect.MakeFunc() not yet implemented in Haxe")
 	if typ.Kind() != Func {
 		panic("reflect: call of MakeFunc with non-Func type")
 	}
@@ -51,26 +50,33 @@ func MakeFunc(typ Type, fn func(args []Value) (results []Value)) Value {
 	t := typ.common()
 	ftyp := (*funcType)(unsafe.Pointer(t))
 
-	// Indirect Go func value (dummy) to obtain
-	// actual code address. (A Go func value is a pointer
-	// to a C function pointer. http://golang.org/s/go11func.)
-	dummy := makeFuncStub
-	code := **(**uintptr)(unsafe.Pointer(&dummy))
-
 	// makeFuncImpl contains a stack map for use by the runtime
 	_, _, _, stack := funcLayout(t, nil)
 
-	impl := &makeFuncImpl{code: code, stack: stack, typ: ftyp, fn: fn}
+	impl := &makeFuncImpl{stack: stack, typ: ftyp, fn: fn}
+
+	// In Haxe, the code half is a Closure with impl as its only bound variable,
+	// as the first word of impl it is what a call of the func value loads.
+	impl.code = haxeStubClosure(makeFuncStub, unsafe.Pointer(impl))
 
 	return Value{t, unsafe.Pointer(impl), flag(Func)}
 }
 
-// makeFuncStub is an assembly function that is the code half of
-// the function returned from MakeFunc. It expects a *callReflectFunc
-// as its context register, and its job is to invoke callReflect(ctxt, frame)
-// where ctxt is the context register and frame is a pointer to the first
-// word in the passed-in argument frame.
-func makeFuncStub()
+// haxeStubClosure returns a Haxe Closure which takes any number of parameters and calls stub with
+// ctxt and the Haxe parameter array, which starts with the goroutine number and the bound variables.
+func haxeStubClosure(stub interface{}, ctxt unsafe.Pointer) uintptr {
+	return hx.CodeDynamic("",
+		"var _stub:Closure=_a.param(0).val;"+
+			"new Closure(Reflect.makeVarArgs(function(_p:Array<Dynamic>):Dynamic{"+
+			"return Closure.callFn(_stub,[_p[0],[],_p[1][0],_p]);}),[_a.param(1).val]);",
+		stub, ctxt)
+}
+
+// makeFuncStub is the Go half of the function returned from MakeFunc,
+// it is called with the Haxe parameters by the Closure from haxeStubClosure.
+func makeFuncStub(ctxt *makeFuncImpl, frame uintptr) uintptr {
+	return callReflect(ctxt, frame)
+}
 
 type methodValue struct {
 	fn     uintptr
@@ -91,6 +97,10 @@ func makeMethodValue(op string, v Value) Value {
 		panic("reflect: internal error: invalid use of makeMethodValue")
 	}
 
+	if v.typ.Kind() == Interface { // Haxe: use the method of the value held
+		v = interfaceMethod(op, v, int(v.flag)>>flagMethodShift)
+	}
+
 	// Ignoring the flagMethod bit, v describes the receiver, not the method type.
 	fl := v.flag & (flagRO | flagAddr | flagIndir)
 	fl |= flag(v.typ.Kind())
@@ -99,22 +109,15 @@ func makeMethodValue(op string, v Value) Value {
 	// v.Type returns the actual type of the method value.
 	funcType := v.Type().(*rtype)
 
-	// Indirect Go func value (dummy) to obtain
-	// actual code address. (A Go func value is a pointer
-	// to a C function pointer. http://golang.org/s/go11func.)
-	//dummy := methodValueCall
-	//code := **(**uintptr)(unsafe.Pointer(&dummy))
-	code := hx.GetDynamic("", "new Closure(Go_reflect_methodVValueCCall.call,[])")
-
 	// methodValue contains a stack map for use by the runtime
 	_, _, _, stack := funcLayout(funcType, nil)
 
 	fv := &methodValue{
-		fn:     code,
 		stack:  stack,
 		method: int(v.flag) >> flagMethodShift,
 		rcvr:   rcvr,
 	}
+	fv.fn = haxeStubClosure(methodValueCall, unsafe.Pointer(fv)) // see MakeFunc
 
 	// Cause panic if method is not appropriate.
 	// The panic would still happen during the call if we omit this,
@@ -124,11 +127,8 @@ func makeMethodValue(op string, v Value) Value {
 	return Value{funcType, unsafe.Pointer(fv), v.flag&flagRO | flag(Func)}
 }
 
-// methodValueCall is an assembly function that is the code half of
-// the function returned from makeMethodValue. It expects a *methodValue
-// as its context register, and its job is to invoke callMethod(ctxt, frame)
-// where ctxt is the context register and frame is a pointer to the first
-// word in the passed-in argument frame.
-func methodValueCall() {
-	panic("reflect.methodValueCall() not yet implemented in Haxe")
+// methodValueCall is the Go half of the function returned from makeMethodValue,
+// it is called with the Haxe parameters by the Closure from haxeStubClosure.
+func methodValueCall(ctxt *methodValue, frame uintptr) uintptr {
+	return callMethod(ctxt, frame)
 }
@@ -1364,7 +1364,14 @@ func haveIdenticalUnderlyingType(T, V *rtype) bool {
 // there can be more than one with a given string.
 // Only types we might want to look up are included:
 // channels, maps, slices, and arrays.
-func typelinks() []*rtype { panic("reflect: typelinks not yet implemented in haxe") }
+func typelinks() []*rtype {
+	tl := haxegoruntime.TypeLinks()
+	ret := make([]*rtype, len(tl))
+	for i, p := range tl {
+		ret[i] = (*rtype)(p)
+	}
+	return ret
+}
 
 // typesByString returns the subslice of typelinks() whose elements have
 // the given string representation.
@@ -1456,7 +1463,6 @@ func cachePut(k cacheKey, t *rtype) Type {
 // The gc runtime imposes a limit of 64 kB on channel element types.
 // If t's size is equal to or exceeds this limit, ChanOf panics.
 func ChanOf(dir ChanDir, t Type) Type {
-	panic("reflect.ChanOf not yet implemented")
 	typ := t.(*rtype)
 
 	// Look in cache.
@@ -1493,21 +1499,23 @@ func ChanOf(dir ChanDir, t Type) Type {
 	}
 
 	// Make a channel type.
-	var ichan interface{} = (chan unsafe.Pointer)(nil)
-	prototype := *(**chanType)(unsafe.Pointer(&ichan))
+	var ichan = haxeInterfaceUnpack((chan unsafe.Pointer)(nil)) //var ichan interface{} = (chan unsafe.Pointer)(nil)
+	prototype := (*chanType)(unsafe.Pointer(ichan.typ))         //prototype := *(**chanType)(unsafe.Pointer(&ichan))
 	ch := new(chanType)
 	*ch = *prototype
 	ch.string = &s
 	ch.hash = fnv1(typ.hash, 'c', byte(dir))
 	ch.elem = typ
+	ch.dir = uintptr(dir)
 	ch.uncommonType = nil
 	ch.ptrToThis = nil
 	ch.zero = unsafe.Pointer(&make([]byte, ch.size)[0])
 
+	haxegoruntime.AddHaxeType(unsafe.Pointer(&ch.rtype))
 	return cachePut(ckey, &ch.rtype)
 }
 
-func ismapkey(*rtype) bool { panic("reflect.ismapkey() not yet implemented in haxe") } // implemented in runtime
+func ismapkey(t *rtype) bool { return haxegoruntime.Comparable(unsafe.Pointer(t)) } // implemented in haxegoruntime
 
 // MapOf returns the map type with the given key and element types.
 // For example, if k represents int and e represents string,
@@ -1516,8 +1524,6 @@ func ismapkey(*rtype) bool { panic("reflect.ismapkey() not yet implemented in ha
 // If the key type is not a valid map key type (that is, if it does
 // not implement Go's == operator), MapOf panics.
 func MapOf(key, elem Type) Type {
-	panic("reflect.MapOf not yet implemented")
-
 	ktyp := key.(*rtype)
 	etyp := elem.(*rtype)
 
@@ -1541,34 +1547,20 @@ func MapOf(key, elem Type) Type {
 	}
 
 	// Make a map type.
-	var imap interface{} = (map[unsafe.Pointer]unsafe.Pointer)(nil)
-	prototype := *(**mapType)(unsafe.Pointer(&imap))
+	var imap = haxeInterfaceUnpack((map[unsafe.Pointer]unsafe.Pointer)(nil)) //var imap interface{} = (map[unsafe.Pointer]unsafe.Pointer)(nil)
+	prototype := (*mapType)(unsafe.Pointer(imap.typ))                         //prototype := *(**mapType)(unsafe.Pointer(&imap))
 	mt := new(mapType)
 	*mt = *prototype
 	mt.string = &s
 	mt.hash = fnv1(etyp.hash, 'm', byte(ktyp.hash>>24), byte(ktyp.hash>>16), byte(ktyp.hash>>8), byte(ktyp.hash))
 	mt.key = ktyp
 	mt.elem = etyp
-	mt.bucket = bucketOf(ktyp, etyp)
-	if ktyp.size > maxKeySize {
-		mt.keysize = uint8(ptrSize)
-		mt.indirectkey = 1
-	} else {
-		mt.keysize = uint8(ktyp.size)
-		mt.indirectkey = 0
-	}
-	if etyp.size > maxValSize {
-		mt.valuesize = uint8(ptrSize)
-		mt.indirectvalue = 1
-	} else {
-		mt.valuesize = uint8(etyp.size)
-		mt.indirectvalue = 0
-	}
-	mt.bucketsize = uint16(mt.bucket.size)
+	// Haxe maps are GOmap objects, so there is no bucket structure to describe.
 	mt.uncommonType = nil
 	mt.ptrToThis = nil
 	mt.zero = unsafe.Pointer(&make([]byte, mt.size)[0])
 
+	haxegoruntime.AddHaxeType(unsafe.Pointer(&mt.rtype))
 	return cachePut(ckey, &mt.rtype)
 }
 
@@ -1724,8 +1716,6 @@ func bucketOf(ktyp, etyp *rtype) *rtype {
 // SliceOf returns the slice type with element type t.
 // For example, if t represents int, SliceOf(t) represents []int.
 func SliceOf(t Type) Type {
-	panic("reflect.SliceOf not yet implemented")
-
 	typ := t.(*rtype)
 
 	// Look in cache.
@@ -1744,8 +1734,8 @@ func SliceOf(t Type) Type {
 	}
 
 	// Make a slice type.
-	var islice interface{} = ([]unsafe.Pointer)(nil)
-	prototype := *(**sliceType)(unsafe.Pointer(&islice))
+	var islice = haxeInterfaceUnpack(([]unsafe.Pointer)(nil)) //var islice interface{} = ([]unsafe.Pointer)(nil)
+	prototype := (*sliceType)(unsafe.Pointer(islice.typ))     //prototype := *(**sliceType)(unsafe.Pointer(&islice))
 	slice := new(sliceType)
 	*slice = *prototype
 	slice.string = &s
@@ -1755,9 +1745,134 @@ func SliceOf(t Type) Type {
 	slice.ptrToThis = nil
 	slice.zero = unsafe.Pointer(&make([]byte, slice.size)[0])
 
+	haxegoruntime.AddHaxeType(unsafe.Pointer(&slice.rtype))
 	return cachePut(ckey, &slice.rtype)
 }
 
+// funcOf returns the function type with the given argument and result types.
+// For example if k represents int and e represents string,
+// funcOf([]Type{k}, []Type{e}, false) represents func(int) string.
+//
+// The variadic argument controls whether the function is variadic. funcOf
+// panics if the in[len(in)-1] does not represent a slice and variadic is
+// true.
+//
+// NOTE: this is a Haxe addition, the FuncOf of Go 1.5, so it is not exported
+// from this Go 1.4 reflect; it is used by the tests, as arrayOf is.
+func funcOf(in, out []Type, variadic bool) Type {
+	if variadic && (len(in) == 0 || in[len(in)-1].Kind() != Slice) {
+		panic("reflect.FuncOf: last arg of variadic func must be slice")
+	}
+
+	// Build the string, and the hash from the argument and result types.
+	var hash uint32
+	s := "func("
+	for i, t := range in {
+		if i > 0 {
+			s += ", "
+		}
+		if variadic && i == len(in)-1 {
+			s += "..." + *t.Elem().common().string
+		} else {
+			s += *t.common().string
+		}
+		hash = fnv1(hash, byte(t.common().hash>>24), byte(t.common().hash>>16), byte(t.common().hash>>8), byte(t.common().hash))
+	}
+	if variadic {
+		hash = fnv1(hash, 'v')
+	}
+	hash = fnv1(hash, '.')
+	s += ")"
+	if len(out) == 1 {
+		s += " " + *out[0].common().string
+	} else if len(out) > 1 {
+		s += " ("
+		for i, t := range out {
+			if i > 0 {
+				s += ", "
+			}
+			s += *t.common().string
+		}
+		s += ")"
+	}
+	for _, t := range out {
+		hash = fnv1(hash, byte(t.common().hash>>24), byte(t.common().hash>>16), byte(t.common().hash>>8), byte(t.common().hash))
+	}
+
+	// Look in cache, there is no single cacheKey for a function type, so use the hash and check the candidates.
+	ckey := cacheKey{Func, nil, nil, uintptr(hash)}
+	lookupCache.RLock()
+	for _, tt := range funcLookupCache[ckey] {
+		if haveIdenticalFuncSignature(tt, in, out, variadic) {
+			lookupCache.RUnlock()
+			return &tt.rtype
+		}
+	}
+	lookupCache.RUnlock()
+
+	// Look in known types.
+	for _, tt := range typesByString(s) {
+		ft := (*funcType)(unsafe.Pointer(tt))
+		if haveIdenticalFuncSignature(ft, in, out, variadic) {
+			return funcCachePut(ckey, ft)
+		}
+	}
+
+	// Make a func type.
+	var ifunc = haxeInterfaceUnpack((func())(nil)) //var ifunc interface{} = (func())(nil)
+	prototype := (*funcType)(unsafe.Pointer(ifunc.typ))
+	ft := new(funcType)
+	*ft = *prototype
+	ft.string = &s
+	ft.hash = hash
+	ft.dotdotdot = variadic
+	ft.in = make([]*rtype, len(in))
+	for i, t := range in {
+		ft.in[i] = t.common()
+	}
+	ft.out = make([]*rtype, len(out))
+	for i, t := range out {
+		ft.out[i] = t.common()
+	}
+	ft.uncommonType = nil
+	ft.ptrToThis = nil
+	ft.zero = unsafe.Pointer(&make([]byte, ft.size)[0])
+
+	haxegoruntime.AddHaxeType(unsafe.Pointer(&ft.rtype))
+	return funcCachePut(ckey, ft)
+}
+
+// funcLookupCache holds the function types found or made by funcOf, by hash, protected by lookupCache.
+var funcLookupCache map[cacheKey][]*funcType
+
+func funcCachePut(k cacheKey, ft *funcType) Type {
+	lookupCache.Lock()
+	if funcLookupCache == nil {
+		funcLookupCache = make(map[cacheKey][]*funcType)
+	}
+	funcLookupCache[k] = append(funcLookupCache[k], ft)
+	lookupCache.Unlock()
+	return &ft.rtype
+}
+
+// haveIdenticalFuncSignature returns true if ft has the given argument and result types.
+func haveIdenticalFuncSignature(ft *funcType, in, out []Type, variadic bool) bool {
+	if ft.dotdotdot != variadic || len(ft.in) != len(in) || len(ft.out) != len(out) {
+		return false
+	}
+	for i, t := range in {
+		if ft.in[i] != t.common() {
+			return false
+		}
+	}
+	for i, t := range out {
+		if ft.out[i] != t.common() {
+			return false
+		}
+	}
+	return true
+}
+
 // ArrayOf returns the array type with the given count and element type.
 // For example, if t represents int, ArrayOf(5, t) represents [5]int.
 //
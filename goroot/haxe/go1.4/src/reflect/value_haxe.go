@@ -339,6 +339,9 @@ func (v Value) call(op string, in []Value) []Value {
 		rcvrtype *rtype
 	)
 	if v.flag&flagMethod != 0 {
+		if v.typ.Kind() == Interface { // Haxe: call the method of the value held
+			v = interfaceMethod(op, v, int(v.flag)>>flagMethodShift)
+		}
 		rcvr = v
 		rcvrtype, t, fn = methodReceiver(op, v, int(v.flag)>>flagMethodShift)
 	} else if v.flag&flagIndir != 0 {
@@ -596,33 +599,17 @@ func (v Value) call(op string, in []Value) []Value {
 // NOTE: This function must be marked as a "wrapper" in the generated code,
 // so that the linker can make it work correctly for panic and recover.
 // The gc compilers know to do that for the name "reflect.callReflect".
-func callReflect(ctxt *makeFuncImpl, frame unsafe.Pointer) {
-	panic("reflect.callReflect not yet implemented")
+//
+// In Haxe, frame is the array of parameters passed to the Closure, which starts with
+// the goroutine number and the bound variables; the result is the Haxe value returned.
+func callReflect(ctxt *makeFuncImpl, frame uintptr) uintptr {
 	ftyp := ctxt.typ
 	f := ctxt.fn
 
-	// Copy argument frame into Values.
-	ptr := frame
-	off := uintptr(0)
+	// Copy Haxe parameters into Values.
 	in := make([]Value, 0, len(ftyp.in))
-	for _, arg := range ftyp.in {
-		typ := arg
-		off += -off & uintptr(typ.align-1)
-		addr := unsafe.Pointer(uintptr(ptr) + off)
-		v := Value{typ, nil, flag(typ.Kind())}
-		if ifaceIndir(typ) {
-			// value cannot be inlined in interface data.
-			// Must make a copy, because f might keep a reference to it,
-			// and we cannot let f keep a reference to the stack frame
-			// after this function returns, not even a read-only reference.
-			v.ptr = unsafe_New(typ)
-			memmove(v.ptr, addr, typ.size)
-			v.flag |= flagIndir
-		} else {
-			v.ptr = *(*unsafe.Pointer)(addr)
-		}
-		in = append(in, v)
-		off += typ.size
+	for i, arg := range ftyp.in {
+		in = append(in, haxeParamValue(arg, frame, i+2))
 	}
 
 	// Call underlying function.
@@ -631,34 +618,56 @@ func callReflect(ctxt *makeFuncImpl, frame unsafe.Pointer) {
 		panic("reflect: wrong return count from function created by MakeFunc")
 	}
 
-	// Copy results back into argument frame.
-	if len(ftyp.out) > 0 {
-		off += -off & (ptrSize - 1)
-		if runtime.GOARCH == "amd64p32" {
-			off = align(off, 8)
+	// Check the results, before they are returned as Haxe values.
+	for i, typ := range ftyp.out {
+		v := out[i]
+		if v.typ != typ {
+			panic("reflect: function created by MakeFunc using " + funcName(f) +
+				" returned wrong type: have " +
+				out[i].typ.String() + " for " + typ.String())
 		}
-		for i, arg := range ftyp.out {
-			typ := arg
-			v := out[i]
-			if v.typ != typ {
-				panic("reflect: function created by MakeFunc using " + funcName(f) +
-					" returned wrong type: have " +
-					out[i].typ.String() + " for " + typ.String())
-			}
-			if v.flag&flagRO != 0 {
-				panic("reflect: function created by MakeFunc using " + funcName(f) +
-					" returned value obtained from unexported field")
-			}
-			off += -off & uintptr(typ.align-1)
-			addr := unsafe.Pointer(uintptr(ptr) + off)
-			if v.flag&flagIndir != 0 {
-				memmove(addr, v.ptr, typ.size)
-			} else {
-				*(*unsafe.Pointer)(addr) = v.ptr
-			}
-			off += typ.size
+		if v.flag&flagRO != 0 {
+			panic("reflect: function created by MakeFunc using " + funcName(f) +
+				" returned value obtained from unexported field")
 		}
 	}
+	return haxeResults(ftyp.out, out)
+}
+
+// haxeParamValue returns the Value of the Haxe parameter at index idx in frame, which has type typ.
+func haxeParamValue(typ *rtype, frame uintptr, idx int) Value {
+	ei := &emptyInterface{typ: typ}
+	haxe2go(ei, hx.CodeDynamic("", "_a.param(0).val[_a.param(1).val];", frame, idx))
+	fl := flag(typ.Kind())
+	if ifaceIndir(typ) {
+		fl |= flagIndir
+	}
+	return Value{typ, ei.word, fl} // as in unpackEface()
+}
+
+// haxeResults returns the Values in out as the Haxe result of a function,
+// a single value, or an object with fields r0, r1... if there is more than one.
+func haxeResults(types []*rtype, out []Value) uintptr {
+	vals := hx.CodeDynamic("", "new Array<Dynamic>();")
+	for i, typ := range types {
+		v := out[i].assignTo("reflect.MakeFunc", typ, nil)
+		if typ.Kind() == Interface { // don't take just the value
+			hx.Code("", "_a.param(0).val.push(_a.param(1));", vals, valueInterface(v, false))
+		} else {
+			hx.Code("", "_a.param(0).val.push(_a.param(1).val);", vals, valueInterface(v, false))
+		}
+	}
+	switch len(types) {
+	case 0:
+		return hx.Null()
+	case 1:
+		return hx.CodeDynamic("", "_a.param(0).val[0];", vals)
+	}
+	return hx.CodeDynamic("",
+		"var _r:Dynamic={};"+
+			"for(_i in 0..._a.param(0).val.length) Reflect.setField(_r,'r'+Std.string(_i),_a.param(0).val[_i]);"+
+			"_r;",
+		vals)
 }
 
 // methodReceiver returns information about the receiver
@@ -671,22 +680,9 @@ func callReflect(ctxt *makeFuncImpl, frame unsafe.Pointer) {
 func methodReceiver(op string, v Value, methodIndex int) (rcvrtype, t *rtype, fn unsafe.Pointer) {
 	i := methodIndex
 	if v.typ.Kind() == Interface {
-		panic("reflect.methodReciever unhandled *interface ")
-		tt := (*interfaceType)(unsafe.Pointer(v.typ))
-		if uint(i) >= uint(len(tt.methods)) {
-			panic("reflect: internal error: invalid method index")
-		}
-		m := &tt.methods[i]
-		if m.pkgPath != nil {
-			panic("reflect: " + op + " of unexported method")
-		}
-		iface := (*nonEmptyInterface)(v.ptr)
-		if iface.itab == nil {
-			panic("reflect: " + op + " of method on nil interface value")
-		}
-		rcvrtype = iface.itab.typ
-		fn = unsafe.Pointer(&iface.itab.fun[i])
-		t = m.typ
+		// There is no itab in Haxe, so use the method of the value held.
+		mv := interfaceMethod(op, v, i)
+		return methodReceiver(op, mv, int(mv.flag)>>flagMethodShift)
 	} else {
 		rcvrtype = v.typ
 		ut := v.typ.uncommon()
@@ -703,6 +699,28 @@ func methodReceiver(op string, v Value, methodIndex int) (rcvrtype, t *rtype, fn
 	return
 }
 
+// interfaceMethod returns the method value for the methodIndex'th method of the interface v,
+// taken from the value held in v.
+func interfaceMethod(op string, v Value, methodIndex int) Value {
+	tt := (*interfaceType)(unsafe.Pointer(v.typ))
+	if uint(methodIndex) >= uint(len(tt.methods)) {
+		panic("reflect: internal error: invalid method index")
+	}
+	m := &tt.methods[methodIndex]
+	if m.pkgPath != nil {
+		panic("reflect: " + op + " of unexported method")
+	}
+	iv := Value{v.typ, v.ptr, v.flag&(flagRO|flagIndir|flagAddr) | flag(Interface)}
+	if iv.IsNil() {
+		panic("reflect: " + op + " of method on nil interface value")
+	}
+	mv := iv.Elem().MethodByName(*m.name)
+	if !mv.IsValid() {
+		panic("reflect: internal error: method " + *m.name + " not found for " + op)
+	}
+	return mv
+}
+
 // v is a method receiver.  Store at p the word which is used to
 // encode that receiver at the start of the argument list.
 // Reflect uses the "interface" calling convention for
@@ -737,33 +755,27 @@ func align(x, n uintptr) uintptr {
 // NOTE: This function must be marked as a "wrapper" in the generated code,
 // so that the linker can make it work correctly for panic and recover.
 // The gc compilers know to do that for the name "reflect.callMethod".
-func callMethod(ctxt *methodValue, frame unsafe.Pointer) {
-	panic("reflect.callMethod not yet implemented")
+//
+// In Haxe, frame and the result are as for callReflect.
+func callMethod(ctxt *methodValue, frame uintptr) uintptr {
 	rcvr := ctxt.rcvr
-	rcvrtype, t, fn := methodReceiver("call", rcvr, ctxt.method)
-	frametype, argSize, retOffset, _ := funcLayout(t, rcvrtype)
-
-	// Make a new frame that is one word bigger so we can store the receiver.
-	args := unsafe_New(frametype)
-
-	// Copy in receiver and rest of args.
-	storeRcvr(rcvr, args)
-	memmove(unsafe.Pointer(uintptr(args)+ptrSize), frame, argSize-ptrSize)
+	_, t, _ := methodReceiver("call", rcvr, ctxt.method)
+	ftyp := (*funcType)(unsafe.Pointer(t))
 
-	// Call.
-	call(fn, args, uint32(frametype.size), uint32(retOffset))
-
-	// Copy return values. On amd64p32, the beginning of return values
-	// is 64-bit aligned, so the caller's frame layout (which doesn't have
-	// a receiver) is different from the layout of the fn call, which has
-	// a receiver.
-	// Ignore any changes to args and just copy return values.
-	callerRetOffset := retOffset - ptrSize
-	if runtime.GOARCH == "amd64p32" {
-		callerRetOffset = align(argSize-ptrSize, 8)
+	// Copy Haxe parameters into Values, the variadic slice (if any) is the last of them.
+	in := make([]Value, 0, len(ftyp.in))
+	for i, arg := range ftyp.in {
+		in = append(in, haxeParamValue(arg, frame, i+2))
+	}
+	op := "Call"
+	if ftyp.dotdotdot {
+		op = "CallSlice"
 	}
-	memmove(unsafe.Pointer(uintptr(frame)+callerRetOffset),
-		unsafe.Pointer(uintptr(args)+retOffset), frametype.size-retOffset)
+
+	// Call, via the method of rcvr.
+	fl := rcvr.flag&(flagRO|flagIndir) | flag(Func) | flag(ctxt.method)<<flagMethodShift | flagMethod
+	out := Value{rcvr.typ, rcvr.ptr, fl}.call(op, in)
+	return haxeResults(ftyp.out, out)
 }
 
 // funcName returns the name of f, for use in error messages.
@@ -799,7 +811,6 @@ func (v Value) Cap() int {
 // Close closes the channel v.
 // It panics if v's Kind is not Chan.
 func (v Value) Close() {
-	panic("reflect.Close not yet implemented")
 	v.mustBe(Chan)
 	v.mustBeExported()
 	chanclose(v.pointer())
@@ -1171,7 +1182,6 @@ func (v Value) Len() int {
 		tt := (*arrayType)(unsafe.Pointer(v.typ))
 		return int(tt.len)
 	case Chan:
-		panic("reflect.value.Len chan not yet implemented")
 		return chanlen(v.pointer())
 	case Map:
 		//panic("reflect.value.Len map not yet implemented")
@@ -1464,7 +1474,6 @@ func (v Value) Pointer() uintptr {
 // The boolean value ok is true if the value x corresponds to a send
 // on the channel, false if it is a zero value received because the channel is closed.
 func (v Value) Recv() (x Value, ok bool) {
-	panic("reflect.value.Recv not yet implemented")
 	v.mustBe(Chan)
 	v.mustBeExported()
 	return v.recv(false)
@@ -1644,16 +1653,24 @@ func (v Value) SetLen(n int) {
 // It panics if v's Kind is not Slice or if n is smaller than the length or
 // greater than the capacity of the slice.
 func (v Value) SetCap(n int) {
-	panic("reflect.value.SetCap not yet implemented")
 	v.mustBeAssignable()
 	v.mustBe(Slice)
 	//s := (*sliceHeader)(v.ptr)
 	//if n < int(s.Len) || n > int(s.Cap) {
-	if n < hx.CodeInt("", "_a.param(0).val.load().len();", v.ptr) ||
-		n > hx.CodeInt("", "_a.param(0).val.load().cap();", v.ptr) {
+	base, cap := sliceBase(v.ptr)
+	l := hx.CodeInt("", "var s=_a.param(0).val.load();s==null?0:s.len();", v.ptr)
+	if n < l || n > cap {
 		panic("reflect: slice capacity out of range in SetCap")
 	}
-	panic("reflect.value.SetLEN - not sure how to do: s.Cap = n") // TODO
+	//s.Cap = n
+	if base == nil {
+		return // n must be 0, as is the capacity of a nil slice
+	}
+	hx.Code("",
+		"_a.param(0).val.store(new Slice("+
+			"_a.param(1).val,0,_a.param(2).val,"+
+			"_a.param(3).val,_a.param(4).val));",
+		v.ptr, base, l, n, sliceElemSize((*sliceType)(unsafe.Pointer(v.typ))))
 }
 
 // SetMapIndex sets the value associated with key in the map v to val.
@@ -1757,12 +1774,7 @@ func (v Value) Slice(i, j int) Value {
 	case Slice:
 		typ = (*sliceType)(unsafe.Pointer(v.typ))
 		//s := (*sliceHeader)(v.ptr)
-		base = unsafe.Pointer(hx.CodeDynamic("", "_a.param(0).val.load().itemAddr(0);", v.ptr)) //unsafe.Pointer(s.Data)
-		if base == nil {
-			cap = 0
-		} else {
-			cap = hx.CodeInt("", "_a.param(0).val.load().cap();", v.ptr) // s.Cap
-		}
+		base, cap = sliceBase(v.ptr) // s.Data, s.Cap
 
 	case String:
 		s := *(*string)(v.ptr)            //(*stringHeader)(v.ptr)
@@ -1794,16 +1806,11 @@ func (v Value) Slice(i, j int) Value {
 	*/
 
 	//Haxe:	public function new(fromArray:Pointer, low:Int, high:Int, ularraysz:Int, isz:Int) {
-	elemSize := uint32(typ.Elem().Size())
-	elemAlign := uint32(typ.Elem().Align()) // TODO should this be field align?
-	for (elemSize % elemAlign) != 0 {
-		elemSize++ // make sure we have the correct size for the elements
-	}
 	hx.Code("",
 		"_a.param(0).val.store(new Slice("+
 			"_a.param(1).val,_a.param(2).val,_a.param(3).val,"+
 			"_a.param(4).val,_a.param(5).val));",
-		&x, base, i, j, cap, elemSize)
+		&x, base, i, j, cap, sliceElemSize(typ))
 
 	fl := v.flag&flagRO | flagIndir | flag(Slice)
 	return Value{typ.common(), unsafe.Pointer(&x), fl}
@@ -1813,7 +1820,6 @@ func (v Value) Slice(i, j int) Value {
 // It panics if v's Kind is not Array or Slice, or if v is an unaddressable array,
 // or if the indexes are out of bounds.
 func (v Value) Slice3(i, j, k int) Value {
-	panic("reflect.value.Slice3 not yet implemented")
 	var (
 		cap  int
 		typ  *sliceType
@@ -1837,12 +1843,7 @@ func (v Value) Slice3(i, j, k int) Value {
 		//s := (*sliceHeader)(v.ptr)
 		//base = s.Data
 		//cap = s.Cap
-		base = unsafe.Pointer(hx.CodeDynamic("", "_a.param(0).val.load().itemAddr(0);", v.ptr)) //unsafe.Pointer(s.Data)
-		if base == nil {
-			cap = 0
-		} else {
-			cap = hx.CodeInt("", "_a.param(0).val.load().cap();", v.ptr) // s.Cap
-		}
+		base, cap = sliceBase(v.ptr)
 	}
 
 	if i < 0 || j < i || k < j || k > cap {
@@ -1870,12 +1871,35 @@ func (v Value) Slice3(i, j, k int) Value {
 		"_a.param(0).val.store(new Slice("+
 			"_a.param(1).val,_a.param(2).val,_a.param(3).val,"+
 			"_a.param(4).val,_a.param(5).val));",
-		&x, base, i, j, k-i, typ.Elem().Size())
+		&x, base, i, j, k, sliceElemSize(typ)) // the capacity of the new Slice is k-i
 
 	fl := v.flag&flagRO | flagIndir | flag(Slice)
 	return Value{typ.common(), unsafe.Pointer(&x), fl}
 }
 
+// sliceBase returns the address of the first item of the Haxe Slice at p and its capacity,
+// or nil and 0 for a nil slice.
+// Unlike Slice.itemAddr(0), this does not fail when the length of the Slice is 0.
+func sliceBase(p unsafe.Pointer) (base unsafe.Pointer, cap int) {
+	base = unsafe.Pointer(hx.CodeDynamic("",
+		"var s=_a.param(0).val.load();"+
+			"(s==null||s.baseArray==null)?null:new Pointer(s.baseArray.obj,s.baseArray.off+s.itemOff(0));", p))
+	if base == nil {
+		return nil, 0
+	}
+	return base, hx.CodeInt("", "_a.param(0).val.load().cap();", p)
+}
+
+// sliceElemSize returns the size of each item in a Haxe Slice of type typ, padded to its alignment.
+func sliceElemSize(typ *sliceType) uint32 {
+	elemSize := uint32(typ.Elem().Size())
+	elemAlign := uint32(typ.Elem().Align()) // TODO should this be field align?
+	for (elemSize % elemAlign) != 0 {
+		elemSize++ // make sure we have the correct size for the elements
+	}
+	return elemSize
+}
+
 // String returns the string v's underlying value, as a string.
 // String is a special case because of Go's String method convention.
 // Unlike the other getters, it does not panic if v's Kind is not String.
@@ -1901,7 +1925,6 @@ func (v Value) String() string {
 // If the receive cannot finish without blocking, x is the zero Value and ok is false.
 // If the channel is closed, x is the zero value for the channel's element type and ok is false.
 func (v Value) TryRecv() (x Value, ok bool) {
-	panic("reflect.value.TryRecv not yet implemented")
 	v.mustBe(Chan)
 	v.mustBeExported()
 	return v.recv(true)
@@ -1912,7 +1935,6 @@ func (v Value) TryRecv() (x Value, ok bool) {
 // It returns true if the value was sent, false otherwise.
 // As in Go, x's value must be assignable to the channel's element type.
 func (v Value) TrySend(x Value) bool {
-	panic("reflect.value.TrySend not yet implemented")
 	v.mustBe(Chan)
 	v.mustBeExported()
 	return v.send(x, true)
@@ -1934,7 +1956,6 @@ func (v Value) Type() Type {
 	// v.typ describes the receiver, not the method type.
 	i := int(v.flag) >> flagMethodShift
 	if v.typ.Kind() == Interface {
-		panic("reflect.Value.Type unhandled *interfaceType ?")
 		// Method on interface.
 		tt := (*interfaceType)(unsafe.Pointer(v.typ))
 		if uint(i) >= uint(len(tt.methods)) {
@@ -2167,9 +2188,58 @@ type runtimeSelect struct {
 // The conventional OK bool indicates whether the receive corresponds
 // to a sent value.
 //go:noescape
-func rselect([]runtimeSelect) (chosen int, recvOK bool) {
-	panic("reflect.rselect() not yet implemented in haxe")
-	return 0, false
+func rselect(cases []runtimeSelect) (chosen int, recvOK bool) {
+	n := len(cases)
+	var wake chan bool // sent a value by any change to the channels of the cases, once it is needed
+	for {
+		// as in the generated code for a select statement, the cases are tried in a pseudo-random order
+		start := 0
+		if n > 1 {
+			start = hx.CallInt("", "Std.random", 1, int32(n)) // a Haxe Int, even when int is 64 bits
+		}
+		chosen, recvOK = -1, false
+		dflt := -1
+		for j := 0; j < n && chosen < 0; j++ {
+			i := (j + start) % n
+			rc := &cases[i]
+			switch SelectDir(rc.dir) {
+			case SelectDefault:
+				dflt = i
+			case SelectSend: // ready in the same way as for the generated code, so closed channels panic in chansend
+				c := haxeChan(rc.ch)
+				if chanSendReady(c) || hx.CodeBool("", "Channel.isClosed(_a.param(0).val);", c) {
+					chansend(rc.typ, rc.ch, rc.val, true)
+					chosen = i
+				}
+			case SelectRecv:
+				if hx.CodeBool("", "Channel.hasContents(_a.param(0).val);", haxeChan(rc.ch)) {
+					_, recvOK = chanrecv(rc.typ, rc.ch, true, rc.val)
+					chosen = i
+				}
+			}
+		}
+		if chosen < 0 && dflt >= 0 {
+			chosen = dflt
+		}
+		if chosen >= 0 {
+			if wake != nil {
+				for i := range cases {
+					if SelectDir(cases[i].dir) != SelectDefault {
+						hx.Code("", "Channel.removeWaiter(_a.param(0).val,_a.param(1).val);", haxeChan(cases[i].ch), wake)
+					}
+				}
+			}
+			return
+		}
+		if wake == nil {
+			wake = make(chan bool, 1)
+			for i := range cases {
+				hx.Code("", "Channel.addWaiter(_a.param(0).val,_a.param(1).val);", haxeChan(cases[i].ch), wake)
+			}
+			continue // a case may have become ready before the waiters were added
+		}
+		<-wake // wait in the Scheduler for a change, a select with no cases (or only nil channels) blocks forever
+	}
 }
 
 // A SelectDir describes the communication direction of a select case.
@@ -2215,7 +2285,6 @@ type SelectCase struct {
 // boolean indicating whether the value corresponds to a send on the channel
 // (as opposed to a zero value received because the channel is closed).
 func Select(cases []SelectCase) (chosen int, recv Value, recvOK bool) {
-	panic("reflect.Select not yet implemented")
 	// NOTE: Do not trust that caller is not modifying cases data underfoot.
 	// The range is safe because the caller cannot modify our copy of the len
 	// and each iteration makes its own copy of the value c.
@@ -2756,34 +2825,105 @@ func cvtI2I(v Value, typ Type) Value {
 	return cvtT2I(v.Elem(), typ)
 }
 
+// haxeChan returns the Haxe Channel object (or null) that ch refers to.
+func haxeChan(ch unsafe.Pointer) uintptr {
+	c := (uintptr)(ch)
+	for hx.CodeBool("", "Std.is(_a.param(0).val,Pointer);", c) {
+		c = hx.CodeDynamic("", "_a.param(0).val.load();", c) // go down the pointer chain
+	}
+	return c
+}
+
+// chanElemToHaxe returns the Haxe value, as held in a Channel, of the element of type t at p.
+func chanElemToHaxe(t *rtype, p unsafe.Pointer) uintptr {
+	ei := &emptyInterface{typ: t, word: p}
+	switch t.Kind() {
+	case Ptr, UnsafePointer:
+		ei.word = *(*unsafe.Pointer)(p) // the pointer is the value, see haxeInterfacePack()
+	}
+	return hx.CodeDynamic("", "_a.param(0).val;", haxeInterfacePack(ei))
+}
+
+// chanElemFromHaxe writes the Haxe value v, as held in a Channel, into the element of type t at p.
+func chanElemFromHaxe(t *rtype, v uintptr, p unsafe.Pointer) {
+	ei := &emptyInterface{typ: t}
+	haxe2go(ei, v)
+	switch t.Kind() {
+	case Ptr, UnsafePointer:
+		*(*unsafe.Pointer)(p) = ei.word
+	default:
+		memmove(p, ei.word, t.size)
+	}
+}
+
 // implemented in ../runtime
 func chancap(ch unsafe.Pointer) int {
-	panic("reflect.chancap() not yet implemented in haxe")
-	return 0
+	c := haxeChan(ch)
+	if hx.IsNull(c) {
+		return 0
+	}
+	return hx.CodeInt("", "cast(_a.param(0).val,Channel).cap();", c)
 }
 func chanclose(ch unsafe.Pointer) {
-	panic("reflect.chanclose() not yet implemented in haxe")
+	c := haxeChan(ch)
+	if hx.IsNull(c) {
+		panic("close of nil channel")
+	}
+	if hx.CodeBool("", "Channel.isClosed(_a.param(0).val);", c) {
+		panic("close of closed channel")
+	}
+	hx.Code("", "cast(_a.param(0).val,Channel).close();", c)
 }
 func chanlen(ch unsafe.Pointer) int {
-	panic("reflect.chanlen() not yet implemented in haxe")
-	return 0
+	c := haxeChan(ch)
+	if hx.IsNull(c) {
+		return 0
+	}
+	return hx.CodeInt("", "cast(_a.param(0).val,Channel).len();", c)
 }
 
+// chanrecv receives from ch into val, blocking via the Scheduler unless nb is set.
+// As with the generated code, receiving from a nil channel blocks forever.
 //go:noescape
 func chanrecv(t *rtype, ch unsafe.Pointer, nb bool, val unsafe.Pointer) (selected, received bool) {
-	panic("reflect.chanrecv() not yet implemented in haxe")
-	return false, false
+	c := haxeChan(ch)
+	if nb && hx.CodeBool("", "Channel.hasNoContents(_a.param(0).val);", c) {
+		return false, false
+	}
+	v, received := <-goChan(c)
+	if received { // otherwise the channel is closed and val keeps its zero value
+		chanElemFromHaxe((*chanType)(unsafe.Pointer(t)).elem, v, val)
+	}
+	return true, received
+}
+
+// goChan gives the Haxe Channel c as a Go channel, so that a blocking operation on it waits in the Scheduler,
+// just as the generated code for a channel operation does.
+// A Channel holds the Haxe form of its values, which a uintptr can carry.
+func goChan(c uintptr) chan uintptr {
+	return *(*chan uintptr)(unsafe.Pointer(&c))
+}
+
+// chanSendReady reports whether a send on the Haxe Channel c can proceed, by the same test as the generated code.
+// Like that code, it treats an unbuffered channel as having room for one value.
+func chanSendReady(c uintptr) bool {
+	return hx.CodeBool("", "Channel.hasSpace(_a.param(0).val);", c)
 }
 
+// chansend sends the value at val on ch, blocking via the Scheduler unless nb is set.
+// Sending on a closed channel panics, sending on a nil channel blocks forever.
+// As with the generated code, a blocked send is not woken by the channel being closed.
 //go:noescape
 func chansend(t *rtype, ch unsafe.Pointer, val unsafe.Pointer, nb bool) bool {
-	panic("reflect.chansend() not yet implemented in haxe")
-	return false
-
-	// TODO
-	//v := hx.CodeDynamic("",
-	//	"_a.param(0).val.load_object(_a.param(1).val);",
-	//	val, t.Elem().Size())
+	c := haxeChan(ch)
+	if hx.CodeBool("", "Channel.isClosed(_a.param(0).val);", c) {
+		panic("send on closed channel")
+	}
+	if nb && !chanSendReady(c) {
+		return false
+	}
+	goChan(c) <- chanElemToHaxe((*chanType)(unsafe.Pointer(t)).elem, val)
+	return true
 }
 
 func makechan(typ *rtype, size uint64) (ch unsafe.Pointer) {
@@ -2794,56 +2934,66 @@ func makechan(typ *rtype, size uint64) (ch unsafe.Pointer) {
 	*((*uintptr)(chPtr)) = hx.CodeDynamic("", "new Channel(_a.param(0).val);", uint(size))
 	return chPtr
 }
+
+// makemap returns a pointer to a new Haxe GOmap, with the zero values of the key and element types as its defaults.
 func makemap(t *rtype) (m unsafe.Pointer) {
-	//panic("reflectmakemap() not yet implemented in haxe")
 	if t == nil {
 		panic("reflect.makemap() nil pointer to type info")
 	}
 	mapPtr := hx.Malloc(t.Size())
 	kt := (*mapType)(unsafe.Pointer(t)).key
 	et := (*mapType)(unsafe.Pointer(t)).elem
-	kv := haxeInterfacePack(&emptyInterface{typ: kt, word: hx.Malloc(kt.Size())})
-	ev := haxeInterfacePack(&emptyInterface{typ: et, word: hx.Malloc(et.Size())})
+	kv := haxeInterfacePack(&emptyInterface{typ: kt, word: zeroWord(kt)})
+	ev := haxeInterfacePack(&emptyInterface{typ: et, word: zeroWord(et)})
 	*(*uintptr)(mapPtr) = hx.CodeDynamic("",
 		"new GOmap(_a.param(0).val,_a.param(1).val);", kv, ev)
 	return mapPtr
 }
 
+// zeroWord returns the emptyInterface word for the zero value of t:
+// nil for pointers, which are held directly, otherwise newly allocated zeroed memory.
+func zeroWord(t *rtype) unsafe.Pointer {
+	switch t.Kind() {
+	case Ptr, UnsafePointer:
+		return nil
+	}
+	return hx.Malloc(t.Size())
+}
+
+// mapaccess returns a pointer to the element for key in the map at mp,
+// or nil if the map is nil or does not contain key.
 func mapaccess(t *rtype, mp unsafe.Pointer, key unsafe.Pointer) (val unsafe.Pointer) {
 	if t == nil {
 		panic("reflect.mapaccess() nil pointer to type info")
 	}
-	//panic("reflect.mapaccess() not yet implemented in haxe")
-	//return nil
-	kt := (*mapType)(unsafe.Pointer(t)).key
-	et := (*mapType)(unsafe.Pointer(t)).elem
-	ei := new(emptyInterface)
-	var el uintptr = hx.Null()
-	if mp != nil {
-		m := (uintptr)(mp)
-		for hx.CodeBool("", "Std.is(_a.param(0).val,Pointer);", m) {
-			m = hx.CodeDynamic("", "_a.param(0).val.load();", m) // go down the pointer chain
-		}
-		if !hx.IsNull(m) {
-			if key != nil {
-				ei.typ = kt
-				ei.word = key
-				hk := haxeInterfacePack(ei)
-				el = hx.CodeDynamic("",
-					"cast(_a.param(0).val,GOmap).get(_a.param(1).val);",
-					m, hk)
-				//println("DEBUG mapaccess key,val=", hk, el)
-			} else {
-				el = hx.CodeDynamic("",
-					"cast(_a.param(0).val,GOmap).vz;",
-					m)
-				//println("DEBUG mapaccess default val=", el)
-			}
-		}
+	if mp == nil {
+		return nil
+	}
+	m := (uintptr)(mp)
+	for hx.CodeBool("", "Std.is(_a.param(0).val,Pointer);", m) {
+		m = hx.CodeDynamic("", "_a.param(0).val.load();", m) // go down the pointer chain
+	}
+	if hx.IsNull(m) {
+		return nil
+	}
+	hk := haxeInterfacePack(&emptyInterface{typ: (*mapType)(unsafe.Pointer(t)).key, word: key})
+	if !hx.CodeBool("", "cast(_a.param(0).val,GOmap).exists(_a.param(1).val);", m, hk) {
+		return nil
+	}
+	el := hx.CodeDynamic("", "cast(_a.param(0).val,GOmap).get(_a.param(1).val);", m, hk)
+	return mapWord((*mapType)(unsafe.Pointer(t)).elem, el)
+}
+
+// mapWord returns a pointer to the Go value of type t held in the GOmap as the Haxe value hv.
+func mapWord(t *rtype, hv uintptr) unsafe.Pointer {
+	ei := &emptyInterface{typ: t}
+	haxe2go(ei, hv)
+	switch t.Kind() {
+	case Ptr, UnsafePointer: // haxe2go gives the pointer itself, rather than a pointer to it
+		p := hx.Malloc(t.Size())
+		*(*unsafe.Pointer)(p) = ei.word
+		return p
 	}
-	ei.typ = et
-	ei.word = nil
-	haxe2go(ei, el)
 	return ei.word
 }
 func mapassign(t *rtype, mp unsafe.Pointer, key, val unsafe.Pointer) {
@@ -2859,14 +3009,7 @@ func mapassign(t *rtype, mp unsafe.Pointer, key, val unsafe.Pointer) {
 		m = hx.CodeDynamic("", "_a.param(0).val.load();", m) // go down the pointer chain
 	}
 	if hx.IsNull(uintptr(m)) {
-		panic("reflect.mapassign() null Haxe map") // as it does in the real runtime version
-		//println("DEBUG ignore write to empty map")
-		//return // NoOp
-		/*
-			println("DEBUG auto-create empty map")
-			*(*uintptr)(mp) = *(*uintptr)(makemap(t)) // make a suitable map TODO review if correct, required for encoding/gob
-			m = *(*uintptr)(mp)
-		*/
+		panic("assignment to entry in nil map") // as it does in the real runtime version
 	}
 	if !hx.CodeBool("", "Std.is(_a.param(0).val,GOmap);", m) {
 		panic("reflect.mapassign() not a Haxe map: " + hx.CallString("", "Std.string", 1, m))
@@ -2877,8 +3020,24 @@ func mapassign(t *rtype, mp unsafe.Pointer, key, val unsafe.Pointer) {
 		"cast(_a.param(0).val,GOmap).set(_a.param(1).val,_a.param(2).val);",
 		m, kv, ev)
 }
-func mapdelete(t *rtype, m unsafe.Pointer, key unsafe.Pointer) {
-	panic("reflect.mapdelete() not yet implemented in haxe")
+
+// mapdelete removes key from the map at mp; as in Go, deleting from a nil map does nothing.
+func mapdelete(t *rtype, mp unsafe.Pointer, key unsafe.Pointer) {
+	if t == nil {
+		panic("reflect.mapdelete() nil pointer to type info")
+	}
+	if mp == nil {
+		return
+	}
+	m := (uintptr)(mp)
+	for hx.CodeBool("", "Std.is(_a.param(0).val,Pointer);", m) {
+		m = hx.CodeDynamic("", "_a.param(0).val.load();", m) // go down the pointer chain
+	}
+	if hx.IsNull(m) {
+		return
+	}
+	kv := haxeInterfacePack(&emptyInterface{typ: (*mapType)(unsafe.Pointer(t)).key, word: key})
+	hx.Code("", "cast(_a.param(0).val,GOmap).remove(_a.param(1).val);", m, kv)
 }
 
 type mapIter struct {
@@ -2918,11 +3077,10 @@ func mapiterkey(it unsafe.Pointer) (key unsafe.Pointer) {
 		return nil
 	}
 	mi := (*mapIter)(it)
-	kt := (*mapType)(unsafe.Pointer(mi.t)).key
-	ei := new(emptyInterface)
-	ei.typ = kt
-	haxe2go(ei, mi.key)
-	return ei.word
+	if !mi.ok {
+		return nil // no more keys
+	}
+	return mapWord((*mapType)(unsafe.Pointer(mi.t)).key, mi.key)
 }
 func mapiternext(it unsafe.Pointer) {
 	//panic("reflect.mspiternext() not yet implemented in haxe")
@@ -59,9 +59,30 @@ func MemProfile(p []MemProfileRecord, inuseZero bool) (n int, ok bool) {
 	return
 }
 
+var totalAlloc, lastHeapAlloc uint64 // for MemStats.TotalAlloc
+
+// ReadMemStats populates m with the host heap statistics, where they are available.
+// Only the totals and the garbage collection count are known, the other fields are zero.
+// The host does not count the bytes allocated, so TotalAlloc is the sum of the increases in HeapAlloc
+// seen by the calls to ReadMemStats, which is less than the true figure.
 func ReadMemStats(m *MemStats) {
-	//panic("TODO:runtime.ReadMemStats")
-	// bytes testing calls this, so no-op
+	*m = MemStats{}
+	m.HeapAlloc = uint64(hx.CallFloat("", "GOgc.heapInUse", 0))
+	if m.HeapAlloc > lastHeapAlloc {
+		totalAlloc += m.HeapAlloc - lastHeapAlloc
+	}
+	lastHeapAlloc = m.HeapAlloc
+	m.HeapSys = uint64(hx.CallFloat("", "GOgc.heapSys", 0))
+	m.HeapInuse = m.HeapAlloc
+	if m.HeapSys > m.HeapAlloc {
+		m.HeapIdle = m.HeapSys - m.HeapAlloc
+	}
+	m.Alloc = m.HeapAlloc
+	m.TotalAlloc = totalAlloc
+	m.Sys = m.HeapSys
+	m.NumGC = uint32(hx.CallInt("", "GOgc.gcCount", 0))
+	m.LastGC = uint64(hx.GetFloat("", "GOgc.lastGC") * 1e9)
+	m.EnableGC = true
 }
 func ThreadCreateProfile(p []StackRecord) (n int, ok bool) {
 	panic("TODO:runtime.ThreadCreateProfile")
@@ -161,9 +182,42 @@ func GOROOT() string { return "" } // TODO set as compile time value
 
 func Version() string { return "go1.4" } // TODO automate this
 
-func SetFinalizer(obj interface{}, finalizer interface{}) {}
+// SetFinalizer sets the finalizer associated with obj, or clears it if finalizer is nil.
+// Finalizers only run on the targets where the host garbage collector can be observed (cpp, cs, java and js with FinalizationRegistry).
+// They run one at a time in their own goroutine, some time after the host finds obj unreachable,
+// and are given a pointer that is equal to obj and shares its memory, but is not the same Haxe object.
+// A finalizer whose parameter is interface{} is given obj as an interface value, otherwise it is given the pointer itself.
+func SetFinalizer(obj interface{}, finalizer interface{}) {
+	_, argIsIface := finalizer.(func(interface{}))
+	if hx.CodeBool("", "GOgc.setFinalizer(_a.param(0),_a.param(1),_a.param(2).val);", obj, finalizer, argIsIface) &&
+		finalizer != nil && finalizersReady == nil {
+		finalizersReady = make(chan bool, 1)
+		hx.Code("", "GOgc.ready=_a.param(0).val;", finalizersReady)
+		go runFinalizers()
+	}
+}
+
+var finalizersReady chan bool // sent a value by the host when there are finalizers to run
 
-func GC() {}
+// runFinalizers is the goroutine that runs the finalizers of unreachable objects.
+func runFinalizers() {
+	for range finalizersReady {
+		for {
+			sf := hx.CodeDynamic("", "GOgc.runNext(this._goroutine);")
+			if hx.IsNull(sf) {
+				break
+			}
+			for hx.CodeBool("", "_a.param(0).val._incomplete;", sf) {
+				Gosched() // the finalizer runs on top of this goroutine's stack, so this only waits for it to return
+			}
+		}
+	}
+}
+
+// GC asks the host to run a garbage collection, if it allows that (js only does so in node with --expose-gc).
+func GC() {
+	hx.Call("", "GOgc.collect", 0)
+}
 
 func LockOSThread()   {}
 func UnlockOSThread() {}
@@ -10,6 +10,8 @@ import (
 	"runtime"
 	"sync/atomic"
 	"unsafe"
+
+	"github.com/tardisgo/tardisgo/haxe/hx"
 )
 
 // A Pool is a set of temporary objects that may be individually saved and
@@ -148,6 +150,7 @@ func (p *Pool) getSlow() (x interface{}) {
 // pin pins the current goroutine to P, disables preemption and returns poolLocal pool for the P.
 // Caller must call runtime_procUnpin() when done with the pool.
 func (p *Pool) pin() *poolLocal {
+	poolCheckGC()
 	pid := runtime_procPin()
 	// In pinSlow we store to localSize and then to local, here we load in opposite order.
 	// Since we've disabled preemption, GC can not happen in between.
@@ -221,7 +224,23 @@ func indexLocal(l unsafe.Pointer, i int) *poolLocal {
 	return &(*[1000000]poolLocal)(l)[i]
 }
 
+// The host garbage collector cannot call poolCleanup, so instead the pools are emptied
+// the next time they are used after the host has been seen to run a collection.
+var (
+	poolCleanupFn func()
+	poolGCs       int // the host garbage collection count when the pools were last emptied
+)
+
+func poolCheckGC() {
+	if n := hx.CallInt("", "GOgc.gcCount", 0); n != poolGCs {
+		poolGCs = n
+		if poolCleanupFn != nil {
+			poolCleanupFn()
+		}
+	}
+}
+
 // Implemented in runtime.
-func runtime_registerPoolCleanup(cleanup func()) {}           // TODO(haxe) review correct action here
+func runtime_registerPoolCleanup(cleanup func()) { poolCleanupFn = cleanup }
 func runtime_procPin() int                       { return 0 } // TODO(haxe) review correct action here
 func runtime_procUnpin()                         {}           // TODO(haxe) review correct action here
@@ -0,0 +1,197 @@
+// Haxe-specific process creation, for those targets that can start host processes (cpp, cs, java, neko and js under node).
+//
+// NaCl has no fork/exec, so StartProcess starts a host process using the HostProc Haxe class instead.
+// The standard input, output and error of the process are connected to the fds given in attr.Files
+// by goroutines that copy data between them, so that os/exec pipes work as usual.
+// The HostProc class never blocks, so these goroutines, and Wait4, park on a channel which HostProc sends a value to
+// when something happens to the process, so the rest of the program runs meanwhile.
+// Under node, process events are only delivered when the JS event loop runs,
+// so the program must give it control regularly, for example by using haxegoruntime.BrowserMain().
+//
+// Paths within a host mount, see fs_host_haxe.go, are given to the host as the host path;
+// other paths, including a command without a '/', are given to the host unchanged.
+// The process is given the host environment, with the variables in attr.Env added,
+// although on Windows hosts neither attr.Env nor attr.Dir can be used, except under node.
+
+package syscall
+
+import "github.com/tardisgo/tardisgo/haxe/hx"
+
+// A hostProc is a host process started by StartProcess.
+type hostProc struct {
+	h      int       // the HostProc handle, or -1 once it has been waited for
+	copied chan bool // sent a value by each of the goroutines copying output from the process, when it has finished
+}
+
+var hostProcs = make(map[int]*hostProc) // by pid
+
+var nextHostPid = 100 // so as not to clash with Getpid() or Getppid()
+
+var hostExecChecked, hostExecOK bool
+
+func hostExecAvailable() bool {
+	if !hostExecChecked {
+		hostExecChecked = true
+		hostExecOK = hx.CallBool("", "HostProc.available", 0)
+	}
+	return hostExecOK
+}
+
+// hostPathOrSame returns the host path for path if it is within a host mount, otherwise path itself.
+func hostPathOrSame(path string) string {
+	if hp, isHost := hostPathOf(path); isHost {
+		return hp
+	}
+	return path
+}
+
+// HostExecPath returns the path of the executable file that StartProcess gives the host for argv0.
+// A path containing a '/' is resolved using the current directory and the host mounts, as by os.Stat,
+// but is passed on unchanged if it is not within a host mount. Other names are searched for by the host.
+// This function is a Haxe addition, it does not exist in the standard syscall package.
+func HostExecPath(argv0 string) string {
+	for i := 0; i < len(argv0); i++ {
+		if argv0[i] == '/' {
+			return hostPathOrSame(argv0)
+		}
+	}
+	return argv0
+}
+
+func hostStartProcess(argv0 string, argv []string, attr *ProcAttr) (pid int, handle uintptr, err error) {
+	if !hostExecAvailable() {
+		return 0, 0, ENOSYS
+	}
+	if attr == nil {
+		attr = &ProcAttr{}
+	}
+	hx.Call("", "HostProc.clear", 0)
+	for i := 1; i < len(argv); i++ {
+		hx.Call("", "HostProc.addArg", 1, argv[i])
+	}
+	for _, kv := range attr.Env {
+		hx.Call("", "HostProc.addEnv", 1, kv)
+	}
+	dir := attr.Dir
+	if _, isHost := hostPathOf(cwdPath); dir == "" && isHost {
+		dir = cwdPath
+	}
+	if dir != "" {
+		dir = hostPathOrSame(dir)
+	}
+	h := hx.CallInt("", "HostProc.start", 2, HostExecPath(argv0), dir)
+	if h < 0 {
+		return 0, 0, ENOENT
+	}
+
+	p := &hostProc{h: h, copied: make(chan bool, 2)}
+	for i := 0; i < 3; i++ {
+		fd := -1
+		if i < len(attr.Files) && int(attr.Files[i]) >= 0 {
+			// the caller closes its copy of the fd after StartProcess, as it would after a fork
+			if fd, err = Dup(int(attr.Files[i])); err != nil {
+				fd = -1
+			}
+		}
+		if i == 0 {
+			if fd < 0 {
+				hx.Call("", "HostProc.closeStdin", 1, h)
+			} else {
+				go p.copyIn(fd)
+			}
+		} else {
+			go p.copyOut(fd, i)
+		}
+	}
+
+	pid = nextHostPid
+	nextHostPid++
+	hostProcs[pid] = p
+	return pid, 0, nil
+}
+
+// wait parks the goroutine until HostProc reports a change to the process or its output.
+func (p *hostProc) wait() {
+	wake := make(chan bool, 1) // one for each wait, as several goroutines may be waiting on the same process
+	hx.Call("", "HostProc.addWaiter", 2, p.h, wake)
+	<-wake
+}
+
+// copyIn copies from fd to the standard input of the process, until fd reaches its end.
+func (p *hostProc) copyIn(fd int) {
+	defer Close(fd)
+	b := make([]byte, 4096)
+	for {
+		n, err := Read(fd, b)
+		if p.h < 0 {
+			return
+		}
+		if n > 0 && hx.CallInt("", "HostProc.write", 2, p.h, b[:n]) < 0 {
+			break
+		}
+		if n <= 0 || err != nil {
+			break
+		}
+	}
+	hx.Call("", "HostProc.closeStdin", 1, p.h)
+}
+
+// copyOut copies standard output (which=1) or error (which=2) of the process to fd, or discards it if fd < 0.
+func (p *hostProc) copyOut(fd, which int) {
+	defer func() {
+		if fd >= 0 {
+			Close(fd)
+		}
+		p.copied <- true
+	}()
+	b := make([]byte, 4096)
+	for {
+		switch n := hx.CallInt("", "HostProc.read", 3, p.h, which, b); n {
+		case hostRetry:
+			p.wait()
+		case 0, hostError:
+			return
+		default:
+			if fd >= 0 {
+				if _, err := Write(fd, b[:n]); err != nil {
+					Close(fd) // nobody is listening, but the output must still be read
+					fd = -1
+				}
+			}
+		}
+	}
+}
+
+// hostWait4 waits for the process to exit and for all of its output to be copied.
+func hostWait4(pid int, wstatus *WaitStatus) (wpid int, err error) {
+	p, ok := hostProcs[pid]
+	if !ok {
+		return 0, ECHILD
+	}
+	s := hx.CallInt("", "HostProc.status", 1, p.h)
+	for s == hostRetry { // still running
+		p.wait()
+		s = hx.CallInt("", "HostProc.status", 1, p.h)
+	}
+	for i := 0; i < 2; i++ { // for stdout and stderr to be copied
+		<-p.copied
+	}
+	if wstatus != nil {
+		*wstatus = WaitStatus(s)
+	}
+	delete(hostProcs, pid)
+	hx.Call("", "HostProc.release", 1, p.h)
+	p.h = -1
+	return pid, nil
+}
+
+func hostKill(pid int, signum Signal) error {
+	p, ok := hostProcs[pid]
+	if !ok {
+		return ESRCH
+	}
+	if !hx.CallBool("", "HostProc.kill", 2, p.h, int(signum)) {
+		return EPERM
+	}
+	return nil
+}
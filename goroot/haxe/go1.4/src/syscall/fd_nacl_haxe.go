@@ -236,8 +236,7 @@ func (f *naclFile) read(b []byte) (int, error) {
 func naclWrite(fd int, b []byte) int {
 	switch fd {
 	case 1, 2: // stdout,stderr
-		hx.Call("", "Console.naclWrite", 1, string(b))
-		return len(b)
+		return stdioWrite(fd, b) // Haxe change: use the host streams
 	default:
 		panic("syscall.naclWrite(" + hx.CallString("", "Std.string", 1, fd) + "," + string(b) + ")")
 		return 0
@@ -0,0 +1,472 @@
+// Haxe-specific access to the host file system, for those targets that have one (cpp, cs, java, neko and js under node).
+//
+// Parts of the host file system are mounted into the simulated file system in fs_nacl_haxe.go,
+// by default the host root at /host, so that "/host/tmp/a.txt" is the host file "/tmp/a.txt".
+// Everything outside a mount stays in memory, so that is all there is for the browser targets,
+// apart from the persistent store in fs_store_haxe.go, which is mounted in the same way.
+//
+// A host file is read into memory when it is opened and written back when it is closed or synced,
+// which is simple and works the same way on every target, but is not suitable for very large files.
+
+package syscall
+
+import (
+	"unsafe"
+
+	"github.com/tardisgo/tardisgo/haxe/hx"
+)
+
+// A hostMount maps a directory in the simulated file system onto a directory in the host file system.
+type hostMount struct {
+	path     string // in the simulated file system, cleaned
+	hostPath string // in the host file system, without a trailing '/'
+}
+
+var hostMounts []hostMount // later mounts take precedence
+
+// cwdPath is the absolute path of the current directory, used to resolve relative paths before they are looked up,
+// as fs.cwd is out of date when the current directory is within a host mount.
+// It is "" after Fchdir, when the current directory is only known as fs.cwd.
+var cwdPath = "/"
+
+// hostDev is the device number given to every host file, as the in-memory files have device 0.
+const hostDev = 1
+
+func init() {
+	if hostFSAvailable() {
+		HostMount("/host", "/")
+	}
+}
+
+func hostFSAvailable() bool {
+	return hx.CallBool("", "HostFS.available", 0)
+}
+
+// HostMount makes the host directory hostPath available at path in the file system, creating path if required.
+// It returns ENOSYS if the target has no host file system.
+// This function is a Haxe addition, it does not exist in the standard syscall package.
+func HostMount(path, hostPath string) error {
+	if !hostFSAvailable() {
+		return ENOSYS
+	}
+	return hostMountAt(path, hostPath)
+}
+
+// hostMountAt adds a mount, for either the host file system or the browser store.
+func hostMountAt(path, hostPath string) error {
+	if len(path) == 0 || path[0] != '/' || len(hostPath) == 0 {
+		return EINVAL
+	}
+	path = cleanPath(path)
+	if path == "/" {
+		return EINVAL
+	}
+	for len(hostPath) > 0 && hostPath[len(hostPath)-1] == '/' {
+		hostPath = hostPath[:len(hostPath)-1]
+	}
+	if _, isHost := hostPathOf(path); !isHost {
+		// give the mount point a directory in memory, so that it can be found by reading its parent
+		fs.mu.Lock()
+		ip, _, err := fs.namei(path, false)
+		if err != nil {
+			_, err = fs.open(path, O_CREATE|O_EXCL, 0555|S_IFDIR)
+		} else if ip.Mode&S_IFMT != S_IFDIR {
+			err = ENOTDIR
+		}
+		fs.mu.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+	hostMounts = append(hostMounts, hostMount{path: path, hostPath: hostPath})
+	return nil
+}
+
+// HostUnmount removes the most recent host mount at path.
+// This function is a Haxe addition, it does not exist in the standard syscall package.
+func HostUnmount(path string) error {
+	path = cleanPath(path)
+	for i := len(hostMounts) - 1; i >= 0; i-- {
+		if hostMounts[i].path == path {
+			hostMounts = append(hostMounts[:i], hostMounts[i+1:]...)
+			return nil
+		}
+	}
+	return EINVAL
+}
+
+// cleanPath returns the absolute path with no ".", ".." or empty elements.
+func cleanPath(path string) string {
+	var elems []string
+	for {
+		elem, rest := skipelem(path)
+		switch elem {
+		case "":
+			ret := ""
+			for _, e := range elems {
+				ret += "/" + e
+			}
+			if ret == "" {
+				return "/"
+			}
+			return ret
+		case ".":
+		case "..":
+			if len(elems) > 0 {
+				elems = elems[:len(elems)-1]
+			}
+		default:
+			elems = append(elems, elem)
+		}
+		path = rest
+	}
+}
+
+// absPath returns path made absolute using cwdPath, and cleaned, or path itself if the current directory is not known.
+// The system calls resolve every path in this way before looking it up, in a host mount or using fs.namei.
+func absPath(path string) string {
+	if len(path) == 0 {
+		return path
+	}
+	if path[0] != '/' {
+		if cwdPath == "" {
+			return path
+		}
+		path = cwdPath + "/" + path
+	}
+	return cleanPath(path)
+}
+
+// hostPathOf returns the host file system path for path, if it is within a host mount.
+func hostPathOf(path string) (string, bool) {
+	path = absPath(path)
+	if len(hostMounts) == 0 || len(path) == 0 || path[0] != '/' {
+		return "", false
+	}
+	for i := len(hostMounts) - 1; i >= 0; i-- {
+		m := hostMounts[i]
+		if path == m.path || len(path) > len(m.path) && path[:len(m.path)] == m.path && path[len(m.path)] == '/' {
+			hp := m.hostPath + path[len(m.path):]
+			if hp == "" {
+				hp = "/"
+			}
+			return hp, true
+		}
+	}
+	return "", false
+}
+
+// hostStat fills in st for the host file, returning ENOENT if it does not exist.
+func hostStat(hp string, st *Stat_t) error {
+	if !hx.CallBool("", "HostFS.stat", 1, hp) {
+		return ENOENT
+	}
+	perm := uint32(hx.GetInt("", "HostFS.statMode")) & 0777
+	*st = Stat_t{
+		Dev:     hostDev,
+		Ino:     hostIno(hp),
+		Nlink:   1,
+		Size:    int64(hx.GetFloat("", "HostFS.statSize")),
+		Blksize: 512,
+	}
+	if hx.GetBool("", "HostFS.statIsDir") {
+		if perm == 0 {
+			perm = 0777 // the host did not say
+		}
+		st.Mode = S_IFDIR | perm
+	} else {
+		if perm == 0 {
+			perm = 0666
+		}
+		st.Mode = S_IFREG | perm
+	}
+	mtime := hx.GetFloat("", "HostFS.statMtime")
+	st.Mtime = int64(mtime)
+	st.MtimeNsec = int64((mtime - float64(st.Mtime)) * 1e9)
+	st.Atime, st.AtimeNsec = st.Mtime, st.MtimeNsec
+	st.Ctime, st.CtimeNsec = st.Mtime, st.MtimeNsec
+	return nil
+}
+
+// hostIno gives a host file an inode number, using a FNV-1a hash of its path, so that os.SameFile works.
+func hostIno(hp string) uint64 {
+	h := uint64(14695981039346656037)
+	for i := 0; i < len(hp); i++ {
+		h ^= uint64(hp[i])
+		h *= 1099511628211
+	}
+	return h
+}
+
+// A hostFile is the fileImpl implementation backed by the host file system.
+type hostFile struct {
+	defaultFileImpl
+	hostPath string
+	openmode int
+	offset   int64
+	st       Stat_t
+	data     []byte   // the contents of a file
+	dirty    bool     // data needs to be written back to the host
+	dir      []string // the names in a directory, including "." and ".."
+}
+
+// hostOpen opens or creates the host file at hp.
+func hostOpen(hp string, openmode int, mode uint32) (fileImpl, error) {
+	f := &hostFile{hostPath: hp, openmode: openmode}
+	err := hostStat(hp, &f.st)
+	if err != nil {
+		if openmode&O_CREATE == 0 {
+			return nil, err
+		}
+		if !hx.CallBool("", "HostFS.saveBytes", 2, hp, []byte{}) {
+			return nil, EACCES
+		}
+		if err = hostStat(hp, &f.st); err != nil {
+			return nil, err
+		}
+	} else if openmode&(O_CREATE|O_EXCL) == O_CREATE|O_EXCL {
+		return nil, EEXIST
+	}
+
+	if f.st.Mode&S_IFMT == S_IFDIR {
+		if openmode&O_ACCMODE != O_RDONLY {
+			return nil, EISDIR
+		}
+		f.dir = []string{".", ".."}
+		if names := hx.CallString("", "HostFS.readDir", 1, hp); names != "" {
+			start := 0
+			for i := 0; i <= len(names); i++ {
+				if i == len(names) || names[i] == '/' {
+					f.dir = append(f.dir, names[start:i])
+					start = i + 1
+				}
+			}
+		}
+		f.st.Size = int64(len(f.dir)) * direntSize
+		return f, nil
+	}
+
+	if openmode&O_TRUNC != 0 {
+		f.dirty = true
+	} else {
+		f.data = make([]byte, f.st.Size)
+		n := hx.CallInt("", "HostFS.readInto", 2, hp, f.data)
+		if n < 0 {
+			return nil, EIO
+		}
+		f.data = f.data[:n]
+	}
+	f.st.Size = int64(len(f.data))
+	if openmode&O_APPEND != 0 {
+		f.offset = f.st.Size
+	}
+	return f, nil
+}
+
+// hostFile methods to implement fileImpl.
+
+func (f *hostFile) stat(st *Stat_t) error {
+	*st = f.st
+	return nil
+}
+
+func (f *hostFile) read(b []byte) (int, error) {
+	n, err := f.pread(b, f.offset)
+	f.offset += int64(n)
+	return n, err
+}
+
+func (f *hostFile) write(b []byte) (int, error) {
+	n, err := f.pwrite(b, f.offset)
+	f.offset += int64(n)
+	return n, err
+}
+
+func (f *hostFile) seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case 1:
+		offset += f.offset
+	case 2:
+		offset += f.st.Size
+	}
+	if offset < 0 || offset > f.st.Size {
+		return 0, EINVAL
+	}
+	f.offset = offset
+	return offset, nil
+}
+
+func (f *hostFile) pread(b []byte, offset int64) (int, error) {
+	if f.openmode&O_ACCMODE == O_WRONLY || offset < 0 {
+		return 0, EINVAL
+	}
+	if offset > f.st.Size {
+		return 0, nil
+	}
+	if f.dir != nil {
+		return f.readDirent(b, offset)
+	}
+	return copy(b, f.data[offset:]), nil
+}
+
+func (f *hostFile) pwrite(b []byte, offset int64) (int, error) {
+	if f.openmode&O_ACCMODE == O_RDONLY || offset < 0 || offset > f.st.Size {
+		return 0, EINVAL
+	}
+	n := copy(f.data[offset:], b)
+	if n < len(b) {
+		f.data = append(f.data, b[n:]...)
+		f.st.Size = int64(len(f.data))
+	}
+	f.dirty = true
+	f.st.Mtime, f.st.MtimeNsec = nowStat()
+	return len(b), nil
+}
+
+// readDirent reads Dirent records for the directory, in the same way as fsysFile.preadLocked.
+func (f *hostFile) readDirent(b []byte, offset int64) (int, error) {
+	if int64(len(b)) > f.st.Size-offset {
+		b = b[:f.st.Size-offset]
+	}
+	if offset%direntSize != 0 || len(b) != 0 && len(b) < direntSize {
+		return 0, EINVAL
+	}
+	n := 0
+	for len(b) >= direntSize {
+		name := f.dir[int(offset/direntSize)]
+		dst := (*Dirent)(unsafe.Pointer(&b[0]))
+		dst.Ino = int64(hostIno(f.hostPath + "/" + name))
+		dst.Off = offset
+		dst.Reclen = direntSize
+		for i := range dst.Name {
+			dst.Name[i] = 0
+		}
+		copy(dst.Name[:], name)
+		n += direntSize
+		offset += direntSize
+		b = b[direntSize:]
+	}
+	return n, nil
+}
+
+// sync writes the file back to the host, if it has changed.
+func (f *hostFile) sync() error {
+	if !f.dirty {
+		return nil
+	}
+	if !hx.CallBool("", "HostFS.saveBytes", 2, f.hostPath, f.data) {
+		return EIO
+	}
+	f.dirty = false
+	return nil
+}
+
+func (f *hostFile) close() error {
+	return f.sync()
+}
+
+// truncate sets the length of the file, which is written back to the host when it is closed or synced.
+func (f *hostFile) truncate(length int64) error {
+	if f.dir != nil {
+		return EISDIR
+	}
+	if length < 0 || length > 1e9 || f.openmode&O_ACCMODE == O_RDONLY {
+		return EINVAL
+	}
+	if length < int64(len(f.data)) {
+		f.data = f.data[:length]
+	} else {
+		f.data = append(f.data, make([]byte, length-int64(len(f.data)))...)
+	}
+	f.st.Size = length
+	f.dirty = true
+	f.st.Mtime, f.st.MtimeNsec = nowStat()
+	return nil
+}
+
+func nowStat() (sec, nsec int64) {
+	s, ns := now()
+	return s, int64(ns)
+}
+
+// Host versions of the system calls in fs_nacl_haxe.go, called when the path is within a host mount.
+// The host file system has no portable way to change the mode, owner or times of a file, or to link to it,
+// so Chmod, Chown, UtimesNano and Link (and their fd versions) return ENOSYS for host files.
+
+func hostTruncate(hp string, length int64) error {
+	f, err := hostOpen(hp, O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	hf := f.(*hostFile)
+	if err = hf.truncate(length); err != nil {
+		return err
+	}
+	return hf.close()
+}
+
+func hostMkdir(hp string) error {
+	var st Stat_t
+	if hostStat(hp, &st) == nil {
+		return EEXIST
+	}
+	if err := hostStat(hostParent(hp), &st); err != nil {
+		return err
+	}
+	if st.Mode&S_IFMT != S_IFDIR {
+		return ENOTDIR
+	}
+	if !hx.CallBool("", "HostFS.createDir", 1, hp) {
+		return EACCES
+	}
+	return nil
+}
+
+func hostUnlink(hp string, isdir bool) error {
+	var st Stat_t
+	if err := hostStat(hp, &st); err != nil {
+		return err
+	}
+	if isdir {
+		if st.Mode&S_IFMT != S_IFDIR {
+			return ENOTDIR
+		}
+		if hx.CallString("", "HostFS.readDir", 1, hp) != "" {
+			return ENOTEMPTY
+		}
+		if !hx.CallBool("", "HostFS.deleteDir", 1, hp) {
+			return EACCES
+		}
+		return nil
+	}
+	if st.Mode&S_IFMT == S_IFDIR {
+		return EISDIR
+	}
+	if !hx.CallBool("", "HostFS.deleteFile", 1, hp) {
+		return EACCES
+	}
+	return nil
+}
+
+func hostRename(from, to string) error {
+	var st Stat_t
+	if err := hostStat(from, &st); err != nil {
+		return err
+	}
+	if !hx.CallBool("", "HostFS.rename", 2, from, to) {
+		return EACCES
+	}
+	return nil
+}
+
+// hostParent returns the host directory containing hp.
+func hostParent(hp string) string {
+	i := len(hp) - 1
+	for i > 0 && hp[i] != '/' {
+		i--
+	}
+	if i <= 0 {
+		return "/"
+	}
+	return hp[:i]
+}
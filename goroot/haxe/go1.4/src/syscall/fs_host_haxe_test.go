@@ -0,0 +1,160 @@
+// Tests of the host mount table in fs_host_haxe.go, which only look at paths, so that they run on every target.
+
+package syscall
+
+import "testing"
+
+// withMounts runs f with only the given mounts and the current directory cwd, restoring both afterwards.
+func withMounts(cwd string, mounts []hostMount, f func()) {
+	oldMounts, oldCwd := hostMounts, cwdPath
+	defer func() { hostMounts, cwdPath = oldMounts, oldCwd }()
+	hostMounts, cwdPath = mounts, cwd
+	f()
+}
+
+func TestHostCleanPath(t *testing.T) {
+	for _, tt := range []struct{ in, out string }{
+		{"/", "/"},
+		{"", "/"},
+		{"/a/b", "/a/b"},
+		{"//a//b/", "/a/b"},
+		{"/a/./b/../c", "/a/c"},
+		{"/../..", "/"},
+		{"a/b/..", "/a"},
+	} {
+		if got := cleanPath(tt.in); got != tt.out {
+			t.Errorf("cleanPath(%q) = %q, want %q", tt.in, got, tt.out)
+		}
+	}
+}
+
+func TestHostAbsPath(t *testing.T) {
+	withMounts("/host/tmp", nil, func() {
+		for _, tt := range []struct{ in, out string }{
+			{"", ""},
+			{"a.txt", "/host/tmp/a.txt"},
+			{"./a/../b", "/host/tmp/b"},
+			{"../../mem", "/mem"},
+			{"/x/./y", "/x/y"},
+		} {
+			if got := absPath(tt.in); got != tt.out {
+				t.Errorf("absPath(%q) with cwd /host/tmp = %q, want %q", tt.in, got, tt.out)
+			}
+		}
+	})
+	withMounts("", nil, func() {
+		if got := absPath("a/b"); got != "a/b" {
+			t.Errorf("absPath(%q) with an unknown cwd = %q, want it unchanged", "a/b", got)
+		}
+	})
+}
+
+func TestHostPathOf(t *testing.T) {
+	mounts := []hostMount{{"/host", ""}, {"/host/data", "/srv/data"}, {"/work", "/home/user/work"}}
+	withMounts("/host/tmp", mounts, func() {
+		for _, tt := range []struct {
+			in     string
+			out    string
+			isHost bool
+		}{
+			{"/host", "/", true},
+			{"/host/etc/hosts", "/etc/hosts", true},
+			{"/hostile", "", false},
+			{"/host/data/x", "/srv/data/x", true}, // the later mount takes precedence
+			{"/host/data/../y", "/y", true},
+			{"/work/../work/a", "/home/user/work/a", true},
+			{"/tmp", "", false},
+			{"a.txt", "/tmp/a.txt", true}, // relative to the cwd in a host mount
+			{"../data/z", "/srv/data/z", true},
+			{"../../mem/file", "", false}, // out of the mount, so back to the in-memory file system
+			{"", "", false},
+		} {
+			got, isHost := hostPathOf(tt.in)
+			if got != tt.out || isHost != tt.isHost {
+				t.Errorf("hostPathOf(%q) = %q, %v, want %q, %v", tt.in, got, isHost, tt.out, tt.isHost)
+			}
+		}
+	})
+	withMounts("/", mounts, func() {
+		if got, isHost := hostPathOf("work/b"); got != "/home/user/work/b" || !isHost {
+			t.Errorf("hostPathOf(%q) with cwd / = %q, %v, want a path in the /work mount", "work/b", got, isHost)
+		}
+	})
+	withMounts("", mounts, func() {
+		if got, isHost := hostPathOf("work/b"); isHost {
+			t.Errorf("hostPathOf(%q) with an unknown cwd = %q, want no host path", "work/b", got)
+		}
+	})
+}
+
+func TestHostExecPath(t *testing.T) {
+	mounts := []hostMount{{"/host", ""}, {"/work", "/home/user/work"}}
+	withMounts("/work/src", mounts, func() {
+		for _, tt := range []struct{ in, out string }{
+			{"sh", "sh"}, // searched for by the host
+			{"/host/bin/sh", "/bin/sh"},
+			{"bin/tool", "/home/user/work/src/bin/tool"}, // relative to the cwd in a host mount
+			{"./tool", "/home/user/work/src/tool"},
+			{"../../host/usr/bin/env", "/usr/bin/env"},
+			{"/usr/bin/env", "/usr/bin/env"}, // not within a host mount, so passed on unchanged
+			{"../../mem/tool", "../../mem/tool"},
+		} {
+			if got := HostExecPath(tt.in); got != tt.out {
+				t.Errorf("HostExecPath(%q) with cwd /work/src = %q, want %q", tt.in, got, tt.out)
+			}
+		}
+	})
+}
+
+func TestHostMountFallback(t *testing.T) {
+	withMounts("/", nil, func() {
+		if err := hostMountAt("relative", "/x"); err != EINVAL {
+			t.Errorf("hostMountAt of a relative path returned %v, want EINVAL", err)
+		}
+		if err := hostMountAt("/", "/x"); err != EINVAL {
+			t.Errorf("hostMountAt of / returned %v, want EINVAL", err)
+		}
+		if err := hostMountAt("/hostmnttest/", "/srv//"); err != nil {
+			t.Fatalf("hostMountAt returned %v", err)
+		}
+		if len(hostMounts) != 1 || hostMounts[0] != (hostMount{"/hostmnttest", "/srv"}) {
+			t.Errorf("after hostMountAt, the mounts are %v, want [{/hostmnttest /srv}]", hostMounts)
+		}
+
+		// the mount point is a directory in memory, so it can be found by reading its parent
+		fs.mu.Lock()
+		ip, _, err := fs.namei("/hostmnttest", false)
+		fs.mu.Unlock()
+		if err != nil || ip.Mode&S_IFMT != S_IFDIR {
+			t.Errorf("the mount point is not an in-memory directory, err = %v", err)
+		}
+
+		// a path out of the mount, relative to a cwd within it, is found in memory rather than by fs.cwd
+		if err := Mkdir("/hostmnttestmem", 0777); err != nil {
+			t.Fatalf("Mkdir returned %v", err)
+		}
+		defer Rmdir("/hostmnttestmem")
+		cwdPath = "/hostmnttest/sub"
+		var st Stat_t
+		if err := Stat("../../hostmnttestmem", &st); err != nil || st.Mode&S_IFMT != S_IFDIR {
+			t.Errorf("Stat of an in-memory directory relative to a host cwd returned %v", err)
+		}
+		if err := Rename("../../hostmnttestmem", "../x"); err != EXDEV {
+			t.Errorf("Rename between memory and a host mount returned %v, want EXDEV", err)
+		}
+		if err := Chmod("a.txt", 0600); err != ENOSYS {
+			t.Errorf("Chmod in a host mount returned %v, want ENOSYS", err)
+		}
+		if err := Link("../../hostmnttestmem", "b.txt"); err != ENOSYS {
+			t.Errorf("Link into a host mount returned %v, want ENOSYS", err)
+		}
+		cwdPath = "/"
+
+		if err := HostUnmount("/hostmnttest"); err != nil || len(hostMounts) != 0 {
+			t.Errorf("HostUnmount returned %v, leaving %v", err, hostMounts)
+		}
+		if err := HostUnmount("/hostmnttest"); err != EINVAL {
+			t.Errorf("a second HostUnmount returned %v, want EINVAL", err)
+		}
+	})
+}
@@ -0,0 +1,107 @@
+// Tests of the files in fs_nacl_haxe.go and unzip_nacl_haxe.go whose data is only read when they are first opened,
+// which only use the in-memory file system, so that they run on every target.
+
+package syscall
+
+import "testing"
+
+// lazyZip is a zip file holding a.txt, deflated, and dir/b.txt, stored.
+const lazyZip = "" +
+	"\x50\x4b\x03\x04\x14\x00\x00\x00\x08\x00\x83\x18\x22\x44\x0b\x99\x9e\xe8\x10\x00\x00\x00\x2c\x00" +
+	"\x00\x00\x05\x00\x00\x00\x61\x2e\x74\x78\x74\xcb\x48\xcd\xc9\xc9\xd7\x51\xa8\xca\x2c\xe0\xca\x20" +
+	"\xc4\x04\x00\x50\x4b\x03\x04\x14\x00\x00\x00\x00\x00\x83\x18\x22\x44\x0b\xf9\x43\x56\x06\x00\x00" +
+	"\x00\x06\x00\x00\x00\x09\x00\x00\x00\x64\x69\x72\x2f\x62\x2e\x74\x78\x74\x73\x74\x6f\x72\x65\x64" +
+	"\x50\x4b\x01\x02\x14\x03\x14\x00\x00\x00\x08\x00\x83\x18\x22\x44\x0b\x99\x9e\xe8\x10\x00\x00\x00" +
+	"\x2c\x00\x00\x00\x05\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x80\x01\x00\x00\x00\x00\x61\x2e" +
+	"\x74\x78\x74\x50\x4b\x01\x02\x14\x03\x14\x00\x00\x00\x00\x00\x83\x18\x22\x44\x0b\xf9\x43\x56\x06" +
+	"\x00\x00\x00\x06\x00\x00\x00\x09\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x80\x01\x33\x00\x00" +
+	"\x00\x64\x69\x72\x2f\x62\x2e\x74\x78\x74\x50\x4b\x05\x06\x00\x00\x00\x00\x02\x00\x02\x00\x6a\x00" +
+	"\x00\x00\x60\x00\x00\x00\x00\x00"
+
+// isLoaded returns true if the data of the file at path has been read into memory.
+func isLoaded(t *testing.T, path string) bool {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	ip, _, err := fs.namei(path, false)
+	if err != nil {
+		t.Fatalf("namei(%q): %v", path, err)
+	}
+	return ip.load == nil
+}
+
+// readLazy opens and reads the file at path.
+func readLazy(t *testing.T, path string) string {
+	fd, err := Open(path, O_RDONLY, 0)
+	if err != nil {
+		t.Fatalf("Open(%q): %v", path, err)
+	}
+	defer Close(fd)
+	buf := make([]byte, 100)
+	n, err := Read(fd, buf)
+	if err != nil {
+		t.Fatalf("Read(%q): %v", path, err)
+	}
+	return string(buf[:n])
+}
+
+func TestUnzipLazy(t *testing.T) {
+	fsinit()
+	unzip("/lazyzip", lazyZip)
+	files := []struct {
+		path, data string
+	}{
+		{"/lazyzip/a.txt", "hello, zip\nhello, zip\nhello, zip\nhello, zip\n"},
+		{"/lazyzip/dir/b.txt", "stored"},
+	}
+	for _, f := range files {
+		var st Stat_t
+		if err := Stat(f.path, &st); err != nil {
+			t.Fatalf("Stat(%q): %v", f.path, err)
+		}
+		if st.Size != int64(len(f.data)) || st.Mode&S_IFMT != S_IFREG {
+			t.Errorf("Stat(%q) gives size %d and mode %o, want %d and a regular file", f.path, st.Size, st.Mode, len(f.data))
+		}
+		if isLoaded(t, f.path) {
+			t.Errorf("%s was un-zipped by Stat", f.path)
+		}
+	}
+	for _, f := range files {
+		if got := readLazy(t, f.path); got != f.data {
+			t.Errorf("read %q from %s, want %q", got, f.path, f.data)
+		}
+		if !isLoaded(t, f.path) {
+			t.Errorf("%s was not un-zipped when opened", f.path)
+		}
+	}
+}
+
+func TestCreateLazy(t *testing.T) {
+	fsinit()
+	loads := 0
+	load := func() []byte { loads++; return []byte("resource data") }
+	const path = "/lazyres/dir/r.txt"
+	if err := createLazy(path, 0666|S_IFREG, 0, -1, load); err != nil {
+		t.Fatal(err)
+	}
+	var st Stat_t
+	if err := Stat(path, &st); err != nil {
+		t.Fatal(err)
+	}
+	if st.Size != 0 || loads != 0 {
+		t.Errorf("before the first open, Stat gives size %d and the data was loaded %d times, want 0 and 0", st.Size, loads)
+	}
+	if err := Stat("/lazyres/dir", &st); err != nil || st.Mode&S_IFMT != S_IFDIR {
+		t.Errorf("Stat of the directory made for the file: mode %o, %v", st.Mode, err)
+	}
+	for i := 0; i < 2; i++ {
+		if got := readLazy(t, path); got != "resource data" {
+			t.Errorf("read %q, want %q", got, "resource data")
+		}
+	}
+	if loads != 1 {
+		t.Errorf("the data was loaded %d times, want once", loads)
+	}
+	if err := Stat(path, &st); err != nil || st.Size != int64(len("resource data")) {
+		t.Errorf("after opening, Stat gives size %d, %v, want %d", st.Size, err, len("resource data"))
+	}
+}
@@ -25,7 +25,7 @@ import (
 
 // Provided by package runtime.
 func now() (sec int64, nsec int32) {
-	haxeNow := hx.GetFloat("", "Date.now().getTime()")
+	haxeNow := hx.CallFloat("", "HostClock.now", 0) // seconds
 	secFloat := hx.CallFloat("", "Math.ffloor", 1, haxeNow)
 	return int64(secFloat), int32(1000000000 * (haxeNow - secFloat))
 }
@@ -54,6 +54,7 @@ type inode struct {
 	Stat_t
 	data []byte
 	dir  []dirent
+	load func() []byte // Haxe addition: reads the data of a file from a zip file or resource, on first use
 }
 
 // A dirent describes a single directory entry.
@@ -122,30 +123,18 @@ var fsinit = func() {
 						}
 					}
 				}
-				//fd, err := Open(nam, O_CREATE|O_EXCL, 0666) // replaced with code below to avoid init loop
-				f, err := fs.open(nam, O_CREATE|O_WRONLY, 0666|S_IFREG)
-				fd := newFD(f)
-				// end code replacement
+				// the file is only read from the resource when it is first opened
+				sec, _ := now()
+				res := nam
+				err := createLazy(nam, 0666|S_IFREG, sec, -1, func() []byte { return hx.Resource(res) })
 				if err != nil {
-					log += "syscall.fsinit() unable to syscall.Open() for " +
-						nam + " : " + err.Error() + "\n"
+					log += "syscall.fsinit() unable to create " + nam + " : " + err.Error() + "\n"
 				} else {
-					defer Close(fd)
-					b := hx.Resource(nam)
-					if b == nil || len(b) == 0 {
-						log += "syscall.fsinit() hx.Resource() file empty for: " + nam + "\n"
-					} else {
-						sz, err := Write(fd, b)
-						if err != nil || sz != len(b) {
-							log += "syscall.fsinit() bad syscall.Write() (size or error) for " +
-								nam + " : " + err.Error() + "\n"
-						} else {
-							log += "syscall.fsinit() loaded file: " + nam + "\n"
-						}
-					}
+					log += "syscall.fsinit() loaded file: " + nam + "\n"
 				}
 			}
 		}
+		log += storeFsinit() // Haxe addition
 		//fd, err := Open(nam, O_CREATE|O_EXCL, 0666) // replaced with code below to avoid init loop
 		f, err := fs.open("/fsinit.log", O_CREATE|O_WRONLY, 0666|S_IFREG)
 		fd := newFD(f)
@@ -357,6 +346,7 @@ func (fs *fsys) open(name string, openmode int, mode uint32) (fileImpl, error) {
 				return nil, EISDIR
 			}
 			ip.data = nil
+			ip.load = nil
 		}
 		if ip.Mode&S_IFMT == S_IFCHR {
 			if ip.Rdev < 0 || ip.Rdev >= int64(len(fs.dev)) || fs.dev[ip.Rdev] == nil {
@@ -393,6 +383,10 @@ func (fs *fsys) open(name string, openmode int, mode uint32) (fileImpl, error) {
 		return nil, EPERM
 	}
 
+	if ip.load != nil {
+		fs.loadData(ip)
+	}
+
 	f := &fsysFile{
 		fsys:     fs,
 		inode:    ip,
@@ -423,6 +417,12 @@ func (f *fsysFile) read(b []byte) (int, error) {
 }
 
 func ReadDirent(fd int, buf []byte) (int, error) {
+	if hf, err := fdToHostFile(fd); err == nil { // Haxe addition
+		if hf.dir == nil {
+			return 0, EINVAL
+		}
+		return hf.read(buf)
+	}
 	f, err := fdToFsysFile(fd)
 	if err != nil {
 		return 0, err
@@ -553,6 +553,15 @@ func (f *fsysFile) pwriteLocked(b []byte, offset int64) (int, error) {
 
 func Open(path string, openmode int, perm uint32) (fd int, err error) {
 	fsinit()
+	// Haxe addition, see cwdPath
+	path = absPath(path)
+	if hp, isHost := hostPathOf(path); isHost { // Haxe addition
+		f, err := hostOpen(hp, openmode, perm&0777|S_IFREG)
+		if err != nil {
+			return -1, err
+		}
+		return newFD(f), nil
+	}
 	fs.mu.Lock()
 	defer fs.mu.Unlock()
 	f, err := fs.open(path, openmode, perm&0777|S_IFREG)
@@ -563,6 +572,11 @@ func Open(path string, openmode int, perm uint32) (fd int, err error) {
 }
 
 func Mkdir(path string, perm uint32) error {
+	// Haxe addition, see cwdPath
+	path = absPath(path)
+	if hp, isHost := hostPathOf(path); isHost { // Haxe addition
+		return hostMkdir(hp)
+	}
 	fs.mu.Lock()
 	defer fs.mu.Unlock()
 	_, err := fs.open(path, O_CREATE|O_EXCL, perm&0777|S_IFDIR)
@@ -576,6 +590,11 @@ func Getcwd(buf []byte) (n int, err error) {
 
 func Stat(path string, st *Stat_t) error {
 	fsinit()
+	// Haxe addition, see cwdPath
+	path = absPath(path)
+	if hp, isHost := hostPathOf(path); isHost { // Haxe addition
+		return hostStat(hp, st)
+	}
 	fs.mu.Lock()
 	defer fs.mu.Unlock()
 	ip, _, err := fs.namei(path, false)
@@ -583,6 +602,9 @@ func Stat(path string, st *Stat_t) error {
 		return err
 	}
 	*st = ip.Stat_t
+	if st.Size < 0 { // Haxe addition, the size of a resource file is only known once it is opened, see createLazy
+		st.Size = 0
+	}
 	return nil
 }
 
@@ -592,6 +614,11 @@ func Lstat(path string, st *Stat_t) error {
 
 func unlink(path string, isdir bool) error {
 	fsinit()
+	// Haxe addition, see cwdPath
+	path = absPath(path)
+	if hp, isHost := hostPathOf(path); isHost { // Haxe addition
+		return hostUnlink(hp, isdir)
+	}
 	fs.mu.Lock()
 	defer fs.mu.Unlock()
 	dp, elem, err := fs.namei(path, true)
@@ -634,6 +661,11 @@ func Rmdir(path string) error {
 
 func Chmod(path string, mode uint32) error {
 	fsinit()
+	// Haxe addition, see cwdPath
+	path = absPath(path)
+	if _, isHost := hostPathOf(path); isHost { // Haxe addition
+		return ENOSYS
+	}
 	fs.mu.Lock()
 	defer fs.mu.Unlock()
 	ip, _, err := fs.namei(path, false)
@@ -645,6 +677,9 @@ func Chmod(path string, mode uint32) error {
 }
 
 func Fchmod(fd int, mode uint32) error {
+	if _, err := fdToHostFile(fd); err == nil { // Haxe addition
+		return ENOSYS
+	}
 	f, err := fdToFsysFile(fd)
 	if err != nil {
 		return err
@@ -657,6 +692,11 @@ func Fchmod(fd int, mode uint32) error {
 
 func Chown(path string, uid, gid int) error {
 	fsinit()
+	// Haxe addition, see cwdPath
+	path = absPath(path)
+	if _, isHost := hostPathOf(path); isHost { // Haxe addition
+		return ENOSYS
+	}
 	fs.mu.Lock()
 	defer fs.mu.Unlock()
 	ip, _, err := fs.namei(path, false)
@@ -669,6 +709,9 @@ func Chown(path string, uid, gid int) error {
 }
 
 func Fchown(fd int, uid, gid int) error {
+	if _, err := fdToHostFile(fd); err == nil { // Haxe addition
+		return ENOSYS
+	}
 	fs.mu.Lock()
 	defer fs.mu.Unlock()
 	f, err := fdToFsysFile(fd)
@@ -691,6 +734,11 @@ func UtimesNano(path string, ts []Timespec) error {
 		return EINVAL
 	}
 	fsinit()
+	// Haxe addition, see cwdPath
+	path = absPath(path)
+	if _, isHost := hostPathOf(path); isHost { // Haxe addition
+		return ENOSYS
+	}
 	fs.mu.Lock()
 	defer fs.mu.Unlock()
 	ip, _, err := fs.namei(path, false)
@@ -706,6 +754,12 @@ func UtimesNano(path string, ts []Timespec) error {
 
 func Link(path, link string) error {
 	fsinit()
+	path, link = absPath(path), absPath(link) // Haxe addition, see cwdPath
+	_, pathHost := hostPathOf(path)
+	_, linkHost := hostPathOf(link)
+	if pathHost || linkHost {
+		return ENOSYS
+	}
 	ip, _, err := fs.namei(path, false)
 	if err != nil {
 		return err
@@ -723,6 +777,15 @@ func Link(path, link string) error {
 
 func Rename(from, to string) error {
 	fsinit()
+	from, to = absPath(from), absPath(to) // Haxe addition, see cwdPath
+	hfrom, fromHost := hostPathOf(from)
+	hto, toHost := hostPathOf(to)
+	if fromHost != toHost {
+		return EXDEV
+	}
+	if fromHost {
+		return hostRename(hfrom, hto)
+	}
 	fdp, felem, err := fs.namei(from, true)
 	if err != nil {
 		return err
@@ -747,6 +810,9 @@ func (fs *fsys) truncate(ip *inode, length int64) error {
 	if length > 1e9 || ip.Mode&S_IFMT != S_IFREG {
 		return EINVAL
 	}
+	if ip.load != nil {
+		fs.loadData(ip)
+	}
 	if length < int64(len(ip.data)) {
 		ip.data = ip.data[:length]
 	} else {
@@ -760,6 +826,11 @@ func (fs *fsys) truncate(ip *inode, length int64) error {
 
 func Truncate(path string, length int64) error {
 	fsinit()
+	// Haxe addition, see cwdPath
+	path = absPath(path)
+	if hp, isHost := hostPathOf(path); isHost { // Haxe addition
+		return hostTruncate(hp, length)
+	}
 	fs.mu.Lock()
 	defer fs.mu.Unlock()
 	ip, _, err := fs.namei(path, false)
@@ -770,6 +841,9 @@ func Truncate(path string, length int64) error {
 }
 
 func Ftruncate(fd int, length int64) error {
+	if hf, err := fdToHostFile(fd); err == nil { // Haxe addition
+		return hf.truncate(length)
+	}
 	f, err := fdToFsysFile(fd)
 	if err != nil {
 		return err
@@ -785,17 +859,43 @@ func Chdir(path string) error {
 }
 
 func chdir(path string) error {
+	// Haxe addition, see cwdPath
+	path = absPath(path)
+	if hp, isHost := hostPathOf(path); isHost {
+		var st Stat_t
+		if err := hostStat(hp, &st); err != nil {
+			return err
+		}
+		if st.Mode&S_IFMT != S_IFDIR {
+			return ENOTDIR
+		}
+		cwdPath = path
+		Setenv("PWD", cwdPath) // so that os.Getwd can find it
+		return nil
+	}
 	fs.mu.Lock()
 	defer fs.mu.Unlock()
 	ip, _, err := fs.namei(path, false)
 	if err != nil {
 		return err
 	}
+	if ip.Mode&S_IFMT != S_IFDIR { // Haxe addition
+		return ENOTDIR
+	}
 	fs.cwd = ip
+	if len(path) > 0 && path[0] == '/' { // Haxe addition
+		cwdPath = path
+		Setenv("PWD", cwdPath)
+	} else {
+		cwdPath = "" // only known as fs.cwd
+	}
 	return nil
 }
 
 func Fchdir(fd int) error {
+	if _, err := fdToHostFile(fd); err == nil { // Haxe addition
+		return ENOSYS
+	}
 	f, err := fdToFsysFile(fd)
 	if err != nil {
 		return err
@@ -806,6 +906,7 @@ func Fchdir(fd int) error {
 		return ENOTDIR
 	}
 	fs.cwd = f.inode
+	cwdPath = "" // Haxe addition, only known as fs.cwd
 	return nil
 }
 
@@ -818,6 +919,9 @@ func Symlink(path, link string) error {
 }
 
 func Fsync(fd int) error {
+	if hf, err := fdToHostFile(fd); err == nil { // Haxe addition
+		return hf.sync()
+	}
 	return nil
 }
 
@@ -897,6 +1001,18 @@ func fdToFsysFile(fd int) (*fsysFile, error) {
 	return fsysf, nil
 }
 
+func fdToHostFile(fd int) (*hostFile, error) {
+	f, err := fdToFile(fd)
+	if err != nil {
+		return nil, err
+	}
+	hostf, ok := f.impl.(*hostFile)
+	if !ok {
+		return nil, EINVAL
+	}
+	return hostf, nil
+}
+
 // create creates a file in the file system with the given name, mode, time, and data.
 // It is meant to be called when initializing the file system image.
 func create(name string, mode uint32, sec int64, data []byte) error {
@@ -922,3 +1038,61 @@ func create(name string, mode uint32, sec int64, data []byte) error {
 	}
 	return nil
 }
+
+// Haxe additions for files which are only read from a zip file or resource when they are first used,
+// see MountZip and fsinit.
+
+// loadData reads the data of a file into memory, after which it behaves like any other file.
+func (fs *fsys) loadData(ip *inode) {
+	ip.data = ip.load()
+	ip.load = nil
+	ip.Size = int64(len(ip.data))
+}
+
+// createLazy is like create, but the data of the file is only read by load when it is first used.
+// If size is -1, the size is not known without loading the data, so Stat gives 0 until the file is opened,
+// rather than loading every file of a directory tree that is walked.
+func createLazy(name string, mode uint32, sec int64, size int64, load func() []byte) error {
+	if i := len(name) - 1; i > 0 && name[0] == '/' {
+		for i > 0 && name[i] != '/' {
+			i--
+		}
+		fs.mu.Lock()
+		err := fs.mkdirAll(name[:i])
+		fs.mu.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+	if err := create(name, mode, sec, nil); err != nil {
+		return err
+	}
+	if mode&S_IFMT == S_IFREG {
+		fs.mu.Lock()
+		defer fs.mu.Unlock()
+		ip, _, err := fs.namei(name, false)
+		if err != nil {
+			return err
+		}
+		ip.Size = size
+		ip.load = load
+	}
+	return nil
+}
+
+// mkdirAll creates the directory path, and any directories above it, if they do not already exist.
+func (fs *fsys) mkdirAll(path string) error {
+	for i := 1; i <= len(path); i++ {
+		if i == len(path) || path[i] == '/' {
+			ip, _, err := fs.namei(path[:i], false)
+			if err != nil {
+				if _, err = fs.open(path[:i], O_CREATE|O_EXCL, 0777|S_IFDIR); err != nil {
+					return err
+				}
+			} else if ip.Mode&S_IFMT != S_IFDIR {
+				return ENOTDIR
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+// Haxe-specific persistent file storage for js running in a browser, using IndexedDB or, if that is not available, localStorage.
+//
+// The store is mounted into the simulated file system in the same way as a host directory, see fs_host_haxe.go,
+// so a file is read from the store when it is opened and written through to it when it is closed or synced.
+// The paths of the files in the store are those in the simulated file system, so a store should always be mounted at the same place.
+//
+// If the program is compiled with the Haxe flag "-D gostore=/dir", the store is loaded before the program starts
+// and fsinit mounts it at /dir. Otherwise StoreMount loads it, but as loading from IndexedDB is asynchronous
+// it can only do so when the JS event loop is running, for example by using haxegoruntime.BrowserMain().
+
+package syscall
+
+import (
+	"runtime"
+
+	"github.com/tardisgo/tardisgo/haxe/hx"
+)
+
+func storeAvailable() bool {
+	return hx.CallBool("js", "BrowserStore.available", 0)
+}
+
+// StoreMount makes the persistent browser store available at path, creating path if required.
+// It returns ENOSYS if the target is not js running in a browser,
+// and EAGAIN if the store cannot be loaded because the JS event loop is not running.
+// This function is a Haxe addition, it does not exist in the standard syscall package.
+func StoreMount(path string) error {
+	if !storeAvailable() {
+		return ENOSYS
+	}
+	if len(path) == 0 || path[0] != '/' {
+		return EINVAL
+	}
+	path = cleanPath(path)
+	if !hx.CallBool("js", "BrowserStore.ready", 0) {
+		hx.Call("js", "BrowserStore.start", 0)
+		for !hx.CallBool("js", "BrowserStore.ready", 0) {
+			if !hx.GetBool("js", "BrowserStore.eventLoop") {
+				return EAGAIN
+			}
+			runtime.Gosched()
+		}
+	}
+	var st Stat_t
+	if hostStat(path, &st) != nil && !hx.CallBool("", "HostFS.createDir", 1, path) {
+		return EIO
+	}
+	return hostMountAt(path, path)
+}
+
+// storeFsinit mounts the store if the program was compiled with "-D gostore=/dir", returning a log of what was done.
+func storeFsinit() string {
+	dir := hx.CodeString("js && gostore", `haxe.macro.Compiler.getDefine("gostore");`)
+	if dir == "" {
+		return ""
+	}
+	if err := StoreMount(dir); err != nil {
+		return "syscall.fsinit() unable to mount the browser store at " + dir + " : " + err.Error() + "\n"
+	}
+	return "syscall.fsinit() mounted the browser store at: " + dir + "\n"
+}
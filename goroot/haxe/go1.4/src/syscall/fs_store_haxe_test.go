@@ -0,0 +1,144 @@
+// Tests of the localStorage fallback of the BrowserStore Haxe class, used by fs_store_haxe.go,
+// with a stub window.localStorage and no IndexedDB. They only run under node, as in a browser the stub would hide the real window.
+
+package syscall
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tardisgo/tardisgo/haxe/hx"
+)
+
+// stubWindow is JavaScript that makes a window whose localStorage keeps its items in memory, in the order they were first set.
+const stubWindow = `global.window = {localStorage: (function() {
+	var items = {}, keys = [];
+	return {
+		get length() { return keys.length; },
+		key: function(i) { return i < keys.length ? keys[i] : null; },
+		getItem: function(k) { return items.hasOwnProperty(k) ? items[k] : null; },
+		setItem: function(k, v) { if (!items.hasOwnProperty(k)) keys.push(k); items[k] = String(v); },
+		removeItem: function(k) { if (items.hasOwnProperty(k)) { delete items[k]; keys.splice(keys.indexOf(k), 1); } }
+	};
+})()};`
+
+// resetStore forgets what BrowserStore has loaded, so that the next BrowserStore.start loads it again.
+func resetStore() {
+	hx.Code("js", "@:privateAccess BrowserStore.state=0;")
+	hx.Code("js", "@:privateAccess BrowserStore.files=new Map();")
+	hx.Code("js", "@:privateAccess BrowserStore.db=null;")
+	hx.Code("js", "@:privateAccess BrowserStore.ls=null;")
+}
+
+// startStore loads BrowserStore from the stub, which it does at once as there is no IndexedDB.
+func startStore(t *testing.T) {
+	resetStore()
+	if !hx.CallBool("js", "BrowserStore.available", 0) {
+		t.Fatal("BrowserStore is not available with the stub window")
+	}
+	hx.Call("js", "BrowserStore.start", 0)
+	if !hx.CallBool("js", "BrowserStore.ready", 0) {
+		t.Fatal("BrowserStore is not ready after loading from the stub localStorage")
+	}
+}
+
+// storeItem returns the stub localStorage item with the key k, or "null" if there is none.
+func storeItem(k string) string {
+	return hx.CodeString("js", "untyped window.localStorage.getItem({0})+'';", k)
+}
+
+func setStoreItem(k, v string) {
+	hx.Code("js", "untyped window.localStorage.setItem({0},{1});", k, v)
+}
+
+// readStore returns the contents of the file p in BrowserStore, which has the given size.
+func readStore(t *testing.T, p string, size int) string {
+	if !hx.CallBool("js", "BrowserStore.stat", 1, p) || hx.GetBool("js", "HostFS.statIsDir") {
+		t.Fatalf("%s is not a file in the store", p)
+	}
+	if got := int(hx.GetFloat("js", "HostFS.statSize")); got != size {
+		t.Errorf("the size of %s is %d, want %d", p, got, size)
+	}
+	buf := make([]byte, size+10)
+	n := hx.CallInt("js", "BrowserStore.readInto", 2, p, buf)
+	if n != size {
+		t.Fatalf("read %d bytes from %s, want %d", n, p, size)
+	}
+	return string(buf[:n])
+}
+
+func TestBrowserStoreLocal(t *testing.T) {
+	if !hx.CodeBool("js", "true;") {
+		t.Skip("BrowserStore is only used by js")
+	}
+	if hx.CodeBool("js", "js.Lib.eval({0});", "typeof window!=='undefined'") {
+		t.Skip("the stub localStorage would hide the real one")
+	}
+	hx.Code("js", "js.Lib.eval({0});", stubWindow)
+	defer func() {
+		resetStore()
+		hx.Code("js", "js.Lib.eval({0});", "delete global.window;")
+	}()
+
+	setStoreItem("tardisgo:/dir", "D1000\n")
+	setStoreItem("tardisgo:/dir/old.txt", "F1000\nold")
+	setStoreItem("other", "F1000\nnot in the store")
+	startStore(t)
+
+	if got := readStore(t, "/dir/old.txt", 3); got != "old" {
+		t.Errorf("read %q from the loaded /dir/old.txt, want %q", got, "old")
+	}
+	if mtime := hx.GetFloat("js", "HostFS.statMtime"); mtime != 1000 {
+		t.Errorf("the loaded mtime of /dir/old.txt is %v, want 1000", mtime)
+	}
+	if !hx.CallBool("js", "BrowserStore.stat", 1, "/dir") || !hx.GetBool("js", "HostFS.statIsDir") {
+		t.Error("the loaded /dir is not a directory")
+	}
+	if hx.CallBool("js", "BrowserStore.stat", 1, "other") {
+		t.Error("an item without the key prefix was loaded")
+	}
+
+	bin := "bin\x00\x7f\x80\xff"
+	if !hx.CallBool("js", "BrowserStore.saveBytes", 2, "/dir/bin", []byte(bin)) {
+		t.Fatal("saveBytes failed")
+	}
+	if !hx.CallBool("js", "BrowserStore.saveBytes", 2, "/dir/new.txt", []byte("new")) {
+		t.Fatal("saveBytes failed")
+	}
+	if v := storeItem("tardisgo:/dir/new.txt"); !strings.HasPrefix(v, "F") || !strings.HasSuffix(v, "\nnew") {
+		t.Errorf("saveBytes stored %q, want F, the mtime, a newline and the data", v)
+	}
+	if !hx.CallBool("js", "BrowserStore.createDir", 1, "/sub") || storeItem("tardisgo:/sub")[0] != 'D' {
+		t.Errorf("createDir stored %q, want a directory", storeItem("tardisgo:/sub"))
+	}
+	if !hx.CallBool("js", "BrowserStore.rename", 2, "/dir", "/sub/moved") {
+		t.Fatal("rename of a directory failed")
+	}
+	if v := storeItem("tardisgo:/dir/old.txt"); v != "null" {
+		t.Errorf("after the rename, the old item is %q", v)
+	}
+	if v := storeItem("tardisgo:/sub/moved/old.txt"); v != "F1000\nold" {
+		t.Errorf("after the rename, the moved item is %q, want it unchanged", v)
+	}
+	if !hx.CallBool("js", "BrowserStore.deleteFile", 1, "/sub/moved/new.txt") || storeItem("tardisgo:/sub/moved/new.txt") != "null" {
+		t.Error("deleteFile did not remove the item")
+	}
+	if hx.CallBool("js", "BrowserStore.deleteDir", 1, "/sub/moved") {
+		t.Error("deleteDir removed a directory that is not empty")
+	}
+
+	startStore(t) // load again what was stored
+	if got := readStore(t, "/sub/moved/bin", len(bin)); got != bin {
+		t.Errorf("read %q from the stored binary file, want %q", got, bin)
+	}
+	if got := readStore(t, "/sub/moved/old.txt", 3); got != "old" {
+		t.Errorf("read %q from the renamed file, want %q", got, "old")
+	}
+	names := strings.Split(hx.CallString("js", "BrowserStore.readDir", 1, "/sub/moved"), "/")
+	if len(names) != 2 || (names[0] != "bin" && names[1] != "bin") || (names[0] != "old.txt" && names[1] != "old.txt") {
+		t.Errorf("after loading again, /sub/moved holds %q, want bin and old.txt", names)
+	}
+	if hx.CallBool("js", "BrowserStore.stat", 1, "/sub/moved/new.txt") || hx.CallBool("js", "BrowserStore.stat", 1, "/dir") {
+		t.Error("a deleted or renamed path was loaded again")
+	}
+}
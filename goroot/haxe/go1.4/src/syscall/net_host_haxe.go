@@ -0,0 +1,363 @@
+// Haxe-specific access to the host network, for those targets that have one (cpp, cs, java, neko and js under node).
+//
+// When the host network is available, AF_INET sockets are host sockets rather than simulated ones,
+// so the netFile methods in net_nacl_haxe.go call the host versions below.
+// The HostNet Haxe class never blocks, so a goroutine waiting for a socket parks on a channel,
+// which HostNet sends a value to when something happens to the socket, so the rest of the program runs meanwhile.
+// Under node, the network events are only delivered when the JS event loop runs,
+// so the program must give it control regularly, for example by using haxegoruntime.BrowserMain().
+
+package syscall
+
+import "github.com/tardisgo/tardisgo/haxe/hx"
+
+const (
+	hostRetry = -1 // HostNet result: not ready yet, try again later
+	hostError = -2 // HostNet result: the operation failed
+)
+
+var hostNetChecked, hostNetOK bool
+
+func hostNetAvailable() bool {
+	if !hostNetChecked {
+		hostNetChecked = true
+		hostNetOK = hx.CallBool("", "HostNet.available", 0)
+	}
+	return hostNetOK
+}
+
+// hostWait parks the goroutine until HostNet reports a change to the host socket, or the deadline passes,
+// when it returns EAGAIN.
+func (f *netFile) hostWait(deadline int64) error {
+	if past(deadline) {
+		return EAGAIN
+	}
+	wake := make(chan bool, 1) // one for each wait, as a reader and a writer may be waiting on the same socket
+	hx.Call("", "HostNet.addWaiter", 2, f.hostH, wake)
+	var t hostTimer
+	t.start(wake, deadline)
+	<-wake
+	t.stop()
+	if past(deadline) {
+		return EAGAIN
+	}
+	return nil
+}
+
+// A hostTimer wakes a goroutine parked in hostWait when its deadline passes.
+type hostTimer struct {
+	wake chan bool
+	r    runtimeTimer
+}
+
+func (t *hostTimer) start(wake chan bool, deadline int64) {
+	if deadline == 0 {
+		return
+	}
+	t.wake = wake
+	t.r.when = deadline
+	t.r.f = hostTimerExpired
+	t.r.arg = t
+	startTimer(&t.r)
+}
+
+func (t *hostTimer) stop() {
+	if t.wake != nil {
+		stopTimer(&t.r)
+	}
+}
+
+func hostTimerExpired(i interface{}, seq uintptr) {
+	select {
+	case i.(*hostTimer).wake <- true:
+	default: // already woken
+	}
+}
+
+// hostAddr returns the host name and port for sa, which must be a *SockaddrInet4.
+func hostAddr(sa Sockaddr) (string, int, error) {
+	sa4, ok := sa.(*SockaddrInet4)
+	if !ok {
+		return "", 0, EINVAL
+	}
+	a := sa4.Addr
+	return itoa(int(a[0])) + "." + itoa(int(a[1])) + "." + itoa(int(a[2])) + "." + itoa(int(a[3])), sa4.Port, nil
+}
+
+// hostSockaddr returns the Sockaddr for the host name and port, which may be an IPv4-mapped IPv6 address.
+func hostSockaddr(host string, port int) Sockaddr {
+	for i := len(host) - 1; i >= 0; i-- {
+		if host[i] == ':' { // as in "::ffff:127.0.0.1"
+			host = host[i+1:]
+			break
+		}
+	}
+	sa := &SockaddrInet4{Port: port}
+	b, n := 0, 0
+	for i := 0; i <= len(host); i++ {
+		if i == len(host) || host[i] == '.' {
+			if n < 4 {
+				sa.Addr[n] = byte(b)
+			}
+			b = 0
+			n++
+		} else if host[i] >= '0' && host[i] <= '9' {
+			b = b*10 + int(host[i]-'0')
+		}
+	}
+	return sa
+}
+
+// hostSockname returns the local or remote address of the host socket h.
+func hostSockname(h int, remote bool) Sockaddr {
+	if !hx.CallBool("", "HostNet.getAddr", 2, h, remote) {
+		return nil
+	}
+	return hostSockaddr(hx.GetString("", "HostNet.addrHost"), hx.GetInt("", "HostNet.addrPort"))
+}
+
+// hostReady waits for the host socket to be connected, listening or bound.
+func (f *netFile) hostReady(deadline int64) error {
+	for {
+		switch hx.CallInt("", "HostNet.status", 1, f.hostH) {
+		case hostRetry:
+			if err := f.hostWait(deadline); err != nil {
+				return err
+			}
+			if f.hostH < 0 {
+				return EINVAL // closed while waiting
+			}
+		case hostError:
+			return ECONNREFUSED
+		default:
+			return nil
+		}
+	}
+}
+
+// Host versions of the netFile methods.
+
+func (f *netFile) hostBind(sa Sockaddr) error {
+	if f.addr != nil {
+		return EISCONN
+	}
+	if sa == nil {
+		sa = &SockaddrInet4{}
+	}
+	host, port, err := hostAddr(sa)
+	if err != nil {
+		return err
+	}
+	f.addr = sa.copy()
+	if f.sotype == SOCK_DGRAM {
+		f.hostH = hx.CallInt("", "HostNet.udpBind", 2, host, port)
+		if f.hostH < 0 {
+			f.addr = nil
+			return EPROTONOSUPPORT
+		}
+		if err := f.hostReady(0); err != nil {
+			f.hostClose()
+			f.addr = nil
+			return EADDRINUSE
+		}
+		f.addr = hostSockname(f.hostH, false)
+	}
+	return nil
+}
+
+func (f *netFile) hostListen(backlog int) error {
+	if f.hostH >= 0 {
+		return EINVAL
+	}
+	if f.addr == nil {
+		if err := f.hostBind(nil); err != nil {
+			return err
+		}
+	}
+	host, port, err := hostAddr(f.addr)
+	if err != nil {
+		return err
+	}
+	f.hostH = hx.CallInt("", "HostNet.tcpListen", 3, host, port, backlog)
+	if f.hostH < 0 {
+		return EADDRINUSE
+	}
+	if err := f.hostReady(0); err != nil {
+		f.hostClose()
+		return EADDRINUSE
+	}
+	f.addr = hostSockname(f.hostH, false)
+	return nil
+}
+
+func (f *netFile) hostAccept() (fd int, sa Sockaddr, err error) {
+	for {
+		if f.hostH < 0 {
+			return -1, nil, EAGAIN // closed
+		}
+		switch h := hx.CallInt("", "HostNet.accept", 1, f.hostH); h {
+		case hostRetry:
+			if err := f.hostWait(f.readDeadline()); err != nil {
+				return -1, nil, err
+			}
+		case hostError:
+			return -1, nil, EINVAL
+		default:
+			newf := &netFile{
+				proto:  f.proto,
+				sotype: f.sotype,
+				onHost: true,
+				hostH:  h,
+				addr:   hostSockname(h, false),
+				raddr:  hostSockname(h, true),
+			}
+			if newf.raddr == nil {
+				newf.raddr = &SockaddrInet4{}
+			}
+			return newFD(newf), newf.raddr.copy(), nil
+		}
+	}
+}
+
+func (f *netFile) hostConnect(sa Sockaddr) error {
+	if sa == nil {
+		return EINVAL
+	}
+	if f.raddr != nil {
+		return EISCONN
+	}
+	if f.sotype == SOCK_DGRAM {
+		if f.hostH < 0 {
+			if err := f.hostBind(nil); err != nil {
+				return err
+			}
+		}
+		f.raddr = sa.copy()
+		return nil
+	}
+	host, port, err := hostAddr(sa)
+	if err != nil {
+		return err
+	}
+	f.hostH = hx.CallInt("", "HostNet.tcpConnect", 2, host, port)
+	if f.hostH < 0 {
+		return ECONNREFUSED
+	}
+	if err := f.hostReady(f.writeDeadline()); err != nil {
+		f.hostClose()
+		return err
+	}
+	f.addr = hostSockname(f.hostH, false)
+	f.raddr = sa.copy()
+	return nil
+}
+
+func (f *netFile) hostRead(b []byte) (int, error) {
+	if f.sotype == SOCK_DGRAM {
+		n, _, err := f.hostRecvfrom(b, 0)
+		return n, err
+	}
+	for {
+		if f.hostH < 0 {
+			if f.raddr != nil {
+				return 0, nil // closed
+			}
+			return 0, ENOTCONN
+		}
+		switch n := hx.CallInt("", "HostNet.read", 2, f.hostH, b); n {
+		case hostRetry:
+			if err := f.hostWait(f.readDeadline()); err != nil {
+				return 0, err
+			}
+		case hostError:
+			return 0, ECONNRESET
+		default:
+			return n, nil
+		}
+	}
+}
+
+func (f *netFile) hostWrite(b []byte) (int, error) {
+	if f.sotype == SOCK_DGRAM {
+		if f.raddr == nil {
+			return 0, ENOTCONN
+		}
+		if err := f.hostSendto(b, 0, f.raddr); err != nil {
+			return 0, err
+		}
+		return len(b), nil
+	}
+	n := 0
+	for n < len(b) {
+		if f.hostH < 0 {
+			return n, EPIPE
+		}
+		switch m := hx.CallInt("", "HostNet.write", 2, f.hostH, b[n:]); m {
+		case hostRetry:
+			if err := f.hostWait(f.writeDeadline()); err != nil {
+				return n, err
+			}
+		case hostError:
+			return n, EPIPE
+		default:
+			n += m
+		}
+	}
+	return n, nil
+}
+
+func (f *netFile) hostRecvfrom(p []byte, flags int) (n int, from Sockaddr, err error) {
+	if f.sotype != SOCK_DGRAM {
+		return 0, nil, EINVAL
+	}
+	for {
+		if f.hostH < 0 {
+			return 0, nil, ENOTCONN
+		}
+		switch n := hx.CallInt("", "HostNet.recvFrom", 2, f.hostH, p); n {
+		case hostRetry:
+			if err := f.hostWait(f.readDeadline()); err != nil {
+				return 0, nil, err
+			}
+		case hostError:
+			return 0, nil, EINVAL
+		default:
+			return n, hostSockaddr(hx.GetString("", "HostNet.addrHost"), hx.GetInt("", "HostNet.addrPort")), nil
+		}
+	}
+}
+
+func (f *netFile) hostSendto(p []byte, flags int, to Sockaddr) error {
+	if f.sotype != SOCK_DGRAM {
+		return EINVAL
+	}
+	if f.hostH < 0 {
+		if err := f.hostBind(nil); err != nil {
+			return err
+		}
+	}
+	host, port, err := hostAddr(to)
+	if err != nil {
+		return err
+	}
+	if hx.CallInt("", "HostNet.sendTo", 4, f.hostH, p, host, port) < 0 {
+		return ECONNREFUSED
+	}
+	return nil
+}
+
+func (f *netFile) hostShutdown(how int) error {
+	if f.hostH < 0 {
+		return ENOTCONN
+	}
+	hx.Call("", "HostNet.shutdown", 3, f.hostH, how != SHUT_WR, how != SHUT_RD)
+	return nil
+}
+
+func (f *netFile) hostClose() error {
+	if f.hostH >= 0 {
+		hx.Call("", "HostNet.close", 1, f.hostH)
+		f.hostH = -1
+	}
+	return nil
+}
@@ -450,6 +450,8 @@ type netFile struct {
 	wrdeadline int64
 	addr       Sockaddr
 	raddr      Sockaddr
+	onHost     bool // Haxe addition: a host socket, see net_host_haxe.go
+	hostH      int  // the HostNet handle of a host socket, or -1 if there is none
 }
 
 // A netAddr is a network address in the global listener map.
@@ -513,6 +515,9 @@ var netprotos = map[int]*netproto{
 // These functions implement the usual BSD socket operations.
 
 func (f *netFile) bind(sa Sockaddr) error {
+	if f.onHost { // Haxe addition
+		return f.hostBind(sa)
+	}
 	if f.addr != nil {
 		return EISCONN
 	}
@@ -532,6 +537,9 @@ func (f *netFile) bind(sa Sockaddr) error {
 }
 
 func (f *netFile) listen(backlog int) error {
+	if f.onHost { // Haxe addition
+		return f.hostListen(backlog)
+	}
 	net.Lock()
 	defer net.Unlock()
 	if f.listener != nil {
@@ -547,6 +555,9 @@ func (f *netFile) listen(backlog int) error {
 }
 
 func (f *netFile) accept() (fd int, sa Sockaddr, err error) {
+	if f.onHost { // Haxe addition
+		return f.hostAccept()
+	}
 	msg, err := f.listener.read(f.readDeadline())
 	if err != nil {
 		return -1, nil, err
@@ -560,6 +571,9 @@ func (f *netFile) accept() (fd int, sa Sockaddr, err error) {
 }
 
 func (f *netFile) connect(sa Sockaddr) error {
+	if f.onHost { // Haxe addition
+		return f.hostConnect(sa)
+	}
 	if past(f.writeDeadline()) {
 		return EAGAIN
 	}
@@ -609,6 +623,9 @@ func (f *netFile) connect(sa Sockaddr) error {
 }
 
 func (f *netFile) read(b []byte) (int, error) {
+	if f.onHost { // Haxe addition
+		return f.hostRead(b)
+	}
 	if f.rd == nil {
 		if f.raddr != nil {
 			n, _, err := f.recvfrom(b, 0)
@@ -620,6 +637,9 @@ func (f *netFile) read(b []byte) (int, error) {
 }
 
 func (f *netFile) write(b []byte) (int, error) {
+	if f.onHost { // Haxe addition
+		return f.hostWrite(b)
+	}
 	if f.wr == nil {
 		if f.raddr != nil {
 			err := f.sendto(b, 0, f.raddr)
@@ -640,6 +660,9 @@ type pktmsg struct {
 }
 
 func (f *netFile) recvfrom(p []byte, flags int) (n int, from Sockaddr, err error) {
+	if f.onHost { // Haxe addition
+		return f.hostRecvfrom(p, flags)
+	}
 	if f.sotype != SOCK_DGRAM {
 		return 0, nil, EINVAL
 	}
@@ -658,6 +681,9 @@ func (f *netFile) recvfrom(p []byte, flags int) (n int, from Sockaddr, err error
 }
 
 func (f *netFile) sendto(p []byte, flags int, to Sockaddr) error {
+	if f.onHost { // Haxe addition
+		return f.hostSendto(p, flags, to)
+	}
 	if f.sotype != SOCK_DGRAM {
 		return EINVAL
 	}
@@ -688,6 +714,9 @@ func (f *netFile) sendto(p []byte, flags int, to Sockaddr) error {
 }
 
 func (f *netFile) close() error {
+	if f.onHost { // Haxe addition
+		return f.hostClose()
+	}
 	if f.listener != nil {
 		f.listener.close()
 	}
@@ -727,6 +756,8 @@ func Socket(proto, sotype, unused int) (fd int, err error) {
 	f := &netFile{
 		proto:  p,
 		sotype: sotype,
+		onHost: p == netprotoAF_INET && hostNetAvailable(), // Haxe addition
+		hostH:  -1,
 	}
 	return newFD(f), nil
 }
@@ -901,6 +932,9 @@ func Shutdown(fd int, how int) error {
 	if err != nil {
 		return err
 	}
+	if f.onHost { // Haxe addition
+		return f.hostShutdown(how)
+	}
 	switch how {
 	case SHUT_RD:
 		f.rd.close()
@@ -0,0 +1,59 @@
+// Haxe-specific standard input, output and error, which use the host streams where the target has them.
+//
+// The naclFile implementation of fds 0, 1 and 2 calls the functions below, using the HostStdio Haxe class.
+// Output to stdout is buffered by HostStdio, so it is flushed before stdin is read, and when the program exits.
+// Reading stdin does not block the rest of the program, as a goroutine waiting for input lets the others run.
+// On the sys targets a thread reads stdin, while under node events are used if the JS event loop is running,
+// otherwise the read blocks the whole program. In a browser, stdin is always empty.
+
+package syscall
+
+import (
+	"runtime"
+
+	"github.com/tardisgo/tardisgo/haxe/hx"
+)
+
+// stdioRead reads from the host stdin.
+func stdioRead(b []byte) (int, error) {
+	for {
+		switch n := hx.CallInt("", "HostStdio.read", 1, b); n {
+		case hostRetry:
+			runtime.Gosched()
+		case hostError:
+			return 0, EIO
+		default:
+			return n, nil
+		}
+	}
+}
+
+// stdioWrite writes to the host stdout (fd 1) or stderr (fd 2).
+func stdioWrite(fd int, b []byte) int {
+	return hx.CallInt("", "HostStdio.write", 2, fd, b)
+}
+
+// stdioFstat describes a standard stream as a character device if it is a terminal, or otherwise as a pipe.
+func stdioFstat(fd int, st *Stat_t) error {
+	*st = Stat_t{
+		Ino:     uint64(fd),
+		Mode:    S_IFIFO | 0600,
+		Nlink:   1,
+		Blksize: 4096,
+	}
+	if hx.CallBool("", "HostStdio.isatty", 1, fd) {
+		st.Mode = S_IFCHR | 0620
+	}
+	return nil
+}
+
+// Isatty returns true if fd is, or is a duplicate of, a standard stream that is connected to a terminal.
+// This function is a Haxe addition, it does not exist in the standard syscall package.
+func Isatty(fd int) bool {
+	f, err := fdToFile(fd)
+	if err != nil {
+		return false
+	}
+	nf, ok := f.impl.(*naclFile)
+	return ok && hx.CallBool("", "HostStdio.isatty", 1, nf.naclFD)
+}
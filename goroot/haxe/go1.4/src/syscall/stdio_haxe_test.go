@@ -0,0 +1,47 @@
+// Tests of the standard streams in stdio_haxe.go, which give the same results whether or not they are terminals.
+
+package syscall
+
+import "testing"
+
+func TestStdioFstat(t *testing.T) {
+	for fd := 0; fd <= 2; fd++ {
+		var st Stat_t
+		if err := Fstat(fd, &st); err != nil {
+			t.Fatalf("Fstat(%d): %v", fd, err)
+		}
+		want := uint32(S_IFIFO | 0600)
+		if Isatty(fd) {
+			want = S_IFCHR | 0620
+		}
+		if st.Mode != want {
+			t.Errorf("Fstat(%d) gives mode %o, want %o as Isatty(%d) is %v", fd, st.Mode, want, fd, Isatty(fd))
+		}
+		if _, err := Seek(fd, 0, 0); err != ESPIPE {
+			t.Errorf("Seek(%d, 0, 0) returned %v, want ESPIPE", fd, err)
+		}
+	}
+}
+
+func TestIsatty(t *testing.T) {
+	fd, err := Dup(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer Close(fd)
+	if Isatty(fd) != Isatty(1) {
+		t.Errorf("Isatty(Dup(1)) = %v, want %v as for fd 1", Isatty(fd), Isatty(1))
+	}
+	fsinit()
+	fd, err = Open("/isatty.txt", O_RDWR|O_CREATE, 0666)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer Close(fd)
+	if Isatty(fd) {
+		t.Error("Isatty of a file in memory is true")
+	}
+	if Isatty(-1) || Isatty(1000) {
+		t.Error("Isatty of a bad fd is true")
+	}
+}
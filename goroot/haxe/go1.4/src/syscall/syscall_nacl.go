@@ -237,12 +237,18 @@ type Stat_t struct {
 
 var ForkLock sync.RWMutex
 
+// Haxe change: a WaitStatus from a host process holds the exit code<<8, or the number of the signal that ended it.
 type WaitStatus uint32
 
-func (w WaitStatus) Exited() bool       { return false }
-func (w WaitStatus) ExitStatus() int    { return 0 }
-func (w WaitStatus) Signaled() bool     { return false }
-func (w WaitStatus) Signal() Signal     { return 0 }
+func (w WaitStatus) Exited() bool { return w&0x7f == 0 }
+func (w WaitStatus) ExitStatus() int {
+	if !w.Exited() {
+		return -1
+	}
+	return int(w>>8) & 0xff
+}
+func (w WaitStatus) Signaled() bool     { return w&0x7f != 0 }
+func (w WaitStatus) Signal() Signal     { return Signal(w & 0x7f) }
 func (w WaitStatus) CoreDump() bool     { return false }
 func (w WaitStatus) Stopped() bool      { return false }
 func (w WaitStatus) Continued() bool    { return false }
@@ -295,15 +301,15 @@ func Getpagesize() int                  { return 65536 }
 func Getppid() int                      { return 2 }
 func Getpid() int                       { return 3 }
 func Getuid() int                       { return 1 }
-func Kill(pid int, signum Signal) error { return ENOSYS }
+func Kill(pid int, signum Signal) error { return hostKill(pid, signum) } // Haxe change
 func Sendfile(outfd int, infd int, offset *int64, count int) (written int, err error) {
 	return 0, ENOSYS
 }
 func StartProcess(argv0 string, argv []string, attr *ProcAttr) (pid int, handle uintptr, err error) {
-	return 0, 0, ENOSYS
+	return hostStartProcess(argv0, argv, attr) // Haxe change
 }
 func Wait4(pid int, wstatus *WaitStatus, options int, rusage *Rusage) (wpid int, err error) {
-	return 0, ENOSYS
+	return hostWait4(pid, wstatus) // Haxe change
 }
 func RouteRIB(facility, param int) ([]byte, error)                { return nil, ENOSYS }
 func ParseRoutingMessage(b []byte) ([]RoutingMessage, error)      { return nil, ENOSYS }
@@ -527,10 +527,11 @@ func zget2(b string) int {
 	return int(b[0]) | int(b[1])<<8
 }
 
+// UnzipFS mounts the zip file resource nam at the root of the file system, see MountZip.
 func UnzipFS(nam string) {
 	log := "syscall.UnzipFS() unzip file: " + nam + "\n"
 	if len(nam) > 5 && nam[len(nam)-4:] == ".zip" {
-		log += unzip(string(hx.Resource(nam)))
+		log += unzip("/", string(hx.Resource(nam)))
 	} else {
 		log += "syscall.UnzipFS() not a .zip file - ignored\n"
 	}
@@ -551,7 +552,32 @@ func UnzipFS(nam string) {
 	}
 }
 
-func unzip(data string) string {
+// MountZip makes the files in the zip file resource nam available below dir, which is created if required.
+// Only the zip directory is read at this point, each file is uncompressed when it is first opened.
+// The files may then be changed, as they are held in the in-memory file system,
+// but those changes are not written back to the zip file.
+// This function is a Haxe addition, it does not exist in the standard syscall package.
+func MountZip(dir, nam string) error {
+	if len(dir) == 0 || dir[0] != '/' {
+		return EINVAL
+	}
+	dir = cleanPath(dir)
+	data := string(hx.Resource(nam))
+	if len(data) < 22 {
+		return ENOENT
+	}
+	fs.mu.Lock()
+	err := fs.mkdirAll(dir)
+	fs.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	unzip(dir, data)
+	return nil
+}
+
+// unzip adds the files in the zip file data below dir, returning a log of what was done.
+func unzip(dir, data string) string {
 	log := ""
 	msg := ""
 	const (
@@ -641,22 +667,20 @@ func unzip(data string) string {
 
 		off += zheadersize + namelen + xlen
 
-		var fdata []byte
-		switch meth {
-		case 0:
-			// buf is uncompressed
-			buf = data[off : off+size]
-			fdata = []byte(buf)
-		case 8:
-			// buf is deflate-compressed
-			buf = data[off : off+csize]
-			fdata = inflate(buf)
-			if len(fdata) != size {
-				msg = "fs unzip: inconsistent size in zip file\n"
-				println(msg)
-				log += msg
-				return log
+		load := func() []byte { // Haxe change: only uncompress the file when it is first opened
+			switch meth {
+			case 0:
+				// buf is uncompressed
+				return []byte(data[off : off+size])
+			case 8:
+				// buf is deflate-compressed
+				fdata := inflate(data[off : off+csize])
+				if len(fdata) != size {
+					println("fs unzip: inconsistent size in zip file for " + name)
+				}
+				return fdata
 			}
+			return nil
 		}
 
 		if xattr&S_IFMT == 0 {
@@ -674,7 +698,10 @@ func unzip(data string) string {
 		msg = "syscall.unzip() file: " + name + "\n"
 		println(msg)
 		log += msg
-		if err := create(name, xattr, zipToTime(mdate, mtime), fdata); err != nil {
+		if dir != "/" {
+			name = dir + "/" + name
+		}
+		if err := createLazy(name, xattr, zipToTime(mdate, mtime), int64(size), load); err != nil {
 			msg = "fs unzip: create " + name + " : " + err.Error() + "\n"
 			println(msg)
 			log += msg
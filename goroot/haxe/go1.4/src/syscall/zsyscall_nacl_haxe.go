@@ -25,12 +25,7 @@ func Exit(code int) (err error) {
 	//if e1 != 0 {
 	//	err = e1
 	//}
-	hx.Call("(cpp || cs || java || macro || neko || php || python)", "Sys.exit", 1, code)
-	if code == 0 {
-		hx.Code("js", "untyped __js__('process.exit(0)');") // only works on Node
-	} else { // all non-zero values return as 1
-		hx.Code("js", "untyped __js__('process.exit(1)');") // only works on Node
-	}
+	hx.Call("", "HostOS.exit", 1, code) // flushes stdout first, only returns if the target cannot exit, as in a browser
 	panic("syscall.Exit(" + hx.CallString("", "Std.string", 1, code) + ")")
 	return
 }
@@ -42,6 +37,9 @@ func naclFstat(fd int, stat *Stat_t) (err error) {
 	//if e1 != 0 {
 	//	err = e1
 	//}
+	if fd >= 0 && fd <= 2 {
+		return stdioFstat(fd, stat)
+	}
 	panic("syscall.naclFstat(" + hx.CallString("", "Std.string", 1, fd) + ")")
 	return
 }
@@ -61,8 +59,7 @@ func naclRead(fd int, b []byte) (n int, err error) {
 	//	err = e1
 	//}
 	if fd == 0 {
-		panic("syscall.naclRead(stdin," + hx.CallString("", "Std.string", 1, len(b)) + ")")
-		return
+		return stdioRead(b)
 	}
 	panic("syscall.naclRead(" + hx.CallString("", "Std.string", 1, fd) + "," +
 		hx.CallString("", "Std.string", 1, len(b)) + ")")
@@ -76,6 +73,9 @@ func naclSeek(fd int, off *int64, whence int) (err error) {
 	//if e1 != 0 {
 	//	err = e1
 	//}
+	if fd >= 0 && fd <= 2 {
+		return ESPIPE
+	}
 	panic("syscall.naclSeek(" + hx.CallString("", "Std.string", 1, fd) + ")")
 	return
 }
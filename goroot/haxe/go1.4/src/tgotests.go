@@ -54,20 +54,24 @@ var allList = []string{
 }
 
 var js1 = "" // "crypto/x509" //runtime very long at 30+ mins
+// reflect is tested per target, as its map and slice support is implemented separately for each
 var js = ` archive/tar 
  debug/elf go/doc  
+ reflect 
 `
 
 var cs = ` 
  debug/elf   
+ reflect 
 `
 
 var cpp = ` 
   archive/tar 
   go/doc       
+  reflect 
 `
 
-var java = ` archive/tar debug/elf 
+var java = ` archive/tar debug/elf reflect 
 `
 
 func pkgList(jumble string) []string {
@@ -0,0 +1,30 @@
+// Copyright 2014 Elliott Stoneham and The TARDIS Go Authors
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// +build haxe
+
+package time
+
+var (
+	OffsetName     = offsetName
+	OffsetLocation = offsetLocation
+	RuntimeNano    = runtimeNano
+)
+
+// ZoneChange is a change of the zone of a Location, for the tests of offsetLocation.
+type ZoneChange struct {
+	When   int64
+	Name   string
+	Offset int
+}
+
+// ZoneChanges returns the changes of zone of l, in time order.
+func ZoneChanges(l *Location) []ZoneChange {
+	var zcs []ZoneChange
+	for _, tx := range l.tx {
+		z := l.zone[tx.index]
+		zcs = append(zcs, ZoneChange{tx.when, z.name, z.offset})
+	}
+	return zcs
+}
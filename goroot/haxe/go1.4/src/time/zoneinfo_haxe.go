@@ -45,6 +45,11 @@ var zoneDirs = []string{
 	"/usr/share/zoneinfo/",
 	"/usr/share/lib/zoneinfo/",
 	"/usr/lib/locale/TZ/",
+	// Haxe addition: the same directories in the host file system, then zoneinfo.zip if added as a resource
+	"/host/usr/share/zoneinfo/",
+	"/host/usr/share/lib/zoneinfo/",
+	"/host/usr/lib/locale/TZ/",
+	"/zoneinfo.zip",
 	runtime.GOROOT() + "/lib/time/zoneinfo.zip",
 	// for testing only
 	"/testdata/zoneinfo.zip",
@@ -68,26 +73,115 @@ func initLocal() {
 	// $TZ="" means use UTC.
 	// $TZ="foo" means use /usr/share/zoneinfo/foo.
 
-	
-		tz, ok := syscall.Getenv("TZ") 
-				switch {
-		case !ok:
-			z, err := loadZoneFile("", "/etc/localtime")
+	tz, ok := syscall.Getenv("TZ")
+	switch {
+	case !ok:
+		// Haxe change: also try the host /etc/localtime, then the name of the host time zone,
+		// then the offsets that the host gives for local time
+		for _, f := range []string{"/etc/localtime", "/host/etc/localtime"} {
+			z, err := loadZoneFile("", f)
 			if err == nil {
 				localLoc = *z
 				localLoc.name = "Local"
 				return
 			}
-		case tz != "" && tz != "UTC":
-			if z, err := loadLocation(tz); err == nil {
+		}
+		if name := hx.CallString("", "HostClock.zoneName", 0); name != "" {
+			if z, err := loadLocation(name); err == nil {
 				localLoc = *z
+				localLoc.name = "Local"
 				return
 			}
 		}
-	
+		if z := hostLocation(); z != nil {
+			localLoc = *z
+			return
+		}
+	case tz != "" && tz != "UTC":
+		if z, err := loadLocation(tz); err == nil {
+			localLoc = *z
+			return
+		}
+	}
 
 	// Fall back to UTC.
-		localLoc.name = "UTC"
+	localLoc.name = "UTC"
+}
+
+// hostLocation builds the local Location from the offsets that the host gives for local time.
+// It returns nil if the host cannot give them.
+func hostLocation() *Location {
+	const noOffset = -1000000 // HostClock.noOffset
+	offsetAt := func(sec int64) int {
+		return hx.CallInt("", "HostClock.offsetAt", 1, float64(sec))
+	}
+	sec, _ := now()
+	if offsetAt(sec) == noOffset {
+		return nil
+	}
+	return offsetLocation(sec, offsetAt, func(sec int64) string {
+		return hx.CallString("", "HostClock.zoneAbbr", 1, float64(sec))
+	})
+}
+
+// offsetLocation builds a Location named Local from the offset from UTC, and the zone abbreviation if known,
+// at each second, finding each change between 1970 and 2038 to the second.
+// The standard time is the lower of the offsets near the start and middle of the year of sec.
+func offsetLocation(sec int64, offsetAt func(int64) int, abbr func(int64) string) *Location {
+	const step = 30 * 24 * 60 * 60
+	l := &Location{name: "Local"}
+	std := offsetAt(sec - sec%(365*24*60*60)) // about the start of this year
+	if mid := offsetAt(sec - sec%(365*24*60*60) + 182*24*60*60); mid < std {
+		std = mid // the southern hemisphere
+	}
+	zoneIndex := func(sec int64, offset int) uint8 {
+		name := abbr(sec)
+		if name == "" {
+			name = offsetName(offset)
+		}
+		for i, z := range l.zone {
+			if z.name == name && z.offset == offset {
+				return uint8(i)
+			}
+		}
+		l.zone = append(l.zone, zone{name: name, offset: offset, isDST: offset > std})
+		return uint8(len(l.zone) - 1)
+	}
+
+	from := int64(0)
+	offset := offsetAt(from)
+	l.tx = append(l.tx, zoneTrans{when: alpha, index: zoneIndex(from, offset)})
+	for from < 1<<31-step && len(l.zone) < 255 {
+		to := from + step
+		next := offsetAt(to)
+		if next == offset {
+			from = to
+			continue
+		}
+		for to-from > 1 { // find the second when it changed
+			mid := from + (to-from)/2
+			if offsetAt(mid) == offset {
+				from = mid
+			} else {
+				to = mid
+			}
+		}
+		offset = offsetAt(to)
+		l.tx = append(l.tx, zoneTrans{when: to, index: zoneIndex(to, offset)})
+		from = to
+	}
+	return l
+}
+
+// offsetName gives a zone with no name from the host a name like "+0530".
+func offsetName(offset int) string {
+	sign := byte('+')
+	if offset < 0 {
+		sign = '-'
+		offset = -offset
+	}
+	h, m := offset/3600, offset/60%60
+	return string([]byte{sign, byte('0' + h/10), byte('0' + h%10), byte('0' + m/10), byte('0' + m%10)})
 }
 
 func loadLocation(name string) (*Location, error) {
@@ -0,0 +1,128 @@
+// Copyright 2014 Elliott Stoneham and The TARDIS Go Authors
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// +build haxe
+
+package time_test
+
+import (
+	"testing"
+	. "time"
+)
+
+func TestOffsetName(t *testing.T) {
+	for _, tt := range []struct {
+		offset int
+		name   string
+	}{
+		{0, "+0000"},
+		{3600, "+0100"},
+		{-3600, "-0100"},
+		{5*3600 + 30*60, "+0530"},
+		{-(9*3600 + 30*60), "-0930"},
+		{12*3600 + 45*60, "+1245"},
+		{-12 * 3600, "-1200"},
+		{14 * 3600, "+1400"},
+	} {
+		if got := OffsetName(tt.offset); got != tt.name {
+			t.Errorf("OffsetName(%d) = %q, want %q", tt.offset, got, tt.name)
+		}
+	}
+}
+
+// TestOffsetLocation checks that the Location built from the offsets of Europe/London, as the host would give them,
+// changes zone at the same seconds as Europe/London does from 1970 until 2038.
+func TestOffsetLocation(t *testing.T) {
+	ForceZipFileForTesting(true)
+	defer ForceZipFileForTesting(false)
+	london, err := LoadLocation("Europe/London")
+	if err != nil {
+		t.Fatal(err)
+	}
+	offsetAt := func(sec int64) int {
+		_, offset := Unix(sec, 0).In(london).Zone()
+		return offset
+	}
+	abbr := func(sec int64) string {
+		name, _ := Unix(sec, 0).In(london).Zone()
+		return name
+	}
+	var want []ZoneChange // the changes of offset after 1970 and before the end of 32-bit time
+	offset := offsetAt(0)
+	for _, zc := range ZoneChanges(london) {
+		if zc.When > 0 && zc.When < 1<<31 && zc.Offset != offset {
+			want = append(want, zc)
+			offset = zc.Offset
+		}
+	}
+	if len(want) < 100 {
+		t.Fatalf("Europe/London has only %d changes of offset from 1970, want over 100", len(want))
+	}
+
+	sec := Date(2015, 3, 1, 0, 0, 0, 0, UTC).Unix()
+	for _, named := range []bool{true, false} {
+		var loc *Location
+		if named {
+			loc = OffsetLocation(sec, offsetAt, abbr)
+		} else {
+			loc = OffsetLocation(sec, offsetAt, func(int64) string { return "" })
+		}
+		got := ZoneChanges(loc)
+		first := ZoneChange{-1 << 63, "BST", 3600} // British Standard Time, from 1968 to 1971
+		if !named {
+			first.Name = "+0100"
+		}
+		if len(got) == 0 || got[0] != first {
+			t.Errorf("named %v: the first zone is %v, want %v", named, got, first)
+			continue
+		}
+		got = got[1:]
+		if len(got) != len(want) {
+			t.Errorf("named %v: %d changes of zone, want %d", named, len(got), len(want))
+		}
+		for i := 0; i < len(got) && i < len(want); i++ {
+			w := want[i]
+			if !named {
+				w.Name = OffsetName(w.Offset)
+			}
+			if got[i] != w {
+				t.Errorf("named %v: change %d at %v is %v, want %v", named, i, Unix(got[i].When, 0).UTC(), got[i], w)
+				break
+			}
+		}
+		for _, tt := range []struct {
+			t      Time
+			name   string
+			offset int
+		}{
+			{Date(2015, 7, 1, 12, 0, 0, 0, UTC), "BST", 3600},
+			{Date(2015, 12, 1, 12, 0, 0, 0, UTC), "GMT", 0},
+			{Date(2015, 3, 29, 0, 59, 59, 0, UTC), "GMT", 0},
+			{Date(2015, 3, 29, 1, 0, 0, 0, UTC), "BST", 3600},
+		} {
+			name, offset := tt.t.In(loc).Zone()
+			if !named {
+				tt.name = OffsetName(tt.offset)
+			}
+			if name != tt.name || offset != tt.offset {
+				t.Errorf("named %v: zone at %v is %s %d, want %s %d", named, tt.t, name, offset, tt.name, tt.offset)
+			}
+		}
+	}
+}
+
+func TestRuntimeNano(t *testing.T) {
+	prev := RuntimeNano()
+	for i := 0; i < 100000; i++ {
+		now := RuntimeNano()
+		if now < prev {
+			t.Fatalf("the runtime clock went back from %d to %d", prev, now)
+		}
+		prev = now
+	}
+	Sleep(10 * Millisecond)
+	if now := RuntimeNano(); now <= prev {
+		t.Errorf("the runtime clock did not advance over a Sleep, from %d to %d", prev, now)
+	}
+}
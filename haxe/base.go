@@ -55,6 +55,7 @@ func (langType) FileStart(haxePackageName, headerText string) string {
 
 // TODO rename
 func (l langType) FileEnd() string {
+	l.exportClasses()      // the facade classes for Go code marked with the pogo.ExportDirective
 	return l.haxeruntime() // this deals with the individual runtime class files
 }
 
@@ -106,6 +107,7 @@ func (l langType) FuncStart(packageName, objectName string, fn *ssa.Function, bl
 	l.hc.fnCanOptMap = canOptMap
 	nullOnExitList := []regToFree{} // names to set to null before we exit the function
 	l.reset1useMap()
+	l.hc.scalarAllocs = make(map[string]*ssa.Alloc)
 
 	if l.PogoComp().DebugFlag {
 		l.hc.reconstructInstrs = nil
@@ -394,11 +396,17 @@ func (l langType) FuncStart(packageName, objectName string, fn *ssa.Function, bl
 						l.hc.pseudoNextReturnAddress--
 					}
 				case *ssa.Alloc:
-					if !in.(*ssa.Alloc).Heap { // allocate space on the stack if possible
+					if a, isScalar := l.scalarAlloc(in); isScalar { // held in individual Haxe variables
+						if reg != "" {
+							reg = strings.TrimSuffix(reg, "inline()") // if there is one
+							l.hc.scalarAllocs[reg] = a
+							ret += l.scalarDecls(a, position)
+						}
+					} else if !in.(*ssa.Alloc).Heap { // allocate space on the stack if possible
 						//fmt.Println("DEBUG allocate stack space for", reg, "at", position)
 						if reg != "" {
 							reg = strings.TrimSuffix(reg, "inline()") // if there is one
-							ret += l.haxeVar(reg+"_stackalloc", "Object", "="+allocNewObject(in.(*ssa.Alloc).Type()), position, "FuncStart()") + "\n"
+							ret += l.haxeVar(reg+"_stackalloc", "Object", "="+l.allocNewObject(in.(*ssa.Alloc).Type()), position, "FuncStart()") + "\n"
 						}
 					}
 				}
@@ -820,13 +828,16 @@ func (l langType) Value(v interface{}, errorInfo string) string {
 	}
 }
 func (l langType) FieldAddr(register string, v interface{}, errorInfo string) string {
+	if a, slot, isScalar := l.PogoComp().ScalarSlot(v); isScalar {
+		return "// " + register + " is " + scalarSlotName(a, slot)
+	}
 	if register != "" {
 		ptr := l.IndirectValue(v.(*ssa.FieldAddr).X, errorInfo)
 		if l.PogoComp().DebugFlag {
 			ptr = "Pointer.check(" + ptr + ")"
 		}
 		fld := v.(*ssa.FieldAddr).X.Type().Underlying().(*types.Pointer).Elem().Underlying().(*types.Struct).Field(v.(*ssa.FieldAddr).Field)
-		off := fieldOffset(v.(*ssa.FieldAddr).X.Type().Underlying().(*types.Pointer).Elem().Underlying().(*types.Struct), v.(*ssa.FieldAddr).Field)
+		off := l.fieldOffset(v.(*ssa.FieldAddr).X.Type().Underlying().(*types.Pointer).Elem().Underlying().(*types.Struct), v.(*ssa.FieldAddr).Field)
 		if off == 0 {
 			if l.is1usePtr(v) {
 				return l.set1usePtr(v.(ssa.Value), oneUsePtr{obj: ptr + ".obj", off: ptr + ".off"}) +
@@ -845,12 +856,37 @@ func (l langType) FieldAddr(register string, v interface{}, errorInfo string) st
 	return ""
 }
 
-func wrapForceToUInt(v string, k types.BasicKind) string {
+// intResult converts the Haxe Int result of a built-in function, such as len(), into the representation of a Go int
+func (l langType) intResult(v string) string {
+	if l.intIs64() {
+		return "GOint64.ofInt(" + v + ")"
+	}
+	return v
+}
+
+// intLangType gives the Haxe type used to hold a Go int
+func (l langType) intLangType() string {
+	if l.intIs64() {
+		return "GOint64"
+	}
+	return "Int"
+}
+
+// intArg converts a Go int into a Haxe Int, for use by the Haxe runtime
+func (l langType) intArg(v string) string {
+	if l.intIs64() {
+		return "GOint64.toInt(" + v + ")"
+	}
+	return v
+}
+
+func (l langType) wrapForceToUInt(v string, k types.BasicKind) string {
+	if l.isGOint64Kind(k) {
+		return "Force.toUint32(GOint64.toInt(" + v + "))"
+	}
 	switch k {
 	case types.Uintptr:
 		return "Force.toUint32(Force.toInt(" + v + "))"
-	case types.Int64, types.Uint64:
-		return "Force.toUint32(GOint64.toInt(" + v + "))"
 	case types.Float32, types.Float64, types.UntypedFloat:
 		return "Force.toUint32(" + v + "<=0?Math.ceil(" + v + "):Math.floor(" + v + "))"
 	}
@@ -861,7 +897,10 @@ func (l langType) IndexAddr(register string, v interface{}, errorInfo string) st
 	if register == "" {
 		return "" // we can't make an address if there is nowhere to put it...
 	}
-	idxString := wrapForceToUInt(l.IndirectValue(v.(*ssa.IndexAddr).Index, errorInfo),
+	if a, slot, isScalar := l.PogoComp().ScalarSlot(v); isScalar {
+		return "// " + register + " is " + scalarSlotName(a, slot)
+	}
+	idxString := l.wrapForceToUInt(l.IndirectValue(v.(*ssa.IndexAddr).Index, errorInfo),
 		v.(*ssa.IndexAddr).Index.(ssa.Value).Type().Underlying().(*types.Basic).Kind())
 	switch v.(*ssa.IndexAddr).X.Type().Underlying().(type) {
 	case *types.Pointer:
@@ -877,7 +916,7 @@ func (l langType) IndexAddr(register string, v interface{}, errorInfo string) st
 			}
 			return fmt.Sprintf(`%s=%s; // .addr(0)`, register, ptr)
 		}
-		idxString += arrayOffsetCalc(ele)
+		idxString += l.arrayOffsetCalc(ele)
 		if l.is1usePtr(v) {
 			return l.set1usePtr(v.(ssa.Value), oneUsePtr{obj: ptr + ".obj", off: "(" + idxString + ")+" + ptr + ".off"}) +
 				"// virtual oneUsePtr " + register + "=" + l.hc.map1usePtr[v.(ssa.Value)].obj + ":" + l.hc.map1usePtr[v.(ssa.Value)].off
@@ -886,6 +925,13 @@ func (l langType) IndexAddr(register string, v interface{}, errorInfo string) st
 	case *types.Slice:
 		x := l.IndirectValue(v.(*ssa.IndexAddr).X, errorInfo)
 		if l.is1usePtr(v) {
+			ele := v.(*ssa.IndexAddr).X.Type().Underlying().(*types.Slice).Elem()
+			if sfx, isTyped := l.typedSliceSuffix(ele); isTyped { // no need for temporary obj & off variables
+				l.hc.map1usePtr[v.(ssa.Value)] = oneUsePtr{
+					obj: x + ".baseArray.obj", off: x + ".itemOff(" + idxString + ")+" + x + ".baseArray.off",
+					slice: x, idx: idxString, sfx: sfx}
+				return "// virtual oneUsePtr " + register + "=" + x + ".getAt" + sfx + "(" + idxString + ")"
+			}
 			return l.set1usePtr(v.(ssa.Value), oneUsePtr{obj: x + ".baseArray.obj", off: x + ".itemOff(" + idxString + ")+" + x + ".baseArray.off"}) +
 				"// virtual oneUsePtr " + register + "=" + l.hc.map1usePtr[v.(ssa.Value)].obj + ":" + l.hc.map1usePtr[v.(ssa.Value)].off
 		}
@@ -909,7 +955,12 @@ func (l langType) intTypeCoersion(t types.Type, v, errorInfo string) string {
 			return "Force.toInt8(" + v + ")"
 		case types.Int16:
 			return "Force.toInt16(" + v + ")"
-		case types.Int32, types.Int: // NOTE type int is always int32
+		case types.Int32:
+			return "Force.toInt32(" + v + ")"
+		case types.Int:
+			if l.intIs64() {
+				return "Force.toInt64(" + v + ")"
+			}
 			return "Force.toInt32(" + v + ")"
 		case types.Int64:
 			return "Force.toInt64(" + v + ")"
@@ -917,7 +968,12 @@ func (l langType) intTypeCoersion(t types.Type, v, errorInfo string) string {
 			return "Force.toUint8(" + v + ")"
 		case types.Uint16:
 			return "Force.toUint16(" + v + ")"
-		case types.Uint32, types.Uint, types.Uintptr: // NOTE type uint is always uint32
+		case types.Uint32, types.Uintptr: // NOTE type uintptr is always held in 32 bits
+			return "Force.toUint32(" + v + ")"
+		case types.Uint:
+			if l.intIs64() {
+				return "Force.toUint64(" + v + ")"
+			}
 			return "Force.toUint32(" + v + ")"
 		case types.Uint64:
 			return "Force.toUint64(" + v + ")"
@@ -940,6 +996,12 @@ func (l langType) intTypeCoersion(t types.Type, v, errorInfo string) string {
 }
 
 func (l langType) Store(v1, v2 interface{}, errorInfo string) string {
+	if a, slot, isScalar := l.PogoComp().ScalarSlot(v1); isScalar {
+		return scalarSlotName(a, slot) + "=" + l.IndirectValue(v2, errorInfo) + ";"
+	}
+	if a, isScalar := l.scalarAlloc(v1); isScalar {
+		return l.scalarStoreAll(a, v2, errorInfo)
+	}
 	ptr := l.IndirectValue(v1, errorInfo)
 	if l.PogoComp().DebugFlag {
 		ptr = "Pointer.check(" + ptr + ")"
@@ -949,11 +1011,15 @@ func (l langType) Store(v1, v2 interface{}, errorInfo string) string {
 		if !found {
 			panic("haxe.Store can't find oneUsePtr " + v1.(ssa.Value).Name() + "=" + v1.(ssa.Value).String())
 		}
-		return oup.obj + ".set" + loadStoreSuffix(v2.(ssa.Value).Type().Underlying(), true) + oup.off + "," +
+		if oup.slice != "" {
+			return oup.slice + ".setAt" + oup.sfx + "(" + oup.idx + "," + l.IndirectValue(v2, errorInfo) + ");" +
+				" /* " + v2.(ssa.Value).Type().Underlying().String() + " */ "
+		}
+		return oup.obj + ".set" + l.loadStoreSuffix(v2.(ssa.Value).Type().Underlying(), true) + oup.off + "," +
 			l.IndirectValue(v2, errorInfo) + ");" +
 			" /* " + v2.(ssa.Value).Type().Underlying().String() + " */ "
 	}
-	return ptr + ".store" + loadStoreSuffix(v2.(ssa.Value).Type().Underlying(), true) +
+	return ptr + ".store" + l.loadStoreSuffix(v2.(ssa.Value).Type().Underlying(), true) +
 		l.IndirectValue(v2, errorInfo) + ");" +
 		" /* " + v2.(ssa.Value).Type().Underlying().String() + " */ "
 }
@@ -1224,16 +1290,16 @@ func (l langType) Call(register string, cc ssa.CallCommon, args []ssa.Value, isB
 			switch args[0].Type().Underlying().(type) {
 			case *types.Chan, *types.Slice:
 				if fnToCall == "len" {
-					return register + "({var _v=" + l.IndirectValue(args[0], errorInfo) + ";_v==null?0:(_v.len());});"
+					return register + l.intResult("({var _v="+l.IndirectValue(args[0], errorInfo)+";_v==null?0:(_v.len());})") + ";"
 				}
 				// cap
-				return register + "({var _v=" + l.IndirectValue(args[0], errorInfo) + ";_v==null?0:(_v.cap());});"
+				return register + l.intResult("({var _v="+l.IndirectValue(args[0], errorInfo)+";_v==null?0:(_v.cap());})") + ";"
 			case *types.Array: // assume len (same as cap anyway)
-				return register + l.IndirectValue(args[0], errorInfo /*, false*/) + ".length;"
+				return register + l.intResult(l.IndirectValue(args[0], errorInfo /*, false*/)+".length") + ";"
 			case *types.Map: // assume len(map)
-				return register + "({var _v=" + l.IndirectValue(args[0], errorInfo) + ";_v==null?0:_v.len();});"
+				return register + l.intResult("({var _v="+l.IndirectValue(args[0], errorInfo)+";_v==null?0:_v.len();})") + ";"
 			case *types.Basic: // assume string as anything else would have produced an error previously
-				return register + "Force.toUTF8length(this._goroutine," + l.IndirectValue(args[0], errorInfo /*, false*/) + ");"
+				return register + l.intResult("Force.toUTF8length(this._goroutine,"+l.IndirectValue(args[0], errorInfo /*, false*/)+")") + ";"
 			default: // TODO handle other types?
 				// TODO error on string?
 				l.PogoComp().LogError(errorInfo, "Haxe", fmt.Errorf("haxe.Call() - unhandled len/cap type: %s",
@@ -1300,7 +1366,7 @@ func (l langType) Call(register string, cc ssa.CallCommon, args []ssa.Value, isB
 				if register != "" {
 					register += "="
 				}
-				return register + l.hxPseudoFuncs(fnToCall, args, errorInfo)
+				return register + l.hxPseudoFuncs(fnToCall, args, cc.Pos(), errorInfo)
 			}
 
 			pn := l.getPackagePath(&cc)
@@ -1668,23 +1734,27 @@ func (l langType) doCall(register string, tuple *types.Tuple, callCode string, u
 	return ret
 }
 
-func allocNewObject(t types.Type) string {
+func (l langType) allocNewObject(t types.Type) string {
 	typ := t.Underlying().(*types.Pointer).Elem().Underlying()
 	switch typ.(type) {
 
 	// this should not be required...
 	case *types.Array:
-		ao := haxeStdSizes.Alignof(typ.(*types.Array).Elem().Underlying())
-		so := haxeStdSizes.Sizeof(typ.(*types.Array).Elem().Underlying())
+		ao := l.hc.sizes.Alignof(typ.(*types.Array).Elem().Underlying())
+		so := l.hc.sizes.Sizeof(typ.(*types.Array).Elem().Underlying())
 		for so%ao != 0 {
 			so++
 		}
+		if kind := l.typedSliceKind(typ.(*types.Array).Elem()); kind != "0" { // so that slices of it use the native storage
+			return fmt.Sprintf("Object.makeKind(%s,%d) /* Array: %s */",
+				kind, typ.(*types.Array).Len()*so, typ.String())
+		}
 		return fmt.Sprintf("Object.make(%d) /* Array: %s */",
 			typ.(*types.Array).Len()*so, typ.String())
 
 	default:
 		return fmt.Sprintf("Object.make(%d) /* %s */",
-			haxeStdSizes.Sizeof(typ),
+			l.hc.sizes.Sizeof(typ),
 			typ.String())
 	}
 }
@@ -1724,36 +1794,41 @@ func (l langType) Alloc(reg string, heap bool, v interface{}, errorInfo string)
 		}
 	*/
 	if heap {
-		return fmt.Sprintf("%s=Pointer.make(%s);", reg, allocNewObject(v.(types.Type)))
+		return fmt.Sprintf("%s=Pointer.make(%s);", reg, l.allocNewObject(v.(types.Type)))
 	}
 	//fmt.Println("DEBUG Alloc on Stack", reg, errorInfo)
 	reg2 := strings.Replace(strings.Replace(reg, "[", "", 1), "]", "", 1) // just in case we're in a big init() and are using a register array
+	if a, isScalar := l.hc.scalarAllocs[reg2]; isScalar {
+		return l.scalarZero(a, errorInfo)
+	}
 	return fmt.Sprintf("%s=Pointer.make(%s_stackalloc.clear());", reg, reg2)
 }
 
 func (l langType) MakeChan(reg string, v interface{}, errorInfo string) string {
 	//typeElem := l.LangType(v.(*ssa.MakeChan).Type().Underlying().(*types.Chan).Elem().Underlying(), false, errorInfo)
-	size := l.IndirectValue(v.(*ssa.MakeChan).Size, errorInfo)
+	size := l.wrapForceToUInt(l.IndirectValue(v.(*ssa.MakeChan).Size, errorInfo),
+		v.(*ssa.MakeChan).Size.Type().Underlying().(*types.Basic).Kind())
 	return reg + "=new Channel(" + size + `);` // <" + typeElem + ">(" + size + `);`
 }
 
-func newSliceCode(typeElem, initElem, capacity, length, errorInfo, itemSize string) string {
+func newSliceCode(typeElem, initElem, capacity, length, errorInfo, itemSize, kind string) string {
 	//return "new Slice(new Pointer(new Make<" + typeElem + ">((" + capacity + ")*(" + itemSize + "))" +
 	//	".array(" + initElem + "," + capacity + ")" +
 	//	"),0," + length + "," + capacity + "," + itemSize + `)`
-	return "new Slice(Pointer.make(Object.make((" + capacity + ")*(" + itemSize + "))" +
+	return "new Slice(Pointer.make(Object.makeKind(" + kind + ",(" + capacity + ")*(" + itemSize + "))" +
 		"),0," + length + "," + capacity + "," + itemSize + `)`
 }
 
 func (l langType) MakeSlice(reg string, v interface{}, errorInfo string) string {
 	typeElem := l.LangType(v.(*ssa.MakeSlice).Type().Underlying().(*types.Slice).Elem().Underlying(), false, errorInfo)
 	initElem := l.LangType(v.(*ssa.MakeSlice).Type().Underlying().(*types.Slice).Elem().Underlying(), true, errorInfo)
-	length := wrapForceToUInt(l.IndirectValue(v.(*ssa.MakeSlice).Len, errorInfo),
+	length := l.wrapForceToUInt(l.IndirectValue(v.(*ssa.MakeSlice).Len, errorInfo),
 		v.(*ssa.MakeSlice).Len.Type().Underlying().(*types.Basic).Kind()) // lengths can't be 64 bit
-	capacity := wrapForceToUInt(l.IndirectValue(v.(*ssa.MakeSlice).Cap, errorInfo),
+	capacity := l.wrapForceToUInt(l.IndirectValue(v.(*ssa.MakeSlice).Cap, errorInfo),
 		v.(*ssa.MakeSlice).Cap.Type().Underlying().(*types.Basic).Kind()) // capacities can't be 64 bit
-	itemSize := "1" + arrayOffsetCalc(v.(*ssa.MakeSlice).Type().Underlying().(*types.Slice).Elem().Underlying())
-	return reg + "=" + newSliceCode(typeElem, initElem, capacity, length, errorInfo, itemSize) + `;`
+	ele := v.(*ssa.MakeSlice).Type().Underlying().(*types.Slice).Elem().Underlying()
+	itemSize := "1" + l.arrayOffsetCalc(ele)
+	return reg + "=" + newSliceCode(typeElem, initElem, capacity, length, errorInfo, itemSize, l.typedSliceKind(ele)) + `;`
 }
 
 // TODO see http://tip.golang.org/doc/go1.2#three_index
@@ -1765,19 +1840,19 @@ func (l langType) Slice(register string, x, lv, hv interface{}, errorInfo string
 	}
 	lvString := "0"
 	if lv != nil {
-		lvString = wrapForceToUInt(l.IndirectValue(lv, errorInfo),
+		lvString = l.wrapForceToUInt(l.IndirectValue(lv, errorInfo),
 			lv.(ssa.Value).Type().Underlying().(*types.Basic).Kind())
 	}
 	hvString := "-1"
 	if hv != nil {
-		hvString = wrapForceToUInt(l.IndirectValue(hv, errorInfo),
+		hvString = l.wrapForceToUInt(l.IndirectValue(hv, errorInfo),
 			hv.(ssa.Value).Type().Underlying().(*types.Basic).Kind())
 	}
 	switch x.(ssa.Value).Type().Underlying().(type) {
 	case *types.Slice:
 		return register + "=({var _v=" + xString + `;_v==null?null:(_v.subSlice(` + lvString + `,` + hvString + `));});`
 	case *types.Pointer:
-		eleSz := "1" + arrayOffsetCalc(x.(ssa.Value).Type().Underlying().(*types.Pointer).Elem().Underlying().(*types.Array).Elem().Underlying())
+		eleSz := "1" + l.arrayOffsetCalc(x.(ssa.Value).Type().Underlying().(*types.Pointer).Elem().Underlying().(*types.Array).Elem().Underlying())
 		return register + "=new Slice(" + xString + `,` + lvString + `,` + hvString + "," +
 			fmt.Sprintf("%d", x.(ssa.Value).Type().Underlying().(*types.Pointer).Elem().Underlying().(*types.Array).Len()) +
 			"," + eleSz + `);`
@@ -1794,15 +1869,15 @@ func (l langType) Slice(register string, x, lv, hv interface{}, errorInfo string
 }
 
 func (l langType) Index(register string, v1, v2 interface{}, errorInfo string) string {
-	keyString := wrapForceToUInt(l.IndirectValue(v2, errorInfo),
+	keyString := l.wrapForceToUInt(l.IndirectValue(v2, errorInfo),
 		v2.(ssa.Value).Type().Underlying().(*types.Basic).Kind())
 	typ := v1.(ssa.Value).Type().Underlying().(*types.Array).Elem().Underlying()
 	return register + "=" + //l.IndirectValue(v1, errorInfo) + "[" + l.IndirectValue(v2, errorInfo) + "];" + // assign value
 		fmt.Sprintf("%s.get%s%s%s)",
 			l.IndirectValue(v1, errorInfo),
-			loadStoreSuffix(typ, true),
+			l.loadStoreSuffix(typ, true),
 			keyString,
-			arrayOffsetCalc(typ)) + ";"
+			l.arrayOffsetCalc(typ)) + ";"
 }
 
 func (l langType) codeField(v interface{}, fNum int, fName, errorInfo string, isFunctionName bool) string {
@@ -1810,8 +1885,8 @@ func (l langType) codeField(v interface{}, fNum int, fName, errorInfo string, is
 	//return fmt.Sprintf(" /* %d */ ", fieldOffset(str, fNum)) +
 	return fmt.Sprintf("%s.get%s%d)",
 		l.IndirectValue(v, errorInfo),
-		loadStoreSuffix(str.Field(fNum).Type().Underlying(), true),
-		fieldOffset(str, fNum))
+		l.loadStoreSuffix(str.Field(fNum).Type().Underlying(), true),
+		l.fieldOffset(str, fNum))
 }
 
 // Field emits the code to load a field value into a register
@@ -1832,6 +1907,12 @@ func (l langType) RangeCheck(x, i interface{}, length int, errorInfo string) str
 	default:
 		iStr = l.IndirectValue(i, errorInfo)
 	}
+	chkFn := "wraprangechk"
+	if iv, isVal := i.(ssa.Value); isVal {
+		if bt, isBasic := iv.Type().Underlying().(*types.Basic); isBasic && l.isGOint64Kind(bt.Kind()) {
+			chkFn = "wraprangechk64"
+		}
+	}
 	if length <= 0 { // length unknown at compile time
 		xStr := l.IndirectValue(x, errorInfo)
 		tPtr := x.(ssa.Value).Type().Underlying()
@@ -1846,10 +1927,10 @@ func (l langType) RangeCheck(x, i interface{}, length int, errorInfo string) str
 		case "Object":
 			lStr += fmt.Sprintf("%d", tPtr.(*types.Array).Len())
 		}
-		chk = fmt.Sprintf("Scheduler.wraprangechk(%s,%s);", iStr, lStr)
+		chk = fmt.Sprintf("Scheduler.%s(%s,%s);", chkFn, iStr, lStr)
 	} else {
 		// length is known at compile time => an array
-		chk = fmt.Sprintf("Scheduler.wraprangechk(%s,%d);", iStr, length)
+		chk = fmt.Sprintf("Scheduler.%s(%s,%d);", chkFn, iStr, length)
 	}
 	ret := ""
 	_, hadIt := l.hc.rangeChecks[chk]
@@ -1894,7 +1975,7 @@ func (l langType) Lookup(reg string, Map, Key interface{}, commaOk bool, errorIn
 	keyString := l.IndirectValue(Key, errorInfo)
 	// check if we are looking up in a string
 	if l.LangType(Map.(ssa.Value).Type().Underlying(), false, errorInfo) == "String" {
-		keyString = wrapForceToUInt(keyString, Key.(ssa.Value).Type().Underlying().(*types.Basic).Kind())
+		keyString = l.wrapForceToUInt(keyString, Key.(ssa.Value).Type().Underlying().(*types.Basic).Kind())
 		valueCode := l.IndirectValue(Map, errorInfo) //+ ".charCodeAt(" + keyString + ")"
 		if commaOk {
 			return reg + "=Force.stringAtOK(" + valueCode + "," + keyString + ");"
@@ -1967,6 +2048,10 @@ func (l langType) Range(reg string, v interface{}, errorInfo string) string {
 }
 func (l langType) Next(register string, v interface{}, isString bool, errorInfo string) string {
 	if isString {
+		if l.intIs64() { // the index of the rune is a Go int
+			return register + "=({var _n=cast(" + l.IndirectValue(v, errorInfo) + ",GOstringRange).next();" +
+				"{r0:_n.r0,r1:GOint64.ofInt(_n.r1),r2:_n.r2};});"
+		}
 		return register + "=cast(" + l.IndirectValue(v, errorInfo) + ",GOstringRange).next();"
 		/*
 			return register + "={var _thisK:Int=" + l.IndirectValue(v, errorInfo) + ".k;" +
@@ -0,0 +1,246 @@
+// Copyright 2014 Elliott Stoneham and The TARDIS Go Authors
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package haxe
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+	"unicode"
+
+	"github.com/tardisgo/tardisgo/pogo"
+)
+
+// The Go functions, methods and struct types marked with the pogo.ExportDirective are published as Haxe facade classes,
+// with typed static functions for the functions, and a class wrapping a Pointer for each struct type.
+// Values are converted between the Go and Haxe representations at the boundary, see typeconv.go.
+
+type exportClass struct {
+	name     string
+	typ      *types.Named // nil for a class of functions
+	doc      []string
+	members  []string
+	position string
+}
+
+type exporter struct {
+	*converter
+	classes []*exportClass
+	byName  map[string]*exportClass
+	byType  map[*types.Named]*exportClass
+}
+
+// exportClasses writes a Haxe class for each class of exports.
+func (l langType) exportClasses() {
+	for _, c := range l.exports().classes {
+		l.PogoComp().WriteAsClass(c.name, l.exports().classCode(c))
+	}
+}
+
+// exports returns the classes of exports, making them on the first call.
+func (l langType) exports() *exporter {
+	if l.hc.exports != nil {
+		return l.hc.exports
+	}
+	x := &exporter{
+		converter: &converter{l: l, classes: make(map[*types.Named]string)},
+		byName:    make(map[string]*exportClass),
+		byType:    make(map[*types.Named]*exportClass),
+	}
+	l.hc.exports = x
+	exps := l.PogoComp().Exports()
+	for _, e := range exps { // first the classes for the types, so that they are known when converting values
+		if e.Func == nil {
+			if c := x.class(e.Name, e.Position); c != nil {
+				if c.typ != nil {
+					x.l.PogoComp().LogError(e.Position, "haxe", fmt.Errorf("exported class %s is already used for %s", e.Name, c.typ))
+					continue
+				}
+				c.typ = e.Type
+				c.doc = e.Doc
+				x.byType[e.Type] = c
+				x.converter.classes[e.Type] = c.name
+			}
+		}
+	}
+	for _, e := range exps {
+		if e.Func == nil {
+			continue
+		}
+		var c *exportClass
+		name := e.Name
+		if e.Type != nil { // a method
+			c = x.byType[e.Type]
+			if c == nil {
+				continue // pogo has already reported the error
+			}
+		} else {
+			className := "Go" + strings.ToUpper(e.Func.Pkg.Pkg.Name()[:1]) + e.Func.Pkg.Pkg.Name()[1:]
+			if dot := strings.LastIndex(name, "."); dot >= 0 {
+				className, name = name[:dot], name[dot+1:]
+			}
+			c = x.class(className, e.Position)
+			if c == nil {
+				continue
+			}
+		}
+		if !isHaxeIdent(name) {
+			x.l.PogoComp().LogError(e.Position, "haxe", fmt.Errorf("invalid exported Haxe name: %s", e.Name))
+			continue
+		}
+		c.members = append(c.members, x.function(name, e))
+	}
+	return x
+}
+
+// class returns the named class, creating it if required, or nil if the name is invalid.
+func (x *exporter) class(name, position string) *exportClass {
+	if c, found := x.byName[name]; found {
+		return c
+	}
+	if !isHaxeIdent(name) || !unicode.IsUpper(rune(name[0])) || name == "Go" {
+		x.l.PogoComp().LogError(position, "haxe", fmt.Errorf("invalid exported Haxe class name: %s", name))
+		return nil
+	}
+	c := &exportClass{name: name, position: position}
+	x.classes = append(x.classes, c)
+	x.byName[name] = c
+	return c
+}
+
+func (x *exporter) classCode(c *exportClass) string {
+	ret := "\n" + haxeDoc(c.doc, "")
+	ret += "#if js\n@:expose(\"" + c.name + "\")\n#end\nclass " + c.name + " {\n"
+	if c.typ != nil {
+		ret += "\tpublic var ptr:Pointer; // the Go " + c.typ.String() + "\n"
+		ret += "\tpublic function new(?ptr:Pointer) {\n"
+		ret += "\t\tif(!Go.doneInit) Go.init();\n"
+		ret += "\t\tthis.ptr = ptr==null ? Pointer.make(" + x.l.allocNewObject(types.NewPointer(c.typ)) + ") : ptr;\n"
+		ret += "\t}\n"
+		str := c.typ.Underlying().(*types.Struct)
+		for f := 0; f < str.NumFields(); f++ {
+			fld := str.Field(f)
+			if !convertedField(fld) {
+				continue
+			}
+			addr := fmt.Sprintf("ptr.fieldAddr(%d)", x.l.fieldOffset(str, f))
+			ht := x.haxeType(fld.Type())
+			ret += fmt.Sprintf("\tpublic var %s(get,set):%s;\n", fld.Name(), ht)
+			ret += fmt.Sprintf("\tfunction get_%s():%s return %s;\n", fld.Name(), ht,
+				x.toHaxe(fld.Type(), addr+".load"+x.l.loadStoreSuffix(fld.Type(), false)+")", 0))
+			ret += fmt.Sprintf("\tfunction set_%s(v:%s):%s { %s.store%s%s); return v; }\n", fld.Name(), ht, ht,
+				addr, x.l.loadStoreSuffix(fld.Type(), true), x.toGo(fld.Type(), "v", 0))
+		}
+	}
+	for _, m := range c.members {
+		ret += m
+	}
+	return ret + "}\n"
+}
+
+// function returns the code of a static function, or of a method, which calls the Go function.
+func (x *exporter) function(name string, e pogo.Export) string {
+	fn := e.Func
+	params := fn.Params
+	ret := haxeDoc(e.Doc, "\t") + "\tpublic "
+	args := []string{}
+	if e.Type != nil {
+		params = params[1:] // the receiver
+		args = append(args, "ptr")
+	} else {
+		ret += "static "
+	}
+	ret += "function " + name + "("
+	conv := ""
+	for p, prm := range params {
+		if p > 0 {
+			ret += ", "
+		}
+		pn := exportParamName(prm.Name(), p)
+		ret += pn + ":" + x.haxeType(prm.Type())
+		if cv := x.toGo(prm.Type(), pn, 0); cv != pn && !isString(prm.Type()) { // hx() converts strings
+			conv += "\t\tvar _" + pn + "=" + cv + ";\n"
+			pn = "_" + pn
+		}
+		args = append(args, pn)
+	}
+	ret += "):"
+	call := "Go_" + x.l.LangName(x.l.PogoComp().GetFnNameParts(fn)) + ".hx(" + strings.Join(args, ", ") + ")"
+	results := fn.Signature.Results()
+	switch results.Len() {
+	case 0:
+		ret += "Void {\n" + conv + "\t\t" + call + ";\n"
+	case 1:
+		rt := results.At(0).Type()
+		ret += x.haxeType(rt) + " {\n" + conv + "\t\treturn "
+		if isString(rt) {
+			ret += call + ";\n"
+		} else {
+			ret += x.toHaxe(rt, call, 0) + ";\n"
+		}
+	default:
+		typ, val := []string{}, []string{}
+		for r := 0; r < results.Len(); r++ {
+			rt := results.At(r).Type()
+			typ = append(typ, fmt.Sprintf("r%d:%s", r, x.haxeType(rt)))
+			v := fmt.Sprintf("_r.r%d", r)
+			if !isString(rt) {
+				v = x.toHaxe(rt, v, 0)
+			}
+			val = append(val, fmt.Sprintf("r%d:%s", r, v))
+		}
+		ret += "{" + strings.Join(typ, ", ") + "} {\n" + conv + "\t\tvar _r=" + call + ";\n"
+		ret += "\t\treturn {" + strings.Join(val, ", ") + "};\n"
+	}
+	return ret + "\t}\n"
+}
+
+func isString(t types.Type) bool {
+	bt, ok := t.Underlying().(*types.Basic)
+	return ok && bt.Kind() == types.String
+}
+
+func haxeDoc(doc []string, indent string) string {
+	if len(doc) == 0 {
+		return ""
+	}
+	ret := indent + "/**\n"
+	for _, line := range doc {
+		ret += indent + "\t" + strings.Replace(line, "*/", "* /", -1) + "\n"
+	}
+	return ret + indent + "**/\n"
+}
+
+var haxeKeywords = map[string]bool{
+	"abstract": true, "break": true, "case": true, "cast": true, "catch": true, "class": true, "continue": true,
+	"default": true, "do": true, "dynamic": true, "else": true, "enum": true, "extends": true, "extern": true,
+	"false": true, "for": true, "function": true, "if": true, "implements": true, "import": true, "in": true,
+	"inline": true, "interface": true, "macro": true, "new": true, "null": true, "override": true, "package": true,
+	"private": true, "public": true, "return": true, "static": true, "super": true, "switch": true, "this": true,
+	"throw": true, "true": true, "try": true, "typedef": true, "untyped": true, "using": true, "var": true, "while": true,
+}
+
+func isHaxeIdent(s string) bool {
+	if s == "" || haxeKeywords[s] || (s[0] >= '0' && s[0] <= '9') {
+		return false
+	}
+	for _, c := range s {
+		if !(c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')) {
+			return false
+		}
+	}
+	return true
+}
+
+// exportParamName gives a Haxe name for a Go parameter, which may be blank, not ASCII, or a Haxe keyword.
+func exportParamName(name string, p int) string {
+	if haxeKeywords[name] || name == "ptr" { // ptr would hide the field of a facade class
+		return name + "_"
+	}
+	if !isHaxeIdent(name) || name == "_" {
+		return fmt.Sprintf("p%d", p)
+	}
+	return name
+}
@@ -0,0 +1,138 @@
+// Copyright 2014 Elliott Stoneham and The TARDIS Go Authors
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package haxe
+
+import (
+	"flag"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tardisgo/tardisgo/pogo"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+)
+
+var updateFlag = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// compileMain compiles the Go package main in fileName to Haxe, in a temporary directory,
+// returning the Haxe files written, by name, and what the compiler wrote to stderr.
+func compileMain(t *testing.T, fileName string) (map[string]string, string, error) {
+	fileName, err := filepath.Abs(fileName) // findExports reads it again after the chdir
+	if err != nil {
+		t.Fatal(err)
+	}
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, fileName, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, _, err := ssautil.BuildPackage(&types.Config{}, fset, types.NewPackage("main", "main"), []*ast.File{f}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := ioutil.TempDir("", "tardisgo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	stderr, err := ioutil.TempFile(dir, "stderr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldStderr := os.Stderr
+	os.Stderr = stderr
+	_, compErr := pogo.Compile(pkg, []*ssa.Package{pkg}, false, false, false, 4, "haxe", "")
+	os.Stderr = oldStderr
+	stderr.Close()
+	msgs, err := ioutil.ReadFile(stderr.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := make(map[string]string)
+	infos, _ := ioutil.ReadDir("tardis") // absent if there were errors
+	for _, info := range infos {
+		src, err := ioutil.ReadFile(filepath.Join("tardis", info.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[info.Name()] = string(src)
+	}
+	return files, string(msgs), compErr
+}
+
+// TestExports checks the Haxe facade classes for testdata/export/main.go against the golden files in testdata/export.
+// Run "go test -update" to rewrite the golden files after a change to the generated code.
+func TestExports(t *testing.T) {
+	files, msgs, err := compileMain(t, "testdata/export/main.go")
+	if err != nil {
+		t.Fatalf("%v: %s", err, msgs)
+	}
+	for _, class := range []string{"Point", "Geometry", "GoMain"} {
+		got := files[class+".hx"]
+		golden := filepath.Join("testdata", "export", class+".hx.golden")
+		if *updateFlag {
+			if err := ioutil.WriteFile(golden, []byte(got), 0666); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		want, err := ioutil.ReadFile(golden)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != string(want) {
+			t.Errorf("%s.hx differs from %s, got:\n%s", class, golden, got)
+		}
+	}
+	// the exported functions are kept, although no Go code calls them
+	for _, fn := range []string{"Go_main_AArea.hx", "Go_main_PPerimeter.hx", "Go_main_CClear.hx"} {
+		if files[fn] == "" {
+			t.Errorf("%s was not written", fn)
+		}
+	}
+}
+
+// TestExportErrors checks that misused export directives are reported.
+func TestExportErrors(t *testing.T) {
+	for _, tt := range []struct{ src, err string }{
+		{"type T struct{}\n\n//tardisgo:export\nfunc (T) M() {}\n", "method M is exported, but its type T is not"},
+		{"//tardisgo:export\ntype N int\n", "only struct types can be exported, not N"},
+		{"//tardisgo:export geometry.area\nfunc F() {}\n", "invalid exported Haxe class name: geometry"},
+		{"//tardisgo:export Go.area\nfunc F() {}\n", "invalid exported Haxe class name: Go"},
+		{"//tardisgo:export Geometry.function\nfunc F() {}\n", "invalid exported Haxe name: Geometry.function"},
+		{"//tardisgo:export A\ntype A struct{}\n\n//tardisgo:export A\ntype B struct{}\n", "exported class A is already used for main.A"},
+	} {
+		dir, err := ioutil.TempDir("", "tardisgo")
+		if err != nil {
+			t.Fatal(err)
+		}
+		fileName := filepath.Join(dir, "bad.go")
+		if err := ioutil.WriteFile(fileName, []byte("package main\n\n"+tt.src+"\nfunc main() {}\n"), 0666); err != nil {
+			t.Fatal(err)
+		}
+		_, msgs, err := compileMain(t, fileName)
+		os.RemoveAll(dir)
+		if err == nil || !strings.Contains(msgs, tt.err) {
+			t.Errorf("compiling:\n%s\nreturned %v with the messages:\n%s\nwant an error containing %q", tt.src, err, msgs, tt.err)
+		}
+	}
+}
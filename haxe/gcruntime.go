@@ -0,0 +1,233 @@
+// Copyright 2014 Elliott Stoneham and The TARDIS Go Authors
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package haxe
+
+// Links to the host garbage collector, used by runtime.SetFinalizer(), runtime.GC(), runtime.ReadMemStats() and sync.Pool.
+//
+// The host tells the GOgc class when a watched Object has become unreachable:
+// java via a PhantomReference, cs via a sentinel with a destructor held in a ConditionalWeakTable,
+// cpp via the hxcpp zombie list and js via a FinalizationRegistry (where available).
+// As the original Object cannot be brought back to life on all of these targets,
+// each Object with a finalizer has a "twin" which shares the same memory, and the finalizer is given a pointer to that.
+// The finalizers run in a goroutine that waits on the GOgc.ready channel, which is sent a value when there are some to run.
+// A "canary" object is also watched, so that each host garbage collection can be counted and sync.Pool emptied.
+// None of this is available with -D abstractobjects, or on the other targets, where finalizers never run.
+
+func gcClass() string {
+	return `
+
+typedef GOgcEntry = {fn:Closure, arg:Dynamic};
+
+#if (cs && !abstractobjects)
+@:classCode("~GOgcSentinel() { global::tardis.GOgc.died(this.id); }\n")
+@:keep
+class GOgcSentinel { // the destructor runs when the Object it is attached to via the ConditionalWeakTable is unreachable
+	public var id:Int;
+	public function new(id:Int) {
+		this.id=id;
+	}
+}
+#end
+
+@:keep
+class GOgc {
+	public static var numGC:Int=0; // the number of host garbage collections observed
+	public static var lastGC:Float=0.0; // the time of the last of those, in seconds since 1970
+	static var fins=new Map<Int,GOgcEntry>(); // the finalizers waiting for their Object to become unreachable, by Object.uniqueRef()
+	static var dead=new Array<Int>(); // the uniqueRefs of the unreachable watched Objects, 0 for the canary
+	static var toRun=new Array<GOgcEntry>(); // finalizers ready to run
+	static var armed:Bool=false; // is there a canary being watched?
+	public static var ready:Channel=null; // given a value when there are finalizers to run, the finalizer goroutine waits on it
+	#if abstractobjects
+	#elseif js
+		static var registry:Dynamic=null;
+	#elseif java
+		static var queue=new java.lang.ref.ReferenceQueue<Dynamic>();
+		static var refs=new haxe.ds.ObjectMap<Dynamic,Int>(); // the PhantomReferences must stay reachable themselves
+	#elseif cs
+		static var table=new cs.system.runtime.compilerservices.ConditionalWeakTable<Dynamic,GOgcSentinel>();
+		static var lock=new Array<Int>(); // dead is written by the finalizer thread
+	#end
+
+	public static function canFinalize():Bool {
+		#if abstractobjects
+			return false;
+		#elseif js
+			return untyped __js__("typeof FinalizationRegistry !== 'undefined'");
+		#elseif (cpp || cs || java)
+			return true;
+		#else
+			return false;
+		#end
+	}
+
+	static function watch(obj:Dynamic,id:Int) { // ask the host to tell us when obj is unreachable
+		#if abstractobjects
+		#elseif js
+			if(registry==null)
+				registry=untyped __js__("new FinalizationRegistry(function(id){ {0}.push(id); })",dead);
+			registry.register(obj,id);
+		#elseif java
+			refs.set(new java.lang.ref.PhantomReference<Dynamic>(obj,queue),id);
+		#elseif cs
+			table.Remove(obj); // in case the finalizer was cleared and then set again
+			table.Add(obj,new GOgcSentinel(id));
+		#elseif cpp
+			cpp.vm.Gc.doNotKill(obj);
+		#end
+	}
+
+	#if (cs && !abstractobjects)
+	public static function died(id:Int) { // called by the finalizer thread
+		cs.Lib.lock(lock,dead.push(id));
+	}
+	#end
+
+	static function poll() { // collect the news from the host, then act on it
+		if(!armed && canFinalize()) {
+			armed=true;
+			watch(new Array<Int>(),0); // a new canary
+		}
+		#if abstractobjects
+		#elseif java
+			var r=queue.poll();
+			while(r!=null) {
+				dead.push(refs.get(r));
+				refs.remove(r);
+				r=queue.poll();
+			}
+		#elseif cpp
+			var z:Dynamic=cpp.vm.Gc.getNextZombie();
+			while(z!=null) {
+				dead.push(Std.is(z,Object)?cast(z,Object).uniqueRef():0);
+				z=cpp.vm.Gc.getNextZombie();
+			}
+		#end
+		var ids:Array<Int>;
+		#if (cs && !abstractobjects)
+			ids=[];
+			cs.Lib.lock(lock,{ ids=dead.copy(); dead.splice(0,dead.length); });
+		#else
+			ids=dead.splice(0,dead.length);
+		#end
+		for(id in ids) {
+			if(id==0) {
+				numGC++;
+				lastGC=Date.now().getTime()/1000.0;
+				armed=false;
+			} else if(fins.exists(id)) {
+				toRun.push(fins.get(id));
+				fins.remove(id);
+			}
+		}
+		if(toRun.length>0 && Channel.hasSpace(ready))
+			ready.send(true); // wake the finalizer goroutine
+	}
+
+	// tick is called by the Scheduler after each run through the goroutines, so that finalizers are found while their goroutine waits
+	public static inline function tick() {
+		if(ready!=null) poll();
+	}
+
+	// setFinalizer implements runtime.SetFinalizer(), returning false if finalizers cannot run on this target
+	public static function setFinalizer(obj:Interface,fn:Interface,argIsIface:Bool):Bool {
+		if(obj==null || !Std.is(obj.val,Pointer)) {
+			Scheduler.panicFromHaxe("runtime.SetFinalizer: first argument is not a pointer");
+			return false;
+		}
+		var p:Pointer=obj.val;
+		if(p==null) {
+			Scheduler.panicFromHaxe("runtime.SetFinalizer: pointer is nil");
+			return false;
+		}
+		var id:Int=p.obj.uniqueRef();
+		if(fn==null || fn.val==null) {
+			fins.remove(id);
+			return canFinalize();
+		}
+		#if abstractobjects
+			return false;
+		#else
+			if(!canFinalize()) return false;
+			if(!fins.exists(id)) watch(p.obj,id);
+			var twin=new Pointer(p.obj.twin(),p.off);
+			fins.set(id,{fn:fn.val, arg:argIsIface?new Interface(obj.typ,twin):twin});
+			return true;
+		#end
+	}
+
+	// runNext starts the next finalizer that is ready in goroutine gr, returning its StackFrame, or null if there is none
+	public static function runNext(gr:Int):Dynamic {
+		poll();
+		if(toRun.length==0) return null;
+		var e=toRun.shift();
+		return Closure.callFn(e.fn,[gr,e.fn.bds,e.arg]);
+	}
+
+	// gcCount returns the number of host garbage collections so far, used to empty sync.Pool
+	public static function gcCount():Int {
+		poll();
+		return numGC;
+	}
+
+	// collect implements runtime.GC()
+	public static function collect() {
+		#if js
+			untyped __js__("if(typeof global!=='undefined' && typeof global.gc==='function') global.gc();"); // node --expose-gc
+		#elseif java
+			java.lang.System.gc();
+		#elseif cs
+			cs.system.GC.Collect();
+			cs.system.GC.WaitForPendingFinalizers();
+		#elseif cpp
+			cpp.vm.Gc.run(true);
+		#elseif neko
+			neko.vm.Gc.run(true);
+		#end
+		var n=numGC;
+		poll();
+		if(n==numGC) { // the canary may not have been collected yet, but a collection was requested
+			numGC++;
+			lastGC=Date.now().getTime()/1000.0;
+		}
+	}
+
+	// heapInUse returns the number of bytes in use on the host heap, or 0 if that is not known
+	public static function heapInUse():Float {
+		#if js
+			return untyped __js__("(typeof process!=='undefined' && process.memoryUsage)?process.memoryUsage().heapUsed:((typeof performance!=='undefined' && performance.memory)?performance.memory.usedJSHeapSize:0)");
+		#elseif java
+			return untyped __java__("(double)(java.lang.Runtime.getRuntime().totalMemory()-java.lang.Runtime.getRuntime().freeMemory())");
+		#elseif cs
+			return untyped __cs__("(double)System.GC.GetTotalMemory(false)");
+		#elseif cpp
+			return cpp.vm.Gc.memInfo(cpp.vm.Gc.MEM_INFO_USAGE);
+		#elseif neko
+			var st=neko.vm.Gc.stats();
+			return st.heap-st.free;
+		#else
+			return 0.0;
+		#end
+	}
+
+	// heapSys returns the number of bytes obtained from the system for the host heap, or 0 if that is not known
+	public static function heapSys():Float {
+		#if js
+			return untyped __js__("(typeof process!=='undefined' && process.memoryUsage)?process.memoryUsage().heapTotal:((typeof performance!=='undefined' && performance.memory)?performance.memory.totalJSHeapSize:0)");
+		#elseif java
+			return untyped __java__("(double)java.lang.Runtime.getRuntime().totalMemory()");
+		#elseif cs
+			return untyped __cs__("(double)System.GC.GetTotalMemory(false)");
+		#elseif cpp
+			return cpp.vm.Gc.memInfo(cpp.vm.Gc.MEM_INFO_RESERVED);
+		#elseif neko
+			return neko.vm.Gc.stats().heap;
+		#else
+			return 0.0;
+		#end
+	}
+}
+`
+}
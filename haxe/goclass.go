@@ -72,8 +72,11 @@ func (l langType) GoClassEnd(pkg *ssa.Package) string {
 	// Haxe main function, only called in a go-only environment,
 	// or ends with a call to haxegoruntime.BrowserMain() to set-up JS timed callbacks
 	main += "\npublic static function main() : Void {\n"
+	// when compiled with -D gostore=/dir the browser store is loaded before the program starts, see syscall/fs_store_haxe.go
+	main += "#if (js && gostore)\nBrowserStore.start(function(){Go_" + l.LangName(pkg.Pkg.Path(), "main") + `.hx();HostOS.mainDone();});` + "\n#else\n"
 	main += "Go_" + l.LangName(pkg.Pkg.Path(), "main") + `.hx();` + "\n"
-	main += "}\n"
+	main += "HostOS.mainDone();\n" // write any buffered output to stdout, and exit as Go does when main returns
+	main += "#end\n}\n"
 
 	pos := "public static function CPos(pos:Int):String {\nvar prefix:String=\"\";\n"
 	pos += fmt.Sprintf(`if (pos==%d) return "(No File Position Hash)";`, pogo.NoPosHash) + "\n"
@@ -216,6 +219,16 @@ func (l langType) Const(lit ssa.Const, position string) (typ, val string) {
 	case constant.Int:
 		hi, lo := l.PogoComp().IntVal(lit.Value, position)
 		switch lit.Type().Underlying().(*types.Basic).Kind() {
+		case types.Int:
+			if l.intIs64() {
+				return "GOint64", fmt.Sprintf("Force.toInt64(GOint64.make(0x%x,0x%x))", uint32(hi), uint32(lo))
+			}
+		case types.Uint:
+			if l.intIs64() {
+				return "GOint64", fmt.Sprintf("Force.toUint64(GOint64.make(0x%x,0x%x))", uint32(hi), uint32(lo))
+			}
+		}
+		switch lit.Type().Underlying().(*types.Basic).Kind() {
 		case types.Int64:
 			return "GOint64", fmt.Sprintf("Force.toInt64(GOint64.make(0x%x,0x%x))", uint32(hi), uint32(lo))
 		case types.Uint64:
@@ -299,7 +312,7 @@ func (l langType) NamedConst(packageName, objectName string, lit ssa.Const, posi
 
 func (l langType) Global(packageName, objectName string, glob ssa.Global, position string, isPublic bool) string {
 	pub := "public " // all globals have to be public in Haxe terms
-	obj := allocNewObject(glob.Type().Underlying().(*types.Pointer))
+	obj := l.allocNewObject(glob.Type().Underlying().(*types.Pointer))
 	return fmt.Sprintf("%sstatic var %s:Pointer=Pointer.make(%s); %s",
 		pub, l.LangName(packageName, objectName), obj, l.Comment(position))
 }
@@ -0,0 +1,57 @@
+// Copyright 2014 Elliott Stoneham and The TARDIS Go Authors
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Command gosrcmap makes a JavaScript source map, written by the Haxe compiler, point at the Go source code.
+//
+// TARDIS Go writes the file tardisgo.posmap.json into its output directory, giving the Go file and line
+// that each range of lines of the generated Haxe code was compiled from.
+// The Haxe compiler writes a source map from the JavaScript to the Haxe code when asked to, for example:
+//
+//	haxe -main tardis.Go -cp tardis -D source-map -js tardis/go.js
+//
+// (use -debug rather than -D source-map with Haxe 3). gosrcmap then combines the two:
+//
+//	gosrcmap tardis/go.js.map
+//
+// so that browser devtools and IDEs show the .go files when running or debugging tardis/go.js.
+// The JavaScript that does not come from a known Go line, such as the run-time code, stays mapped to the Haxe code.
+//
+// The flags are:
+//
+//	-posmap file   the position map written by tardisgo (default "tardis/tardisgo.posmap.json")
+//	-o file        the source map to write (default: replace the given one)
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/tardisgo/tardisgo/pogo"
+)
+
+var (
+	posmapFlag = flag.String("posmap", "tardis/"+pogo.PositionMapFile, "the position map written by tardisgo")
+	outFlag    = flag.String("o", "", "the source map to write, the given one is replaced if this is empty")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: gosrcmap [flags] file.js.map")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	out := *outFlag
+	if out == "" {
+		out = flag.Arg(0)
+	}
+	if err := convert(flag.Arg(0), *posmapFlag, out); err != nil {
+		fmt.Fprintln(os.Stderr, "gosrcmap:", err)
+		os.Exit(1)
+	}
+}
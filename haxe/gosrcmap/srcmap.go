@@ -0,0 +1,247 @@
+// Copyright 2014 Elliott Stoneham and The TARDIS Go Authors
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/tardisgo/tardisgo/pogo"
+)
+
+// sourceMap is a source map, as given by the Source Map Revision 3 Proposal.
+type sourceMap struct {
+	Version        int       `json:"version"`
+	File           string    `json:"file,omitempty"`
+	SourceRoot     string    `json:"sourceRoot,omitempty"`
+	Sources        []string  `json:"sources"`
+	SourcesContent []*string `json:"sourcesContent,omitempty"`
+	Names          []string  `json:"names"`
+	Mappings       string    `json:"mappings"`
+}
+
+// A segment maps a column of the generated code to a source position, all counting from 0.
+// Only the fields up to n are used: 1 for an unmapped column, 4 without a name, or 5.
+type segment struct {
+	n                                  int
+	col, source, srcLine, srcCol, name int
+}
+
+// convert writes to out the source map in mapFile, with the lines of the Haxe files given in posmapFile mapped to Go.
+func convert(mapFile, posmapFile, out string) error {
+	var sm sourceMap
+	if err := readJSON(mapFile, &sm); err != nil {
+		return err
+	}
+	if sm.Version != 3 {
+		return fmt.Errorf("%s: unsupported source map version %d", mapFile, sm.Version)
+	}
+	var pm pogo.PositionMap
+	if err := readJSON(posmapFile, &pm); err != nil {
+		return err
+	}
+	lines, err := decodeMappings(sm.Mappings)
+	if err != nil {
+		return fmt.Errorf("%s: %v", mapFile, err)
+	}
+
+	tgtDir := filepath.Base(filepath.Dir(posmapFile))
+	hxFiles := make([]string, len(sm.Sources)) // the PositionMap file name of each source, if it has one
+	for i, src := range sm.Sources {
+		src = path.Clean(strings.TrimPrefix(filepath.ToSlash(src), "file://"))
+		if _, found := pm.Files[path.Base(src)]; found && path.Base(path.Dir(src)) == tgtDir {
+			hxFiles[i] = path.Base(src)
+		}
+	}
+	goSources := make(map[string]int) // Go file name to index in sm.Sources
+	mapped := 0
+	for _, segs := range lines {
+		for s := range segs {
+			seg := &segs[s]
+			if seg.n < 4 || seg.source >= len(hxFiles) || hxFiles[seg.source] == "" {
+				continue
+			}
+			goFile, goLine, ok := pm.Lookup(hxFiles[seg.source], seg.srcLine+1)
+			if !ok {
+				continue
+			}
+			idx, seen := goSources[goFile]
+			if !seen {
+				idx = len(sm.Sources)
+				goSources[goFile] = idx
+				sm.Sources = append(sm.Sources, sourceName(goFile, out, sm.SourceRoot))
+				if sm.SourcesContent != nil {
+					var content *string
+					if b, err := ioutil.ReadFile(goFile); err == nil {
+						s := string(b)
+						content = &s
+					}
+					sm.SourcesContent = append(sm.SourcesContent, content)
+				}
+			}
+			*seg = segment{n: 4, col: seg.col, source: idx, srcLine: goLine - 1} // the Go names are not known
+			mapped++
+		}
+	}
+	if mapped == 0 {
+		return fmt.Errorf("%s: no lines of the code in %s could be mapped to Go", mapFile, posmapFile)
+	}
+	sm.Mappings = encodeMappings(lines)
+	data, err := json.Marshal(sm)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(out, data, 0666)
+}
+
+func readJSON(name string, v interface{}) error {
+	b, err := ioutil.ReadFile(name)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("%s: %v", name, err)
+	}
+	return nil
+}
+
+// sourceName gives the name of a Go file in the source map, relative to the map where possible,
+// as browsers resolve relative names using the location of the map.
+func sourceName(goFile, mapFile, sourceRoot string) string {
+	abs, err := filepath.Abs(goFile)
+	if err != nil {
+		return filepath.ToSlash(goFile)
+	}
+	if sourceRoot == "" {
+		if dir, err := filepath.Abs(filepath.Dir(mapFile)); err == nil {
+			if rel, err := filepath.Rel(dir, abs); err == nil {
+				return filepath.ToSlash(rel)
+			}
+		}
+	}
+	abs = filepath.ToSlash(abs)
+	if !strings.HasPrefix(abs, "/") { // a Windows drive letter
+		abs = "/" + abs
+	}
+	return "file://" + abs
+}
+
+const base64Chars = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+
+// decodeMappings decodes the Base64 VLQ mappings of a source map, giving absolute values for each line of generated code.
+func decodeMappings(mappings string) ([][]segment, error) {
+	var lines [][]segment
+	var prev segment
+	for _, line := range strings.Split(mappings, ";") {
+		var segs []segment
+		prev.col = 0 // generated columns are relative to the start of each line
+		for _, field := range strings.Split(line, ",") {
+			if field == "" {
+				continue
+			}
+			var vals []int
+			for i := 0; i < len(field); {
+				v, n, err := decodeVLQ(field[i:])
+				if err != nil {
+					return nil, err
+				}
+				vals = append(vals, v)
+				i += n
+			}
+			if len(vals) != 1 && len(vals) != 4 && len(vals) != 5 {
+				return nil, fmt.Errorf("invalid mapping segment %q", field)
+			}
+			seg := prev
+			seg.n = len(vals)
+			seg.col += vals[0]
+			if seg.n >= 4 {
+				seg.source += vals[1]
+				seg.srcLine += vals[2]
+				seg.srcCol += vals[3]
+			}
+			if seg.n == 5 {
+				seg.name += vals[4]
+			}
+			segs = append(segs, seg)
+			prev = seg
+		}
+		lines = append(lines, segs)
+	}
+	return lines, nil
+}
+
+// encodeMappings reverses decodeMappings.
+func encodeMappings(lines [][]segment) string {
+	var b bytes.Buffer
+	var prev segment
+	for l, segs := range lines {
+		if l > 0 {
+			b.WriteByte(';')
+		}
+		prev.col = 0
+		for s, seg := range segs {
+			if s > 0 {
+				b.WriteByte(',')
+			}
+			encodeVLQ(&b, seg.col-prev.col)
+			prev.col = seg.col
+			if seg.n >= 4 {
+				encodeVLQ(&b, seg.source-prev.source)
+				encodeVLQ(&b, seg.srcLine-prev.srcLine)
+				encodeVLQ(&b, seg.srcCol-prev.srcCol)
+				prev.source, prev.srcLine, prev.srcCol = seg.source, seg.srcLine, seg.srcCol
+			}
+			if seg.n == 5 {
+				encodeVLQ(&b, seg.name-prev.name)
+				prev.name = seg.name
+			}
+		}
+	}
+	return b.String()
+}
+
+// decodeVLQ returns the first value in s, and the number of characters it uses.
+func decodeVLQ(s string) (int, int, error) {
+	v, shift := 0, uint(0)
+	for i := 0; i < len(s); i++ {
+		digit := strings.IndexByte(base64Chars, s[i])
+		if digit < 0 {
+			return 0, 0, fmt.Errorf("invalid Base64 VLQ character %q", s[i])
+		}
+		v += (digit & 31) << shift
+		if digit&32 == 0 {
+			if v&1 != 0 {
+				return -(v >> 1), i + 1, nil
+			}
+			return v >> 1, i + 1, nil
+		}
+		shift += 5
+	}
+	return 0, 0, fmt.Errorf("unterminated Base64 VLQ value %q", s)
+}
+
+func encodeVLQ(b *bytes.Buffer, v int) {
+	if v < 0 {
+		v = (-v << 1) | 1
+	} else {
+		v <<= 1
+	}
+	for {
+		digit := v & 31
+		v >>= 5
+		if v > 0 {
+			digit |= 32
+		}
+		b.WriteByte(base64Chars[digit])
+		if v == 0 {
+			return
+		}
+	}
+}
@@ -0,0 +1,161 @@
+// Copyright 2014 Elliott Stoneham and The TARDIS Go Authors
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestVLQ(t *testing.T) {
+	for _, tt := range []struct {
+		v   int
+		vlq string
+	}{
+		{0, "A"},
+		{1, "C"},
+		{-1, "D"},
+		{15, "e"},
+		{-15, "f"},
+		{16, "gB"},
+		{-16, "hB"},
+		{1000, "w+B"},
+		{-1000, "x+B"},
+		{1 << 20, "ggggC"},
+	} {
+		var b bytes.Buffer
+		encodeVLQ(&b, tt.v)
+		if b.String() != tt.vlq {
+			t.Errorf("encodeVLQ(%d) = %q, want %q", tt.v, b.String(), tt.vlq)
+		}
+		v, n, err := decodeVLQ(tt.vlq + "A") // the value is followed by the next one
+		if err != nil || v != tt.v || n != len(tt.vlq) {
+			t.Errorf("decodeVLQ(%q) = %d, %d, %v, want %d, %d", tt.vlq+"A", v, n, err, tt.v, len(tt.vlq))
+		}
+	}
+	for _, s := range []string{"", "g", "gg", "!", "g!"} {
+		if _, _, err := decodeVLQ(s); err == nil {
+			t.Errorf("decodeVLQ(%q) did not fail", s)
+		}
+	}
+}
+
+// TestMappings checks mappings with 1, 4 and 5 field segments, negative deltas and lines without segments.
+// A 1 field segment keeps the source position of the segment before it, as decodeMappings gives it.
+func TestMappings(t *testing.T) {
+	for _, tt := range []struct {
+		mappings string
+		lines    [][]segment
+	}{
+		{"", [][]segment{nil}},
+		{"AAAA", [][]segment{{{n: 4}}}},
+		{";;C", [][]segment{nil, nil, {{n: 1, col: 1}}}},
+		{
+			"AAAA,IAEEA,K;ACGF;EDDC,IAEDA",
+			[][]segment{
+				{{n: 4}, {n: 5, col: 4, srcLine: 2, srcCol: 2}, {n: 1, col: 9, srcLine: 2, srcCol: 2}},
+				{{n: 4, source: 1, srcLine: 5}},
+				{{n: 4, col: 2, srcLine: 4, srcCol: 1}, {n: 5, col: 6, srcLine: 6}},
+			},
+		},
+		{
+			"CAAAC,GCCCC;EDDDD,C", // negative deltas of every field, after a 1 field segment
+			[][]segment{
+				{{n: 5, col: 1, name: 1}, {n: 5, col: 4, source: 1, srcLine: 1, srcCol: 1, name: 2}},
+				{{n: 5, col: 2, name: 1}, {n: 1, col: 3, name: 1}},
+			},
+		},
+	} {
+		lines, err := decodeMappings(tt.mappings)
+		if err != nil {
+			t.Errorf("decodeMappings(%q): %v", tt.mappings, err)
+			continue
+		}
+		if !reflect.DeepEqual(lines, tt.lines) {
+			t.Errorf("decodeMappings(%q) = %v, want %v", tt.mappings, lines, tt.lines)
+		}
+		if s := encodeMappings(lines); s != tt.mappings {
+			t.Errorf("encodeMappings(%v) = %q, want %q", lines, s, tt.mappings)
+		}
+	}
+	for _, s := range []string{"AA", "AAA", "AAAAAA", "A!", "AAAg"} {
+		if _, err := decodeMappings(s); err == nil {
+			t.Errorf("decodeMappings(%q) did not fail", s)
+		}
+	}
+}
+
+func TestSourceName(t *testing.T) {
+	if got := sourceName("testdata/main.go", "testdata/go.js.map", ""); got != "main.go" {
+		t.Errorf("sourceName relative to the map = %q, want main.go", got)
+	}
+	if got := sourceName("testdata/main.go", "testdata/go.js.map", "/src"); !strings.HasPrefix(got, "file:///") || !strings.HasSuffix(got, "/testdata/main.go") {
+		t.Errorf("sourceName with a sourceRoot = %q, want a file URL", got)
+	}
+}
+
+// TestConvert converts testdata/go.js.map, where Go.hx lines 1, 3 and 7 come from testdata/main.go,
+// according to testdata/tardis/tardisgo.posmap.json.
+func TestConvert(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gosrcmap")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	out := filepath.Join(dir, "go.js.map")
+	if err := convert("testdata/go.js.map", "testdata/tardis/tardisgo.posmap.json", out); err != nil {
+		t.Fatal(err)
+	}
+	var sm sourceMap
+	if err := readJSON(out, &sm); err != nil {
+		t.Fatal(err)
+	}
+	goSource := sourceName("testdata/main.go", out, "")
+	if want := []string{"file:///home/u/proj/tardis/Go.hx", "/usr/share/haxe/std/StringTools.hx", goSource}; !reflect.DeepEqual(sm.Sources, want) {
+		t.Errorf("sources %q, want %q", sm.Sources, want)
+	}
+	goContent, err := ioutil.ReadFile("testdata/main.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sm.SourcesContent) != 3 || sm.SourcesContent[1] != nil || sm.SourcesContent[2] == nil || *sm.SourcesContent[2] != string(goContent) {
+		t.Errorf("sourcesContent %v, want the Haxe content, null and the content of main.go", sm.SourcesContent)
+	}
+	if sm.Version != 3 || sm.File != "go.js" || !reflect.DeepEqual(sm.Names, []string{"x"}) {
+		t.Errorf("version %d, file %q, names %q, want them unchanged", sm.Version, sm.File, sm.Names)
+	}
+	want := encodeMappings([][]segment{
+		{{n: 4, source: 2, srcLine: 2}, {n: 4, col: 4, source: 2, srcLine: 3}, {n: 1, col: 9}},
+		{{n: 4, source: 1, srcLine: 5}}, // StringTools.hx is not in the position map
+		{{n: 4, col: 2, srcLine: 4, srcCol: 1}, {n: 4, col: 6, source: 2, srcLine: 4}}, // Go.hx line 5 has no Go line
+	})
+	if sm.Mappings != want {
+		t.Errorf("mappings %q, want %q", sm.Mappings, want)
+	}
+
+	for _, tt := range []struct{ sourceMap, err string }{
+		{`{"version":2,"sources":[],"names":[],"mappings":""}`, "unsupported source map version 2"},
+		{`{"version":3,"sources":["other/Go.hx"],"names":[],"mappings":"AAAA"}`, "no lines"}, // not in the target directory
+		{`{"version":3,"sources":["tardis/Go.hx"],"names":[],"mappings":"AA"}`, "invalid mapping segment"},
+		{`{"version":3`, "unexpected end of JSON input"},
+	} {
+		bad := filepath.Join(dir, "bad.js.map")
+		if err := ioutil.WriteFile(bad, []byte(tt.sourceMap), 0666); err != nil {
+			t.Fatal(err)
+		}
+		err := convert(bad, "testdata/tardis/tardisgo.posmap.json", out)
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("converting %s returned %v, want an error containing %q", tt.sourceMap, err, tt.err)
+		}
+	}
+	if err := convert("testdata/go.js.map", filepath.Join(dir, "missing.json"), out); err == nil {
+		t.Error("converting with a missing position map did not fail")
+	}
+}
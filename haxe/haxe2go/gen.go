@@ -0,0 +1,595 @@
+// Copyright 2014 Elliott Stoneham and The TARDIS Go Authors
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const hxImport = "github.com/tardisgo/tardisgo/haxe/hx"
+
+// A hxType is a Haxe class or enum chosen to be wrapped.
+type hxType struct {
+	path   string // the Haxe type path, e.g. "haxe.io.Bytes"
+	pkg    string // the Haxe package, e.g. "haxe.io"
+	name   string // the Haxe type name, e.g. "Bytes"
+	isEnum bool
+	n      *node
+}
+
+type generator struct {
+	source, out, prefix, rootPkg, ifLogic string
+
+	types    map[string]*hxType   // by Haxe type path
+	packages map[string][]*hxType // by Haxe package
+	reserved map[string]bool      // names not to be used for arguments
+}
+
+// choose records the classes and enums in top that match the selectors (all of them if there are none).
+func (g *generator) choose(top *node, selectors []string) error {
+	g.types = make(map[string]*hxType)
+	g.packages = make(map[string][]*hxType)
+	used := make([]bool, len(selectors))
+	for i := range top.Nodes {
+		n := &top.Nodes[i]
+		if (n.name() != "class" && n.name() != "enum") || n.attr("private") == "1" {
+			continue
+		}
+		path := n.attr("path")
+		if path == "" || g.types[path] != nil {
+			continue
+		}
+		chosen := len(selectors) == 0
+		for s, sel := range selectors {
+			if path == sel || (strings.HasSuffix(sel, ".*") && strings.HasPrefix(path, sel[:len(sel)-1])) {
+				chosen = true
+				used[s] = true
+			}
+		}
+		if !chosen {
+			continue
+		}
+		t := &hxType{path: path, name: path, isEnum: n.name() == "enum", n: n}
+		if dot := strings.LastIndex(path, "."); dot >= 0 {
+			t.pkg, t.name = path[:dot], path[dot+1:]
+		}
+		g.types[path] = t
+		g.packages[t.pkg] = append(g.packages[t.pkg], t)
+	}
+	for s, sel := range selectors {
+		if !used[s] {
+			return fmt.Errorf("no public Haxe class or enum matches %q in %s", sel, g.source)
+		}
+	}
+	g.reserved = map[string]bool{"hx": true, "o": true, "v": true}
+	for pkg := range g.packages {
+		g.reserved[g.pkgName(pkg)] = true
+	}
+	return nil
+}
+
+// pkgDir gives the directory, relative to -out, of the Go package for a Haxe package.
+func (g *generator) pkgDir(pkg string) string {
+	if pkg == "" {
+		return g.rootPkg
+	}
+	return strings.Replace(pkg, ".", "/", -1)
+}
+
+// pkgName gives the name of the Go package for a Haxe package.
+func (g *generator) pkgName(pkg string) string {
+	dir := g.pkgDir(pkg)
+	name := strings.ToLower(dir[strings.LastIndex(dir, "/")+1:])
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+func (g *generator) generate() error {
+	pkgs := make([]string, 0, len(g.packages))
+	for pkg := range g.packages {
+		pkgs = append(pkgs, pkg)
+	}
+	sort.Strings(pkgs)
+	for _, pkg := range pkgs {
+		ts := g.packages[pkg]
+		sort.Sort(byPath(ts))
+		dir := filepath.Join(g.out, filepath.FromSlash(g.pkgDir(pkg)))
+		if err := os.MkdirAll(dir, 0777); err != nil {
+			return err
+		}
+		names := make(map[string]bool) // the names used at the package level
+		for _, t := range ts {
+			names[goName(t.name)] = true
+		}
+		for _, t := range ts {
+			f := &file{g: g, pkg: pkg, imports: make(map[string]string), names: names}
+			if t.isEnum {
+				f.enum(t)
+			} else {
+				f.class(t)
+			}
+			src, err := f.source()
+			if err != nil {
+				return fmt.Errorf("%s: %v", t.path, err)
+			}
+			fileName := filepath.Join(dir, strings.ToLower(t.name)+".go")
+			if err := ioutil.WriteFile(fileName, src, 0666); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+type byPath []*hxType
+
+func (b byPath) Len() int           { return len(b) }
+func (b byPath) Less(i, j int) bool { return b[i].path < b[j].path }
+func (b byPath) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
+
+// A file is the Go source for one Haxe type.
+type file struct {
+	g       *generator
+	pkg     string
+	imports map[string]string // import path -> name used
+	names   map[string]bool   // package-level names already used
+	methods map[string]bool   // method names already used by the current type
+	buf     bytes.Buffer
+}
+
+func (f *file) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&f.buf, format, args...)
+}
+
+// source returns the formatted Go source of the file.
+func (f *file) source() ([]byte, error) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by haxe2go from %s; DO NOT EDIT.\n\n", filepath.Base(f.g.source))
+	fmt.Fprintf(&b, "package %s\n\nimport (\n", f.g.pkgName(f.pkg))
+	paths := make([]string, 0, len(f.imports))
+	for path := range f.imports {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		fmt.Fprintf(&b, "\t%s %q\n", f.imports[path], path)
+	}
+	b.WriteString(")\n")
+	b.Write(f.buf.Bytes())
+	return format.Source(b.Bytes())
+}
+
+// use notes that the file uses the Go package with the given import path, returning the name to refer to it by.
+func (f *file) use(path, name string) string {
+	if n, ok := f.imports[path]; ok {
+		return n
+	}
+	n := name
+	for i := 2; f.nameTaken(n); i++ {
+		n = fmt.Sprintf("%s%d", name, i)
+	}
+	f.imports[path] = n
+	return n
+}
+
+func (f *file) nameTaken(n string) bool {
+	for _, used := range f.imports {
+		if used == n {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *file) hx() string {
+	return f.use(hxImport, "hx")
+}
+
+// A goType describes how a Haxe type is held in Go.
+type goType struct {
+	decl string  // the Go type, "" for Void
+	kind string  // the suffix of the hx function to use: "Bool", "Int", "Float", "String" or "Dynamic"
+	wrap *hxType // the wrapped Haxe type, if any
+}
+
+var dynamicType = goType{decl: "uintptr", kind: "Dynamic"}
+
+// goTypeOf returns the Go type for the Haxe type described by n.
+func (f *file) goTypeOf(n *node) goType {
+	if n == nil {
+		return dynamicType
+	}
+	path := n.attr("path")
+	switch n.name() {
+	case "x", "t", "c":
+		switch path {
+		case "Void":
+			return goType{}
+		case "Bool":
+			return goType{decl: "bool", kind: "Bool"}
+		case "Int", "UInt":
+			return goType{decl: "int", kind: "Int"}
+		case "Float":
+			return goType{decl: "float64", kind: "Float"}
+		case "String":
+			return goType{decl: "string", kind: "String"}
+		case "Null":
+			if ts := n.types(); len(ts) == 1 {
+				if gt := f.goTypeOf(ts[0]); gt.wrap == nil && gt.decl != "" {
+					return gt // NOTE a null value is given as the zero value of the Go type
+				}
+			}
+		}
+	}
+	if t := f.g.types[path]; t != nil && (n.name() == "c" || n.name() == "e") {
+		return goType{decl: f.qualify(t), kind: "Dynamic", wrap: t}
+	}
+	return dynamicType
+}
+
+// qualify returns the Go name of a wrapped type, as used in this file.
+func (f *file) qualify(t *hxType) string {
+	if t.pkg == f.pkg {
+		return goName(t.name)
+	}
+	return f.use(path.Join(f.g.prefix, f.g.pkgDir(t.pkg)), f.g.pkgName(t.pkg)) + "." + goName(t.name)
+}
+
+// toHaxe returns the expression to pass the Go value v to an hx function.
+func (gt goType) toHaxe(v string) string {
+	if gt.wrap != nil {
+		return "uintptr(" + v + ")"
+	}
+	return v
+}
+
+// fromHaxe returns the expression to give the result of the hx call as the Go type.
+func (gt goType) fromHaxe(call string) string {
+	if gt.wrap != nil {
+		return gt.decl + "(" + call + ")"
+	}
+	return call
+}
+
+// goName returns an exported Go identifier for a Haxe name.
+func goName(name string) string {
+	if name == "" {
+		return "X"
+	}
+	r := []rune(name)
+	if !unicode.IsLetter(r[0]) {
+		return "X" + name
+	}
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+// unique returns name, or name with "_" added until it is not in used, recording the result in used.
+func unique(name string, used map[string]bool) string {
+	for used[name] {
+		name += "_"
+	}
+	used[name] = true
+	return name
+}
+
+// comment writes a doc comment, followed by the Haxe documentation if there is any.
+func (f *file) comment(first string, n *node) {
+	f.printf("\n// %s\n", first)
+	if lines := n.doc(); len(lines) > 0 {
+		f.printf("//\n")
+		for _, l := range lines {
+			if l == "" {
+				f.printf("//\n")
+			} else {
+				f.printf("// %s\n", l)
+			}
+		}
+	}
+}
+
+// An argument of a Haxe function.
+type arg struct {
+	name string
+	typ  goType
+}
+
+// args returns the arguments and result of the Haxe function type fn.
+func (f *file) args(fn *node) ([]arg, goType) {
+	ts := fn.types()
+	if len(ts) == 0 {
+		return nil, goType{}
+	}
+	return f.params(fn.attr("a"), ts[:len(ts)-1]), f.goTypeOf(ts[len(ts)-1])
+}
+
+// params returns the arguments with the given types, and names separated by ':' as in the "a" attribute.
+func (f *file) params(names string, ts []*node) []arg {
+	if len(ts) == 0 {
+		return nil
+	}
+	ns := strings.Split(names, ":")
+	used := make(map[string]bool)
+	var as []arg
+	for i, t := range ts {
+		name := ""
+		if i < len(ns) {
+			name = strings.TrimPrefix(ns[i], "?")
+		}
+		if name == "" || !isIdent(name) || isKeyword(name) || f.g.reserved[name] {
+			name = fmt.Sprintf("a%d", i)
+		}
+		as = append(as, arg{unique(name, used), f.goTypeOf(t)})
+	}
+	return as
+}
+
+// signature returns the Go parameter list and result for args and result.
+func signature(as []arg, res goType) string {
+	s := "("
+	for i, a := range as {
+		if i > 0 {
+			s += ", "
+		}
+		s += a.name + " " + a.typ.decl
+	}
+	s += ")"
+	if res.decl != "" {
+		s += " " + res.decl
+	}
+	return s
+}
+
+// callArgs returns the nargs and args parameters of an hx call.
+func callArgs(as []arg) string {
+	s := strconv.Itoa(len(as))
+	for _, a := range as {
+		s += ", " + a.typ.toHaxe(a.name)
+	}
+	return s
+}
+
+// body returns the body of a Go function making the hx call, with its result if it has one.
+func body(res goType, call string) string {
+	if res.decl == "" {
+		return "{\n\t" + call + "\n}\n"
+	}
+	return "{\n\treturn " + res.fromHaxe(call) + "\n}\n"
+}
+
+// hxFunc returns the name of the hx function to use for a Haxe result type, with the given prefix, e.g. "Call".
+func (f *file) hxFunc(prefix string, res goType) string {
+	if res.decl == "" {
+		return f.hx() + "." + prefix
+	}
+	return f.hx() + "." + prefix + res.kind
+}
+
+func (f *file) class(t *hxType) {
+	typ := goName(t.name)
+	kind := "class"
+	if t.n.attr("interface") == "1" {
+		kind = "interface"
+	}
+	f.comment(fmt.Sprintf("%s is a Haxe %s instance of type %s.", typ, kind, t.path), t.n)
+	f.printf("type %s uintptr\n", typ)
+	f.methods = make(map[string]bool)
+
+	// conversions to the wrapped types this class extends or implements
+	for _, rel := range []struct{ name, desc string }{{"extends", "super class"}, {"implements", "interface"}} {
+		for i := range t.n.Nodes {
+			if t.n.Nodes[i].name() != rel.name {
+				continue
+			}
+			if st := f.g.types[t.n.Nodes[i].attr("path")]; st != nil {
+				name := unique("As"+goName(st.name), f.methods)
+				decl := f.qualify(st)
+				f.printf("\n// %s returns o as its Haxe %s %s.\n", name, rel.desc, st.path)
+				f.printf("func (o %s) %s() %s { return %s(o) }\n", typ, name, decl, decl)
+			}
+		}
+	}
+
+	for i := range t.n.Nodes {
+		fld := &t.n.Nodes[i]
+		ft := fld.fieldType()
+		if ft == nil || (fld.attr("public") != "1" && t.n.attr("extern") != "1" && kind != "interface") {
+			continue
+		}
+		static := fld.attr("static") == "1"
+		hxName := fld.name()
+		switch {
+		case hxName == "new":
+			if static || kind == "interface" {
+				continue
+			}
+			for i, sig := range signatures(fld) {
+				as, _ := f.args(sig)
+				name := unique("New"+typ+suffix(i), f.names)
+				f.comment(fmt.Sprintf("%s creates a new %s, using the Haxe constructor%s.", name, t.path, overload(i)), fld)
+				f.printf("func %s%s %s", name, signature(as, goType{decl: typ}),
+					body(goType{decl: typ, kind: "Dynamic", wrap: t},
+						fmt.Sprintf("%s.New(%q, %q, %s)", f.hx(), f.g.ifLogic, t.path, callArgs(as))))
+			}
+
+		case ft.name() == "f" && (fld.attr("set") == "method" || fld.attr("set") == "dynamic" || fld.attr("get") == "inline"):
+			for i, sig := range signatures(fld) {
+				as, res := f.args(sig)
+				if static {
+					name := unique(typ+"_"+goName(hxName)+suffix(i), f.names)
+					f.comment(fmt.Sprintf("%s calls the Haxe static function %s.%s%s.", name, t.path, hxName, overload(i)), fld)
+					f.printf("func %s%s %s", name, signature(as, res),
+						body(res, fmt.Sprintf("%s(%q, %q, %s)", f.hxFunc("Call", res), f.g.ifLogic, t.path+"."+hxName, callArgs(as))))
+				} else {
+					name := unique(goName(hxName)+suffix(i), f.methods)
+					f.comment(fmt.Sprintf("%s calls the Haxe method %s.%s%s.", name, t.path, hxName, overload(i)), fld)
+					f.printf("func (o %s) %s%s %s", typ, name, signature(as, res),
+						body(res, fmt.Sprintf("%s(%q, uintptr(o), %q, %q, %s)", f.hxFunc("Meth", res), f.g.ifLogic, t.path, hxName, callArgs(as))))
+				}
+			}
+
+		default: // a variable or property
+			vt := f.goTypeOf(ft)
+			if vt.decl == "" {
+				continue
+			}
+			canGet := fld.attr("get") != "null" && fld.attr("get") != "never"
+			canSet := fld.attr("set") != "null" && fld.attr("set") != "never" && fld.attr("get") != "inline"
+			if static {
+				if c, ok := constant(fld, vt); ok {
+					name := unique(typ+"_"+goName(hxName), f.names)
+					f.comment(fmt.Sprintf("%s is the value of the Haxe constant %s.%s.", name, t.path, hxName), fld)
+					f.printf("const %s %s = %s\n", name, vt.decl, c)
+					continue
+				}
+				if canGet {
+					name := unique(typ+"_"+goName(hxName), f.names)
+					f.comment(fmt.Sprintf("%s returns the value of the Haxe static variable %s.%s.", name, t.path, hxName), fld)
+					f.printf("func %s() %s %s", name, vt.decl,
+						body(vt, fmt.Sprintf("%s.Get%s(%q, %q)", f.hx(), vt.kind, f.g.ifLogic, t.path+"."+hxName)))
+				}
+				if canSet {
+					name := unique(typ+"_Set"+goName(hxName), f.names)
+					f.printf("\n// %s sets the Haxe static variable %s.%s.\n", name, t.path, hxName)
+					f.printf("func %s(v %s) {\n\t%s.Set%s(%q, %q, %s)\n}\n", name, vt.decl,
+						f.hx(), vt.kind, f.g.ifLogic, t.path+"."+hxName, vt.toHaxe("v"))
+				}
+			} else {
+				if canGet {
+					name := unique(goName(hxName), f.methods)
+					f.comment(fmt.Sprintf("%s returns the value of the Haxe field %s.%s.", name, t.path, hxName), fld)
+					f.printf("func (o %s) %s() %s %s", typ, name, vt.decl,
+						body(vt, fmt.Sprintf("%s.Fget%s(%q, uintptr(o), %q, %q)", f.hx(), vt.kind, f.g.ifLogic, t.path, hxName)))
+				}
+				if canSet {
+					name := unique("Set"+goName(hxName), f.methods)
+					f.printf("\n// %s sets the Haxe field %s.%s.\n", name, t.path, hxName)
+					f.printf("func (o %s) %s(v %s) {\n\t%s.Fset%s(%q, uintptr(o), %q, %q, %s)\n}\n", typ, name, vt.decl,
+						f.hx(), vt.kind, f.g.ifLogic, t.path, hxName, vt.toHaxe("v"))
+				}
+			}
+		}
+	}
+}
+
+// signatures returns the function type of a method field, followed by those of its overloads, if it has any.
+func signatures(fld *node) []*node {
+	sigs := []*node{fld.fieldType()}
+	if ov := fld.child("overloads"); ov != nil {
+		for i := range ov.Nodes {
+			if ft := ov.Nodes[i].fieldType(); ft != nil && ft.name() == "f" {
+				sigs = append(sigs, ft)
+			}
+		}
+	}
+	return sigs
+}
+
+// suffix returns the suffix of the Go name for signature i of an overloaded method, e.g. "2" for the first overload.
+func suffix(i int) string {
+	if i == 0 {
+		return ""
+	}
+	return strconv.Itoa(i + 1)
+}
+
+// overload returns the end of the doc comment for signature i of an overloaded method.
+func overload(i int) string {
+	if i == 0 {
+		return ""
+	}
+	return fmt.Sprintf(", with overloaded signature %d", i+1)
+}
+
+func (f *file) enum(t *hxType) {
+	typ := goName(t.name)
+	f.comment(fmt.Sprintf("%s is a value of the Haxe enum %s.", typ, t.path), t.n)
+	f.printf("type %s uintptr\n", typ)
+	f.methods = map[string]bool{"Index": true}
+	f.printf("\n// Index returns the index of the constructor of e, in the order they are declared.\n")
+	f.printf("func (e %s) Index() int {\n\treturn %s.CallInt(%q, \"Type.enumIndex\", 1, uintptr(e))\n}\n", typ, f.hx(), f.g.ifLogic)
+	for i := range t.n.Nodes {
+		ctor := &t.n.Nodes[i]
+		switch ctor.name() {
+		case "meta", "haxe_doc":
+			continue
+		}
+		name := unique(typ+"_"+goName(ctor.name()), f.names)
+		target := t.path + "." + ctor.name()
+		res := goType{decl: typ, kind: "Dynamic", wrap: t}
+		if len(ctor.types()) == 0 {
+			f.comment(fmt.Sprintf("%s returns the Haxe enum value %s.", name, target), ctor)
+			f.printf("func %s() %s %s", name, typ, body(res, fmt.Sprintf("%s.GetDynamic(%q, %q)", f.hx(), f.g.ifLogic, target)))
+			continue
+		}
+		as := f.params(ctor.attr("a"), ctor.types())
+		f.comment(fmt.Sprintf("%s returns a new Haxe enum value %s.", name, target), ctor)
+		f.printf("func %s%s %s", name, signature(as, res),
+			body(res, fmt.Sprintf("%s.CallDynamic(%q, %q, %s)", f.hx(), f.g.ifLogic, target, callArgs(as))))
+	}
+}
+
+// constant returns the Go form of the value of a Haxe inline static variable of a basic type, if it is a literal.
+func constant(fld *node, vt goType) (string, bool) {
+	expr := strings.TrimSpace(fld.attr("expr"))
+	if fld.attr("get") != "inline" || expr == "" {
+		return "", false
+	}
+	switch vt.kind {
+	case "Bool":
+		if expr == "true" || expr == "false" {
+			return expr, true
+		}
+	case "Int":
+		if _, err := strconv.ParseInt(strings.TrimPrefix(expr, "-"), 0, 64); err == nil {
+			return expr, true
+		}
+	case "Float":
+		if _, err := strconv.ParseFloat(expr, 64); err == nil {
+			return expr, true
+		}
+	case "String":
+		if strings.HasPrefix(expr, `"`) {
+			if s, err := strconv.Unquote(expr); err == nil {
+				return strconv.Quote(s), true
+			}
+		}
+	}
+	return "", false
+}
+
+func isIdent(s string) bool {
+	for i, r := range s {
+		if !unicode.IsLetter(r) && r != '_' && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func isKeyword(s string) bool {
+	switch s {
+	case "break", "case", "chan", "const", "continue", "default", "defer", "else", "fallthrough", "for", "func",
+		"go", "goto", "if", "import", "interface", "map", "package", "range", "return", "select", "struct",
+		"switch", "type", "var",
+		"bool", "int", "float64", "string", "uintptr": // the types used in signatures
+		return true
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+// Copyright 2014 Elliott Stoneham and The TARDIS Go Authors
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var updateFlag = flag.Bool("update", false, "rewrite the golden files in testdata/golden")
+
+// TestGolden generates the Go for testdata/haxe.xml, a small "haxe -xml" dump, and compares it with testdata/golden,
+// where each generated file has a golden file with ".golden" added to its name.
+// Run "go test -update" to rewrite the golden files after a change to the generated code.
+func TestGolden(t *testing.T) {
+	top, err := readXML("testdata/haxe.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := ioutil.TempDir("", "haxe2go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(out)
+	g := &generator{source: "testdata/haxe.xml", out: out, prefix: "example.com/hxlib", rootPkg: "hxroot"}
+	if err := g.choose(top, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.generate(); err != nil {
+		t.Fatal(err)
+	}
+
+	generated := make(map[string][]byte) // by the file name relative to out
+	err = filepath.Walk(out, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(out, path)
+		if err != nil {
+			return err
+		}
+		generated[filepath.ToSlash(rel)], err = ioutil.ReadFile(path)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"demo/shape.go", "demo/square.go", "demo/drawable.go", "demo/kind.go", "hxroot/main.go"} {
+		if generated[want] == nil {
+			t.Errorf("%s was not generated", want)
+		}
+	}
+	if len(generated) != 5 {
+		t.Errorf("generated %d files, want 5 (no private types or typedefs)", len(generated))
+	}
+
+	for rel, got := range generated {
+		golden := filepath.Join("testdata", "golden", filepath.FromSlash(rel)+".golden")
+		if *updateFlag {
+			if err := os.MkdirAll(filepath.Dir(golden), 0777); err != nil {
+				t.Fatal(err)
+			}
+			if err := ioutil.WriteFile(golden, got, 0666); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		want, err := ioutil.ReadFile(golden)
+		if err != nil {
+			t.Errorf("%s: %v", rel, err)
+			continue
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s differs from %s, got:\n%s", rel, golden, got)
+		}
+	}
+}
+
+func TestChoose(t *testing.T) {
+	top, err := readXML("testdata/haxe.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		selectors []string
+		paths     []string
+	}{
+		{[]string{"demo.Kind"}, []string{"demo.Kind"}},
+		{[]string{"demo.*"}, []string{"demo.Drawable", "demo.Kind", "demo.Shape", "demo.Square"}},
+		{[]string{"Main", "demo.Square"}, []string{"Main", "demo.Square"}},
+	} {
+		g := &generator{source: "haxe.xml", rootPkg: "hxroot"}
+		if err := g.choose(top, tt.selectors); err != nil {
+			t.Errorf("choose(%v): %v", tt.selectors, err)
+			continue
+		}
+		if len(g.types) != len(tt.paths) {
+			t.Errorf("choose(%v) chose %d types, want %v", tt.selectors, len(g.types), tt.paths)
+		}
+		for _, p := range tt.paths {
+			if g.types[p] == nil {
+				t.Errorf("choose(%v) did not choose %s", tt.selectors, p)
+			}
+		}
+	}
+	for _, sel := range []string{"demo.Point", "demo._Shape.Helper", "other.*"} {
+		g := &generator{source: "haxe.xml", rootPkg: "hxroot"}
+		if err := g.choose(top, []string{sel}); err == nil {
+			t.Errorf("choose(%q) did not fail, though no public class or enum matches it", sel)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2014 Elliott Stoneham and The TARDIS Go Authors
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Command haxe2go generates typed Go wrappers for Haxe classes and enums, for use with TARDIS Go.
+//
+// It reads the Haxe type information written by the -xml flag of the Haxe compiler, for example:
+//
+//	haxe -js dummy.js --no-output -xml haxe.xml haxe.Http haxe.io.Bytes
+//
+// and writes a Go package for each Haxe package of the chosen types, so that the Haxe class haxe.Http
+// becomes the Go type Http in the package <prefix>/haxe, with typed methods, constructors and constants.
+// The generated code calls the untyped pseudo-functions of package hx, so using a Haxe library in the wrong way
+// becomes a Go compile error, rather than a Haxe compile error or a run-time failure.
+//
+// Usage:
+//
+//	haxe2go [flags] haxe.xml [type ...]
+//
+// Each type is a Haxe type path, such as haxe.Http, or a package path ending in ".*", such as haxe.io.*,
+// which chooses every type in that package and in its sub-packages. With no types, every public type is chosen.
+// The flags are:
+//
+//	-out dir       the directory to write the Go packages into (default ".")
+//	-prefix path   the Go import path of that directory, used when one generated package refers to another
+//	-root name     the Go package for the Haxe top-level package (default "hxroot")
+//	-if logic      the Haxe compile-time condition for the generated calls, for example "js" for the js.* classes
+//
+// In the Go code, a Haxe object is held as a named uintptr type, a Haxe function or a type that was not chosen as a uintptr,
+// and the Haxe types Bool, Int, Float and String as the Go types bool, int, float64 and string.
+// Optional Haxe arguments must still be given, use hx.Null() (converted to the right type) for an absent object.
+// A Haxe static field "Name" of the type "T" becomes the Go function T_Name, and a class constructor NewT.
+// Each overload of a Haxe method (@:overload, as in the extern classes for java and cs) adds a numbered Go function,
+// so that the overloads of method "name" become Name, Name2, Name3 and so on, and those of the constructor NewT2...
+// Haxe type parameters are not carried over, so a value of a type parameter is held as a uintptr.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+var (
+	outFlag    = flag.String("out", ".", "the directory to write the Go packages into")
+	prefixFlag = flag.String("prefix", "", "the Go import path of the -out directory")
+	rootFlag   = flag.String("root", "hxroot", "the Go package for the Haxe top-level package")
+	ifFlag     = flag.String("if", "", "the Haxe compile-time condition for the generated calls")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: haxe2go [flags] haxe.xml [type ...]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() < 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	top, err := readXML(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "haxe2go:", err)
+		os.Exit(1)
+	}
+	g := &generator{
+		source:  flag.Arg(0),
+		out:     *outFlag,
+		prefix:  *prefixFlag,
+		rootPkg: *rootFlag,
+		ifLogic: *ifFlag,
+	}
+	if err := g.choose(top, flag.Args()[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "haxe2go:", err)
+		os.Exit(1)
+	}
+	if err := g.generate(); err != nil {
+		fmt.Fprintln(os.Stderr, "haxe2go:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2014 Elliott Stoneham and The TARDIS Go Authors
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/xml"
+	"os"
+	"strings"
+)
+
+// A node is an element of the XML written by "haxe -xml".
+//
+// The top-level elements are class (including interfaces), enum, typedef and abstract, each with a path attribute.
+// The child elements of a class are its fields, named by the field, followed by meta, haxe_doc and so on;
+// the first child of a field gives its type:
+//
+//	<x path="Int"/>           an abstract, including Bool, Int, Float and Void
+//	<c path="String"/>        a class or interface
+//	<e path="haxe.io.Error"/> an enum
+//	<t path="Null"><x path="Int"/></t> a typedef, with its type parameters
+//	<f a="name:?opt">...</f>  a function, with a type for each argument then the return type
+//	<d/>, <a>...</a>          Dynamic and anonymous structures
+type node struct {
+	XMLName xml.Name
+	Attrs   []xml.Attr `xml:",any,attr"`
+	Nodes   []node     `xml:",any"`
+	Text    string     `xml:",chardata"`
+}
+
+func readXML(fileName string) (*node, error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	top := &node{}
+	if err := xml.NewDecoder(f).Decode(top); err != nil {
+		return nil, err
+	}
+	return top, nil
+}
+
+func (n *node) name() string {
+	return n.XMLName.Local
+}
+
+func (n *node) attr(name string) string {
+	for _, a := range n.Attrs {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+func (n *node) child(name string) *node {
+	for i := range n.Nodes {
+		if n.Nodes[i].name() == name {
+			return &n.Nodes[i]
+		}
+	}
+	return nil
+}
+
+// isType returns true if the node describes a type, rather than a field or meta data.
+func (n *node) isType() bool {
+	switch n.name() {
+	case "x", "c", "e", "t", "f", "d", "a", "unknown":
+		return true
+	}
+	return false
+}
+
+// fieldType returns the type of a field or enum constructor, or nil if the node is not one.
+func (n *node) fieldType() *node {
+	for i := range n.Nodes {
+		if n.Nodes[i].isType() {
+			return &n.Nodes[i]
+		}
+	}
+	return nil
+}
+
+// types returns the child nodes that are types, such as the argument and return types of a function.
+func (n *node) types() []*node {
+	var r []*node
+	for i := range n.Nodes {
+		if n.Nodes[i].isType() {
+			r = append(r, &n.Nodes[i])
+		}
+	}
+	return r
+}
+
+// doc returns the lines of the Haxe documentation of the node, without the leading stars.
+func (n *node) doc() []string {
+	d := n.child("haxe_doc")
+	if d == nil {
+		return nil
+	}
+	var lines []string
+	for _, l := range strings.Split(d.Text, "\n") {
+		l = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(l), "*"))
+		if l != "" || (len(lines) > 0 && lines[len(lines)-1] != "") {
+			lines = append(lines, l)
+		}
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
@@ -18,6 +18,7 @@ func (l langType) haxeruntime() string {
 
 class Console {
 	public static inline function naclWrite(v:String){
+		HostStdio.flush(); // keep the order of output
 		#if ( cpp || cs || java || neko || php || python )
 			Sys.print(v);
 		#else
@@ -25,6 +26,7 @@ class Console {
 		#end
 	}
 	public static inline function println(v:Array<Dynamic>) {
+		HostStdio.flush(); // keep the order of output
 		#if ( cpp || cs || java || neko || php || python )
 			Sys.println(join(v));
 		#else
@@ -32,6 +34,7 @@ class Console {
 		#end
 	}
 	public static inline function print(v:Array<Dynamic>) {
+		HostStdio.flush(); // keep the order of output
 		#if ( cpp || cs || java || neko || php || python )
 			Sys.print(join(v));
 		#else
@@ -461,11 +464,11 @@ class Force { // TODO maybe this should not be a separate haxe class, as no non-
 		if(Std.is(v,String)){
 			v=toHaxeString(v);
 		}
-		// TODO !
-		//if(GOint64.is(v)) { 
-		//	v=cast(v,haxe.Int64);
-		//}
-		return v;
+		return v; // a Go int or uint in a GOint64 is converted to an Int by the caller, other 64-bit values stay GOint64
+	}
+	public static function toHaxeIntParam(v:Dynamic,intTyp:Int,uintTyp:Int):Dynamic { // toHaxeParam, with Go int and uint as Int
+		if(Std.is(v,Interface) && (v.typ==intTyp || v.typ==uintTyp)) return toInt(v);
+		return toHaxeParam(v);
 	}
 	
 	public static #if (cpp || neko || php) inline #end function toHaxeString(v:String):String {
@@ -617,6 +620,26 @@ class Object {
 		#end
 	}
 
+	// The kinds of native typed array that may hold the memory of an Object, rather than the general purpose storage.
+	// Such an Object is only accessed as a sequence of items of that type, as the memory of a slice of it is.
+	public static inline var kindUint8:Int=1; // haxe.io.UInt8Array
+	public static inline var kindInt32:Int=2; // haxe.io.Int32Array
+	public static inline var kindFloat64:Int=3; // haxe.io.Float64Array
+	public static inline function makeKind(kind:Int,size:Int,?byts:haxe.io.Bytes):Object {
+		#if (abstractobjects || fullunsafe || notypedarrays)
+			return make(size,byts);
+		#else
+			return new Object(size,byts,kind);
+		#end
+	}
+	public inline function getKind():Int {
+		#if (abstractobjects || fullunsafe || notypedarrays)
+			return 0;
+		#else
+			return kind;
+		#end
+	}
+
 	#if ((js || cpp || neko) && fullunsafe) 
 		public static var nativeFloats:Bool=true; 
 	#else
@@ -640,6 +663,10 @@ class Object {
 		private var dView:js.html.DataView;
 	#elseif !fullunsafe	// Simple! 1 address per byte, non-Int types are always on 4-byte
 		private var iVec:haxe.ds.Vector<Int>; 
+		private var kind:Int; // 0 for the storage above, otherwise the only one of the native typed arrays below in use
+		private var u8:haxe.io.UInt8Array;
+		private var i32:haxe.io.Int32Array; // one entry per 4 bytes
+		private var f64:haxe.io.Float64Array; // one entry per 8 bytes
 	#else // fullunsafe position is to allow unsafe pointers, and therefore run slowly...
 		private var byts:haxe.io.Bytes;
 	#end
@@ -662,8 +689,8 @@ class Object {
     	this = v;
   	}
 #else
-	public function new(byteSize:Int,?bytes:haxe.io.Bytes){ // size is in bytes
-		dVec4 = new haxe.ds.Vector<Dynamic>(1+(byteSize>>2)); // +1 to make sure non-zero
+	public function new(byteSize:Int,?bytes:haxe.io.Bytes,kind:Int=0){ // size is in bytes
+		if(kind==0) dVec4 = new haxe.ds.Vector<Dynamic>(1+(byteSize>>2)); // +1 to make sure non-zero
 		if(bytes!=null) byteSize = bytes.length;
 		#if (js && fullunsafe)
 			arrayBuffer = new js.html.ArrayBuffer(byteSize);
@@ -673,10 +700,22 @@ class Object {
 				for(i in 0 ... byteSize) 
 					set_uint8(i, bytes.get(i));
 		#elseif !fullunsafe
-			iVec = new haxe.ds.Vector<Int>(byteSize);
-			if(bytes!=null)
-				for(i in 0 ... byteSize) 
-					iVec[i] = bytes.get(i);
+			this.kind=kind;
+			if(kind==kindUint8) {
+				u8 = new haxe.io.UInt8Array(byteSize);
+				if(bytes!=null)
+					for(i in 0 ... byteSize) 
+						u8[i] = bytes.get(i);
+			} else if(kind==kindInt32) {
+				i32 = new haxe.io.Int32Array(byteSize>>2);
+			} else if(kind==kindFloat64) {
+				f64 = new haxe.io.Float64Array(byteSize>>3);
+			} else {
+				iVec = new haxe.ds.Vector<Int>(byteSize);
+				if(bytes!=null)
+					for(i in 0 ... byteSize) 
+						iVec[i] = bytes.get(i);
+			}
 		#else
 			if(bytes==null)	{
 				byts = haxe.io.Bytes.alloc(byteSize);
@@ -691,11 +730,33 @@ class Object {
 		#end
 		#if nonulltests
 			#if (js || php || neko ) 
-				for(i in 0...length)
-					set_uint8(i,0); 
+				if(kind==0)
+					for(i in 0...length)
+						set_uint8(i,0); 
 			#end
 		#end
 	}
+#end
+#if !abstractobjects
+	public function twin():Object { // a new Object sharing the same memory, used to give finalizers a pointer to an unreachable Object
+		var t:Object=Type.createEmptyInstance(Object);
+		t.dVec4=dVec4;
+		#if (js && fullunsafe)
+			t.arrayBuffer=arrayBuffer;
+			t.dView=dView;
+		#elseif !fullunsafe
+			t.iVec=iVec;
+			t.kind=kind;
+			t.u8=u8;
+			t.i32=i32;
+			t.f64=f64;
+		#else
+			t.byts=byts;
+		#end
+		t.length=length;
+		t.uRef=uRef; // so that pointers to the twin are equal to those of the original
+		return t;
+	}
 #end
 	public function getBytes():haxe.io.Bytes {
 		#if (js && fullunsafe)
@@ -708,14 +769,25 @@ class Object {
 				byts.set(i,this[i]);
 		#elseif !fullunsafe
 			var byts = haxe.io.Bytes.alloc(length);
-			for(i in 0 ... length) 
-				byts.set(i,iVec[i]);
+			if(kind==kindUint8)
+				for(i in 0 ... length) 
+					byts.set(i,u8[i]);
+			else if(kind!=0)
+				return get_object(length,0).getBytes();
+			else
+				for(i in 0 ... length) 
+					byts.set(i,iVec[i]);
 		#else
 			// the byts field already exists
 		#end
 		return byts;
 	}
 	public function clear():Object {
+		#if !(abstractobjects || fullunsafe)
+			if(kind==kindUint8) { for(i in 0...u8.length) u8[i]=0; return this; }
+			if(kind==kindInt32) { for(i in 0...i32.length) i32[i]=0; return this; }
+			if(kind==kindFloat64) { for(i in 0...f64.length) f64[i]=0.0; return this; }
+		#end
 		for(i in 0...this.length){
 			set_uint8(i,0);
 			if(i&3==0) set(i,null);
@@ -724,6 +796,10 @@ class Object {
 	}
 	public function isEqual(off:Int,target:Object,tgtOff:Int):Bool { // TODO check if correct, used by interface{} value comparison
 		if((this.length-off)!=(target.len()-tgtOff)) return false;
+		#if !(abstractobjects || fullunsafe)
+			if(kind!=0) return get_object(length-off,off).isEqual(0,target,tgtOff);
+			if(target.kind!=0) return isEqual(off,target.get_object(target.len()-tgtOff,tgtOff),0);
+		#end
 		for(i in 0...(this.length-off)) {
 			if((i+off)&3==0){
 				var a:Dynamic=this.get(i+off);
@@ -836,12 +912,44 @@ class Object {
 		#elseif abstractobjects
 			haxe.ds.Vector.blit(src,srcPos, dest, destPos, size); 
 		#else //if !fullunsafe
+			if(src.kind!=0 || dest.kind!=0) {
+				kindBlit(src,srcPos,dest,destPos,size);
+				return;
+			}
 			if((size>>2)>0)
 				haxe.ds.Vector.blit(src.dVec4,srcPos>>2, dest.dVec4, destPos>>2, size>>2); 
 			haxe.ds.Vector.blit(src.iVec,srcPos, dest.iVec, destPos, size); 
 		#end
 		} // end of: if(size>0&&src!=null) {
 	}
+	#if !(abstractobjects || fullunsafe)
+	static function kindBlit(src:Object,srcPos:Int,dest:Object,destPos:Int,size:Int):Void{ // at least one Object is a typed array
+		var kind:Int = src.kind!=0 ? src.kind : dest.kind;
+		#if js
+			if(src.kind==dest.kind) { // the native set() copies overlapping items correctly
+				if(kind==kindUint8) 
+					dest.u8.getData().set(src.u8.getData().subarray(srcPos,srcPos+size),destPos);
+				else if(kind==kindInt32) 
+					dest.i32.getData().set(src.i32.getData().subarray(srcPos>>2,(srcPos+size)>>2),destPos>>2);
+				else 
+					dest.f64.getData().set(src.f64.getData().subarray(srcPos>>3,(srcPos+size)>>3),destPos>>3);
+				return;
+			}
+		#end
+		var step:Int = kind==kindUint8 ? 1 : kind==kindInt32 ? 4 : 8;
+		var i:Int = 0;
+		if(src==dest && srcPos<destPos) { // copy from the end, so that overlapping items are read before they are written
+			i = size-step;
+			step = -step;
+		}
+		while(i>=0 && i<size) {
+			if(kind==kindUint8) dest.set_uint8(destPos+i,src.get_uint8(srcPos+i));
+			else if(kind==kindInt32) dest.set_int32(destPos+i,src.get_int32(srcPos+i));
+			else dest.set_float64(destPos+i,src.get_float64(srcPos+i));
+			i += step;
+		}
+	}
+	#end
 	public inline function get_object(size:Int,from:Int):Object { // TODO SubObj class that is effectively a pointer?
 		var so:Object = make(size);
 		objBlit(this,from, so, 0, size); 
@@ -911,7 +1019,8 @@ class Object {
 		#elseif abstractobjects
 			#if (js || php || neko ) return this[i]==null?0:0|this[i]; #else return this[i]; #end
 		#elseif !fullunsafe
-			#if ((js || php || neko )&&!nonulltests) return iVec[i]==null?0:0|iVec[i]; #else return iVec[i]; #end
+			return kind==kindInt32 ? i32[i>>2] :
+				#if ((js || php || neko )&&!nonulltests) (iVec[i]==null?0:0|iVec[i]); #else iVec[i]; #end
 		#else
 			return Force.toInt32((get_uint16(i+2)<<16)|get_uint16(i)); // little end 1st			
 		#end
@@ -930,7 +1039,8 @@ class Object {
 		#elseif abstractobjects
 			#if (js || php || neko ) return this[i]==null?0:0|this[i]; #else return this[i]; #end
 		#elseif !fullunsafe
-			#if ((js || php || neko )&&!nonulltests) return iVec[i]==null?0:0|iVec[i]; #else return iVec[i]; #end
+			return kind==kindUint8 ? u8[i] :
+				#if ((js || php || neko )&&!nonulltests) (iVec[i]==null?0:0|iVec[i]); #else iVec[i]; #end
 		#else 
 			return Force.toUint8(byts.get(i));
 		#end
@@ -982,7 +1092,7 @@ class Object {
 		#if (js && fullunsafe)
 			return dView.getFloat64(i,true); // little-endian
 		#elseif !fullunsafe
-			return get(i)==null?0.0:get(i); 
+			return kind==kindFloat64 ? f64[i>>3] : (get(i)==null?0.0:get(i)); 
 		#else
 			return byts.getDouble(i); // Go_haxegoruntime_FFloat64frombits.callFromRT(0,get_uint64(i)); 		
 		#end
@@ -1057,11 +1167,14 @@ class Object {
 		#elseif abstractobjects
 			set(i,v);//this[i]=v==0?null:v;
 		#elseif !fullunsafe
-			#if ((js || php || neko ) &&!nonulltests)
-				iVec[i]=v==0?null:v; 
-			#else
-				iVec[i]=v;
-			#end
+			if(kind==kindInt32) i32[i>>2]=v;
+			else {
+				#if ((js || php || neko ) &&!nonulltests)
+					iVec[i]=v==0?null:v; 
+				#else
+					iVec[i]=v;
+				#end
+			}
 		#else
 			set_int16(i,v);
 			set_int16(i+2,v>>16); 
@@ -1082,10 +1195,13 @@ class Object {
 		#elseif abstractobjects
 			set(i,v);//this[i]=v==0?null:v;
 		#elseif !fullunsafe
-			iVec[i]=v;
-			#if ((js || php || neko ) &&!nonulltests)
-				if(iVec[i]==0) iVec[i]=null; 
-			#end
+			if(kind==kindUint8) u8[i]=v;
+			else {
+				iVec[i]=v;
+				#if ((js || php || neko ) &&!nonulltests)
+					if(iVec[i]==0) iVec[i]=null; 
+				#end
+			}
 		#else
 			byts.set(i,v&0xff);
 		#end
@@ -1170,16 +1286,19 @@ class Object {
 	 	#if (js && fullunsafe)
 			dView.setFloat64(i,v,true); // little-endian
 		#elseif !fullunsafe
-			#if (js || php || neko ) 
-				if(v==0.0) {
-					#if !php
-					var t:Float=1/v; // result is +/- infinity
-					if(t>MinFloat64) // ie not -0
-					#end
-						v=null;
-				} 
-			#end
-			set(i,v);
+			if(kind==kindFloat64) f64[i>>3]=v;
+			else {
+				#if (js || php || neko ) 
+					if(v==0.0) {
+						#if !php
+						var t:Float=1/v; // result is +/- infinity
+						if(t>MinFloat64) // ie not -0
+						#end
+							v=null;
+					} 
+				#end
+				set(i,v);
+			}
 		#else
 			#if (cpp||neko)
 				byts.setDouble(i,v);
@@ -1205,6 +1324,9 @@ class Object {
 		return v==null?"nil":Std.is(v,Pointer)?v.toUniqueVal():Std.string(v);
 	}
 	public function toString(addr:Int=0,count:Int=-1):String{
+		#if !(abstractobjects || fullunsafe)
+			if(kind!=0) return get_object(length,0).toString(addr,count);
+		#end
 		if(count==-1) count=this.length;
 		if(addr<0) addr=0;
 		if(count<0 || count>(this.length-addr)) count = this.length-addr;
@@ -1417,7 +1539,7 @@ class Slice {
 		return fromBytes(haxe.Resource.getBytes(name));
 	}
 	public static function fromBytes(res:haxe.io.Bytes):Slice {
-		var obj = res==null?Object.make(0):Object.make(res.length,res); 
+		var obj = res==null?Object.make(0):Object.makeKind(Object.kindUint8,res.length,res); 
 		var ptr = Pointer.make(obj);
 		var ret = new Slice(ptr,0,-1,res==null?0:res.length,1); // []byte
 		#if nulltempvars
@@ -1451,11 +1573,17 @@ class Slice {
 		if(oldEnt.cap()>=(oldEnt.len()+newEnt.len())){
 			var retEnt=new Slice(oldEnt.baseArray,oldEnt.start,oldEnt.end,oldEnt.capacity,oldEnt.itemSize);
 			var offset=retEnt.len();
-			for(i in 0...newEnt.len()){
-				retEnt.end++; 
-				//retEnt.itemAddr(offset+i).store_object(oldEnt.itemSize,newEnt.itemAddr(i).load_object(newEnt.itemSize));
-				Object.objBlit(newEnt.baseArray.obj,newEnt.itemOff(i)+newEnt.baseArray.off,
-					retEnt.baseArray.obj,retEnt.itemOff(offset+i)+retEnt.baseArray.off,oldEnt.itemSize);
+			if(retEnt.baseArray.obj!=newEnt.baseArray.obj) { // no overlap, so copy all the items in one go
+				retEnt.end+=newEnt.len();
+				Object.objBlit(newEnt.baseArray.obj,newEnt.itemOff(0)+newEnt.baseArray.off,
+					retEnt.baseArray.obj,retEnt.itemOff(offset)+retEnt.baseArray.off,newEnt.len()*oldEnt.itemSize);
+			} else {
+				for(i in 0...newEnt.len()){
+					retEnt.end++; 
+					//retEnt.itemAddr(offset+i).store_object(oldEnt.itemSize,newEnt.itemAddr(i).load_object(newEnt.itemSize));
+					Object.objBlit(newEnt.baseArray.obj,newEnt.itemOff(i)+newEnt.baseArray.off,
+						retEnt.baseArray.obj,retEnt.itemOff(offset+i)+retEnt.baseArray.off,oldEnt.itemSize);
+				}
 			}
 			#if nulltempvars
 				oldEnt=null;newEnt=null;
@@ -1465,18 +1593,14 @@ class Slice {
 		}else{
 			var newLen = oldEnt.length+newEnt.len();
 			var newCap = newLen+(newLen>>2); // NOTE auto-create 50pc new capacity 
-			var newObj:Object = Object.make(newCap*oldEnt.itemSize);
-			for(i in 0...oldEnt.length) {
-				//newObj.set_object(oldEnt.itemSize,i*oldEnt.itemSize,oldEnt.itemAddr(i).load_object(oldEnt.itemSize));
-				Object.objBlit(oldEnt.baseArray.obj,oldEnt.itemOff(i)+oldEnt.baseArray.off,
-					newObj,i*oldEnt.itemSize,oldEnt.itemSize);
-			}
-			for(i in 0...newEnt.len()){
-				//newObj.set_object(oldEnt.itemSize,
-				//	oldEnt.length*oldEnt.itemSize+i*oldEnt.itemSize,newEnt.itemAddr(i).load_object(oldEnt.itemSize));
-				Object.objBlit(newEnt.baseArray.obj,newEnt.itemOff(i)+newEnt.baseArray.off,
-					newObj,(oldEnt.length*oldEnt.itemSize)+(i*oldEnt.itemSize),oldEnt.itemSize);
-			}
+			var kind = oldEnt.baseArray.obj.getKind(); // keep any native typed array storage for the items
+			if(kind==0) kind = newEnt.baseArray.obj.getKind();
+			var newObj:Object = Object.makeKind(kind,newCap*oldEnt.itemSize);
+			// the new Object cannot overlap either of the others, so copy each in one go
+			Object.objBlit(oldEnt.baseArray.obj,oldEnt.itemOff(0)+oldEnt.baseArray.off,
+				newObj,0,oldEnt.length*oldEnt.itemSize);
+			Object.objBlit(newEnt.baseArray.obj,newEnt.itemOff(0)+newEnt.baseArray.off,
+				newObj,oldEnt.length*oldEnt.itemSize,newEnt.len()*oldEnt.itemSize);
 			var ptr = Pointer.make(newObj);
 			var ret = new Slice(ptr,0,newLen,newCap,oldEnt.itemSize);
 			#if nulltempvars
@@ -1512,13 +1636,10 @@ class Slice {
 					i-=1;
 				}
 			}
-		}else{
-			for(i in 0...copySize){
-				//target.itemAddr(i).store_object(target.itemSize,source.itemAddr(i).load_object(target.itemSize));
-				Object.objBlit(source.baseArray.obj,source.itemOff(i)+source.baseArray.off,
-					target.baseArray.obj,target.itemOff(i)+target.baseArray.off,
-					target.itemSize);
-			}
+		}else{ // no overlap, so copy all the items in one go
+			Object.objBlit(source.baseArray.obj,source.itemOff(0)+source.baseArray.off,
+				target.baseArray.obj,target.itemOff(0)+target.baseArray.off,
+				copySize*target.itemSize);
 		}
 		target.setLength();
 		return copySize;
@@ -1560,6 +1681,7 @@ class Slice {
 		return capacity-start;
 	}
 `
+	sliceClass += l.typedSliceAccessors()
 	if l.PogoComp().DebugFlag { // Normal range checking should cover this, so only in debug mode
 		sliceClass += `
 	public function itemAddr(idx:Int):Pointer {
@@ -1819,6 +1941,7 @@ var oldest_entry:Int;
 var closed:Bool;
 var capa:Int;
 var uniqueId:Int;
+var waiters:Array<Channel>=null; // woken by any change to this channel, see addWaiter()
 
 static var nextId:Int=0;
 
@@ -1847,6 +1970,7 @@ public function send(source:Dynamic):Bool {
 		next_element = (oldest_entry + num_entries) % max_entries;
 		num_entries++;
 		entries[next_element]=source;  
+		wake();
 		return true;
 	} 
 	return false;
@@ -1867,6 +1991,7 @@ public function receive(zero:Dynamic):{r0:Dynamic ,r1:Bool} {
 		ret=entries[oldest_entry];
 		oldest_entry = (oldest_entry + 1) % max_entries;
 		num_entries--;
+		wake();
 		return {r0:ret,r1:true};
 	} else
 		if(closed)
@@ -1885,6 +2010,26 @@ public inline function cap():Int {
 public function close() {
 	if(this==null) Scheduler.panicFromHaxe( "attempt to close a nil channel" ); 
 	closed = true;
+	wake();
+}
+// addWaiter makes every send, receive or close on ch also send true to w, if it has space,
+// so that a goroutine can wait on w for any one of several channels to change, as reflect.Select() does
+public static function addWaiter(ch:Channel,w:Channel) {
+	if(ch==null) return; // nil channels never change
+	if(ch.waiters==null) ch.waiters=new Array<Channel>();
+	ch.waiters.push(w);
+}
+public static function removeWaiter(ch:Channel,w:Channel) {
+	if(ch!=null && ch.waiters!=null) ch.waiters.remove(w);
+}
+inline function wake() {
+	if(waiters!=null)
+		for(w in waiters)
+			if(hasSpace(w)) w.send(true);
+}
+public static function isClosed(ch:Channel):Bool { // used by reflect
+	if (ch==null) return false;
+	return ch.closed;
 }
 public function toString():String{
 	return "<ChanId:"+Std.string(uniqueId)+">";
@@ -2211,8 +2356,12 @@ public static function ucompare(x:HaxeInt64abs,y:HaxeInt64abs):Int {
 	//#end
 }
 }
-
+`+nativeInt64Class()+`
+#if ((cpp || java || cs) && !emulateint64)
+	typedef GOint64 = GOint64native;
+#else
 	typedef GOint64 = HaxeInt64abs;
+#end
 
 //**************** rewrite of std Haxe library function haxe.Int64 for PHP integer overflow an other errors
 /*
@@ -2842,6 +2991,11 @@ public static function runAll() { // this must be re-entrant, in order to allow
 		if(grStacksLen>1) // we must always have goroutine 0
 			if(grStacks[grStacksLen-1].length==0) 
 				grStacks.pop();
+
+		GOgc.tick(); // wake the finalizer goroutine if the host has found unreachable Objects
+		HostNet.tick(); // wake the goroutines waiting for host sockets that have changed
+		HostProc.tick(); // and for host processes
+		HostOS.tick(); // and for host signals
 	}
 	#if nulltempvars
 		thisStack=null; // for GC
@@ -2857,6 +3011,7 @@ static inline function runOne(gr:Int,entryCount:Int,thisStack:Array<StackFrame>,
 			while(grInPanic[gr]){
 				if(grStacks[gr].length==0){
 					 Console.naclWrite("Panic in goroutine "+gr+"\n"+panicStackDump); // use stored stack dump
+					 HostOS.exit(2); // as Go does for an unrecovered panic, where the target allows it
 					 throw "Go panic";
 				} else {
 					var sf:StackFrame=grStacks[gr].pop();
@@ -3040,6 +3195,7 @@ public static function panicFromHaxe(err:String) {
 	else
 		panic(currentGR,new Interface(TypeInfo.getId("string"),"Runtime panic, "+err+" "));
 	Console.naclWrite(panicStackDump); 
+	HostOS.exit(2); // as Go does for an unrecovered panic, where the target allows it
 	throw "Haxe panic"; // NOTE can't be recovered!
 }
 public static function bbi() {
@@ -3055,6 +3211,10 @@ public static function htc(c:Dynamic,pos:Int) {
 public static #if inlinepointers inline #end function wraprangechk(val:Int,sz:Int) {
 	if((val<0)||(val>=sz)) ioor();
 }
+public static function wraprangechk64(val:GOint64,sz:Int) { // for 64-bit indexes
+	if(GOint64.getHigh(val)!=0) ioor();
+	wraprangechk(GOint64.getLow(val),sz);
+}
 public static function unt():Dynamic {
 		panicFromHaxe("nil interface target for method");	
 		return null;
@@ -3231,8 +3391,8 @@ class GOstringRange {
 		if(k>=v.len())
 			return {r0:false,r1:0,r2:0};
 		else {
-			var _dr:{r0:Int,r1:Int}=Go_unicode_slsh_utf8_DDecodeRRune.callFromRT(g,v.subSlice(_thisK,-1));
-			k+=_dr.r1;
+			var _dr:{r0:Int,r1:`+l.intLangType()+`}=Go_unicode_slsh_utf8_DDecodeRRune.callFromRT(g,v.subSlice(_thisK,-1));
+			k+=`+l.intArg("_dr.r1")+`; // the size is a Go int
 			return {r0:true,r1:_thisK,r2:_dr.r0};
 		}
 	}
@@ -3240,6 +3400,14 @@ class GOstringRange {
 
 
 `)
+	l.PogoComp().WriteAsClass("GOgc", gcClass())
+	l.PogoComp().WriteAsClass("HostFS", hostFSClass())
+	l.PogoComp().WriteAsClass("BrowserStore", browserStoreClass())
+	l.PogoComp().WriteAsClass("HostNet", hostNetClass())
+	l.PogoComp().WriteAsClass("HostProc", hostProcClass())
+	l.PogoComp().WriteAsClass("HostClock", hostClockClass())
+	l.PogoComp().WriteAsClass("HostStdio", hostStdioClass())
+	l.PogoComp().WriteAsClass("HostOS", hostOSClass())
 
 	return ""
 }
@@ -12,6 +12,7 @@ import (
 // haxeContext contains the context of a haxe code generation run
 type haxeContext struct {
 	pogoComp *pogo.Compilation // the host compilation context
+	sizes    types.StdSizes    // of the Go types in this compilation, as given to the type checker
 
 	useRegisterArray bool // should we use an array rather than individual register vars
 
@@ -32,6 +33,8 @@ type haxeContext struct {
 
 	map1usePtr map[ssa.Value]oneUsePtr
 
+	scalarAllocs map[string]*ssa.Alloc // the allocations in this function held in individual Haxe variables, by register name
+
 	localFunctionMap map[int]string
 	thisBlock        int
 
@@ -43,9 +46,18 @@ type haxeContext struct {
 
 	tempVarList []regToFree
 
-	typesByID []types.Type
-	pte       typeutil.Map
-	pteKeys   []types.Type
+	typesByID    []types.Type
+	pte          typeutil.Map
+	pteKeys      []types.Type
+	minimalTypes map[int]bool // the type ids given only an rtype, as reflect cannot reach them
+
+	hxTypes       *hxTypeDump // the Haxe types to check hx pseudo-function calls against, see hxcheck.go
+	hxTypesFailed bool        // HxTypesFile could not be read
+
+	exports     *exporter    // the Haxe facade classes for exported Go code, see export.go
+	convTypes   typeutil.Map // the index of each type in convList
+	convList    []types.Type // the types converted by hx.ToHaxe() and hx.FromHaxe(), see typeconv.go
+	convDynamic bool         // some conversions depend on the run-time type
 
 	langEntry *pogo.LanguageEntry
 }
@@ -60,6 +72,13 @@ func (l langType) InitLang(comp *pogo.Compilation, langEnt *pogo.LanguageEntry)
 		langEntry: langEnt,
 	}}
 	ret.hc.funcNamesUsed = make(map[string]bool)
+	ret.hc.sizes = types.StdSizes{ // must agree with the sizes used by the type checker
+		WordSize: 4, // word size in bytes - must be >= 4 (32bits)
+		MaxAlign: 8, // maximum alignment in bytes - must be >= 1
+	}
+	if comp.WordSize >= 4 {
+		ret.hc.sizes.WordSize = comp.WordSize
+	}
 	return ret
 }
 func (l langType) PogoComp() *pogo.Compilation {
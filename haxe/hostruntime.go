@@ -0,0 +1,1713 @@
+// Copyright 2014 Elliott Stoneham and The TARDIS Go Authors
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package haxe
+
+// Links to the host operating system, for those targets that have one.
+
+// The HostFS class gives package syscall access to the host file system, see syscall/fs_host_haxe.go.
+// It is available for the sys targets (cpp, cs, java, neko) and for js when running under node.
+// For js in a browser the same functions give access to the BrowserStore instead.
+// The results of stat() are left in static variables, to avoid returning a Haxe structure to Go.
+func hostFSClass() string {
+	return `
+
+class HostFS {
+	public static var statIsDir:Bool=false;
+	public static var statMode:Int=0;
+	public static var statSize:Float=0.0;
+	public static var statMtime:Float=0.0; // in seconds
+
+	#if js
+	static var nodeFS:Dynamic=null;
+	static function fs():Dynamic {
+		if(nodeFS==null && untyped __js__("typeof process!=='undefined' && process.versions!=null && process.versions.node!=null"))
+			nodeFS=untyped __js__("require('fs')");
+		return nodeFS;
+	}
+	#end
+
+	public static function available():Bool {
+		#if (cpp || cs || java || neko)
+			return true;
+		#elseif js
+			return fs()!=null;
+		#else
+			return false;
+		#end
+	}
+
+	public static function stat(p:String):Bool {
+		try {
+			#if (cpp || cs || java || neko)
+				if(!sys.FileSystem.exists(p)) return false;
+				var s=sys.FileSystem.stat(p);
+				statIsDir=sys.FileSystem.isDirectory(p);
+				statMode=s.mode;
+				statSize=s.size;
+				statMtime=s.mtime.getTime()/1000.0;
+				return true;
+			#elseif js
+				if(fs()==null) return BrowserStore.stat(p);
+				var s:Dynamic=fs().statSync(p);
+				statIsDir=s.isDirectory();
+				statMode=s.mode;
+				statSize=s.size;
+				statMtime=s.mtime.getTime()/1000.0;
+				return true;
+			#end
+		} catch(e:Dynamic) {}
+		return false;
+	}
+
+	// readDir returns the names in the directory separated by "/", as that cannot be part of a name
+	public static function readDir(p:String):String {
+		try {
+			#if (cpp || cs || java || neko)
+				return sys.FileSystem.readDirectory(p).join("/");
+			#elseif js
+				if(fs()==null) return BrowserStore.readDir(p);
+				var a:Array<String>=fs().readdirSync(p);
+				return a.join("/");
+			#end
+		} catch(e:Dynamic) {}
+		return "";
+	}
+
+	// readInto reads the start of the file into the []byte Slice, returning the number of bytes read, or -1 on error
+	public static function readInto(p:String,sl:Slice):Int {
+		try {
+			#if (cpp || cs || java || neko)
+				var b=sys.io.File.getBytes(p);
+				var n=b.length<Slice.nullLen(sl)?b.length:Slice.nullLen(sl);
+				for(i in 0...n) sl.itemAddr(i).store_uint8(b.get(i));
+				return n;
+			#elseif js
+				if(fs()==null) return BrowserStore.readInto(p,sl);
+				var b:Dynamic=fs().readFileSync(p);
+				var l:Int=b.length;
+				var n=l<Slice.nullLen(sl)?l:Slice.nullLen(sl);
+				for(i in 0...n) sl.itemAddr(i).store_uint8(b[i]);
+				return n;
+			#end
+		} catch(e:Dynamic) {}
+		return -1;
+	}
+
+	public static function saveBytes(p:String,sl:Slice):Bool {
+		var n=Slice.nullLen(sl);
+		try {
+			#if (cpp || cs || java || neko)
+				var b=haxe.io.Bytes.alloc(n);
+				for(i in 0...n) b.set(i,sl.itemAddr(i).load_uint8());
+				sys.io.File.saveBytes(p,b);
+				return true;
+			#elseif js
+				if(fs()==null) return BrowserStore.saveBytes(p,sl);
+				var b:Dynamic=(untyped __js__("Buffer"))(n);
+				for(i in 0...n) b[i]=sl.itemAddr(i).load_uint8();
+				fs().writeFileSync(p,b);
+				return true;
+			#end
+		} catch(e:Dynamic) {}
+		return false;
+	}
+
+	public static function rename(from:String,to:String):Bool {
+		try {
+			#if (cpp || cs || java || neko)
+				sys.FileSystem.rename(from,to);
+				return true;
+			#elseif js
+				if(fs()==null) return BrowserStore.rename(from,to);
+				fs().renameSync(from,to);
+				return true;
+			#end
+		} catch(e:Dynamic) {}
+		return false;
+	}
+
+	public static function deleteFile(p:String):Bool {
+		try {
+			#if (cpp || cs || java || neko)
+				sys.FileSystem.deleteFile(p);
+				return true;
+			#elseif js
+				if(fs()==null) return BrowserStore.deleteFile(p);
+				fs().unlinkSync(p);
+				return true;
+			#end
+		} catch(e:Dynamic) {}
+		return false;
+	}
+
+	public static function deleteDir(p:String):Bool {
+		try {
+			#if (cpp || cs || java || neko)
+				sys.FileSystem.deleteDirectory(p);
+				return true;
+			#elseif js
+				if(fs()==null) return BrowserStore.deleteDir(p);
+				fs().rmdirSync(p);
+				return true;
+			#end
+		} catch(e:Dynamic) {}
+		return false;
+	}
+
+	public static function createDir(p:String):Bool {
+		try {
+			#if (cpp || cs || java || neko)
+				sys.FileSystem.createDirectory(p);
+				return true;
+			#elseif js
+				if(fs()==null) return BrowserStore.createDir(p);
+				fs().mkdirSync(p);
+				return true;
+			#end
+		} catch(e:Dynamic) {}
+		return false;
+	}
+}
+`
+}
+
+// The BrowserStore class gives package syscall a persistent file store in a browser, see syscall/fs_store_haxe.go.
+// The whole store is held in memory, in files, and each change is written through to IndexedDB or,
+// if that is not available, to localStorage. Loading from IndexedDB is asynchronous,
+// so start() calls done when it has finished, which can only happen when the JS event loop runs.
+func browserStoreClass() string {
+	return `
+
+class BrowserStore {
+	#if js
+	public static var eventLoop:Bool=false; // set by haxegoruntime.BrowserMain(), when Go code lets the JS event loop run
+	static var state:Int=0; // 0=not loaded, 1=loading, 2=ready
+	static var files:Map<String,Dynamic>=new Map<String,Dynamic>(); // path -> {d:is a directory, m:mtime in seconds, b:haxe.io.Bytes}
+	static var db:Dynamic=null; // the IndexedDB database, if it is being used
+	static var ls:Dynamic=null; // localStorage, if it is being used
+	static inline var prefix:String="tardisgo:"; // for the localStorage keys
+
+	public static function available():Bool {
+		try {
+			return untyped __js__("typeof window!=='undefined' && (window.indexedDB!=null || window.localStorage!=null)");
+		} catch(e:Dynamic) {}
+		return false;
+	}
+
+	public static function ready():Bool {
+		return state==2;
+	}
+
+	public static function start(?done:Void->Void):Void {
+		if(state!=0) return;
+		state=1;
+		var finish=function() {
+			state=2;
+			if(done!=null) done();
+		};
+		var idb:Dynamic=null;
+		try {
+			idb=untyped __js__("window.indexedDB");
+		} catch(e:Dynamic) {}
+		if(idb==null) {
+			loadLocal();
+			finish();
+			return;
+		}
+		try {
+			var req:Dynamic=idb.open("tardisgo",1);
+			req.onupgradeneeded=function(e:Dynamic) {
+				req.result.createObjectStore("files");
+			};
+			req.onerror=function(e:Dynamic) {
+				loadLocal();
+				finish();
+			};
+			req.onsuccess=function(e:Dynamic) {
+				db=req.result;
+				var cur:Dynamic=db.transaction("files","readonly").objectStore("files").openCursor();
+				cur.onsuccess=function(e:Dynamic) {
+					var c:Dynamic=cur.result;
+					if(c==null) {
+						finish();
+						return;
+					}
+					var v:Dynamic=c.value;
+					files.set(c.key,{d:v.d,m:v.m,b:haxe.io.Bytes.ofData(v.b)});
+					Reflect.callMethod(c,Reflect.field(c,"continue"),[]);
+				};
+				cur.onerror=function(e:Dynamic) {
+					db=null;
+					files=new Map<String,Dynamic>();
+					loadLocal();
+					finish();
+				};
+			};
+		} catch(e:Dynamic) {
+			db=null;
+			loadLocal();
+			finish();
+		}
+	}
+
+	// loadLocal reads the store from localStorage, where each value is "D" or "F", the mtime, a newline, then one char per byte
+	static function loadLocal() {
+		try {
+			ls=untyped __js__("window.localStorage");
+		} catch(e:Dynamic) {
+			ls=null;
+		}
+		if(ls==null) return; // the store only lasts as long as the page
+		var n:Int=ls.length;
+		for(i in 0...n) {
+			var k:String=ls.key(i);
+			if(k!=null && k.indexOf(prefix)==0) {
+				var v:String=ls.getItem(k);
+				var nl=v.indexOf("\n");
+				var b=haxe.io.Bytes.alloc(v.length-nl-1);
+				for(j in 0...b.length) b.set(j,v.charCodeAt(nl+1+j));
+				files.set(k.substr(prefix.length),{d:v.charAt(0)=="D",m:Std.parseFloat(v.substr(1,nl-1)),b:b});
+			}
+		}
+	}
+
+	// persist writes the current state of the path to IndexedDB or localStorage
+	static function persist(p:String) {
+		var f:Dynamic=files.get(p);
+		try {
+			if(db!=null) {
+				var os:Dynamic=db.transaction("files","readwrite").objectStore("files");
+				if(f==null) os.delete(p);
+				else os.put({d:f.d,m:f.m,b:f.b.getData()},p);
+			} else if(ls!=null) {
+				if(f==null) ls.removeItem(prefix+p);
+				else {
+					var b:haxe.io.Bytes=f.b;
+					var buf=new StringBuf();
+					buf.add(f.d?"D":"F");
+					buf.add(f.m);
+					buf.add("\n");
+					for(i in 0...b.length) buf.addChar(b.get(i));
+					ls.setItem(prefix+p,buf.toString());
+				}
+			}
+		} catch(e:Dynamic) {} // the storage may be full, but the data remains in memory
+	}
+
+	static function children(p:String):Array<String> {
+		var r=new Array<String>();
+		var pre=p=="/"?"/":p+"/";
+		for(k in files.keys())
+			if(k.length>pre.length && k.substr(0,pre.length)==pre && k.indexOf("/",pre.length)==-1)
+				r.push(k.substr(pre.length));
+		return r;
+	}
+
+	// The functions below behave like those of the same name in HostFS.
+
+	public static function stat(p:String):Bool {
+		var f:Dynamic=files.get(p);
+		if(f==null) return false;
+		HostFS.statIsDir=f.d;
+		HostFS.statMode=f.d?0x1ff:0x1b6; // 0777 or 0666
+		HostFS.statSize=f.b.length;
+		HostFS.statMtime=f.m;
+		return true;
+	}
+
+	public static function readDir(p:String):String {
+		return children(p).join("/");
+	}
+
+	public static function readInto(p:String,sl:Slice):Int {
+		var f:Dynamic=files.get(p);
+		if(f==null || f.d) return -1;
+		var b:haxe.io.Bytes=f.b;
+		var n=b.length<Slice.nullLen(sl)?b.length:Slice.nullLen(sl);
+		for(i in 0...n) sl.itemAddr(i).store_uint8(b.get(i));
+		return n;
+	}
+
+	public static function saveBytes(p:String,sl:Slice):Bool {
+		var f:Dynamic=files.get(p);
+		if(f!=null && f.d) return false;
+		var n=Slice.nullLen(sl);
+		var b=haxe.io.Bytes.alloc(n);
+		for(i in 0...n) b.set(i,sl.itemAddr(i).load_uint8());
+		files.set(p,{d:false,m:Date.now().getTime()/1000.0,b:b});
+		persist(p);
+		return true;
+	}
+
+	public static function rename(from:String,to:String):Bool {
+		var f:Dynamic=files.get(from);
+		if(f==null) return false;
+		var t:Dynamic=files.get(to);
+		if(t!=null && (t.d!=f.d || children(to).length>0)) return false;
+		var moves=[from];
+		if(f.d) {
+			var pre=from+"/";
+			for(k in files.keys())
+				if(k.substr(0,pre.length)==pre) moves.push(k);
+		}
+		for(k in moves) {
+			var nk=to+k.substr(from.length);
+			files.set(nk,files.get(k));
+			files.remove(k);
+			persist(k);
+			persist(nk);
+		}
+		return true;
+	}
+
+	public static function deleteFile(p:String):Bool {
+		var f:Dynamic=files.get(p);
+		if(f==null || f.d) return false;
+		files.remove(p);
+		persist(p);
+		return true;
+	}
+
+	public static function deleteDir(p:String):Bool {
+		var f:Dynamic=files.get(p);
+		if(f==null || !f.d || children(p).length>0) return false;
+		files.remove(p);
+		persist(p);
+		return true;
+	}
+
+	public static function createDir(p:String):Bool {
+		if(files.exists(p)) return false;
+		files.set(p,{d:true,m:Date.now().getTime()/1000.0,b:haxe.io.Bytes.alloc(0)});
+		persist(p);
+		return true;
+	}
+	#end
+}
+`
+}
+
+// The HostNet class gives package syscall access to host TCP and UDP sockets, see syscall/net_host_haxe.go.
+// It is available for the sys targets (cpp, cs, java, neko, although UDP is only available for cpp and neko)
+// and for js when running under node. Sockets are identified by a handle, an index into socks.
+// None of these functions block, instead they return -1 if the operation should be tried again later, and -2 on error.
+// A goroutine that has to wait adds a Go channel as a waiter on the socket, which is sent true when the socket changes:
+// under node by the event callbacks, on the sys targets by tick(), which polls the sockets with waiters from Scheduler.runAll(),
+// as there the host gives no notice, and a TCP connection is made by a thread, so that it does not block the program.
+// Under node, events are only delivered when the Go code gives control back to the JS event loop.
+func hostNetClass() string {
+	return `
+
+#if cpp
+typedef HostNetThread = cpp.vm.Thread;
+typedef HostNetDeque<T> = cpp.vm.Deque<T>;
+#elseif neko
+typedef HostNetThread = neko.vm.Thread;
+typedef HostNetDeque<T> = neko.vm.Deque<T>;
+#elseif java
+typedef HostNetThread = java.vm.Thread;
+typedef HostNetDeque<T> = java.vm.Deque<T>;
+#elseif cs
+typedef HostNetThread = cs.vm.Thread;
+typedef HostNetDeque<T> = cs.vm.Deque<T>;
+#end
+
+class HostNet {
+	public static var addrHost:String=""; // the result of getAddr() or recvFrom()
+	public static var addrPort:Int=0;
+
+	static var socks:Array<Dynamic>=[];
+	// sock gives the state of a socket, with the data that has arrived but not yet been read
+	static function sock(s:Dynamic,kind:String,ready:Bool):Dynamic {
+		return {s:s,kind:kind,ready:ready,err:false,eof:false,chunks:[],off:0,pending:[],packets:[],waiters:null,
+			connecting:null,blockedWrite:false};
+	}
+	static function add(o:Dynamic):Int {
+		for(i in 0...socks.length)
+			if(socks[i]==null) {
+				socks[i]=o;
+				return i;
+			}
+		socks.push(o);
+		return socks.length-1;
+	}
+
+	// addWaiter makes the next change to the socket send true to the Go channel w, see hostWait() in package syscall
+	public static function addWaiter(h:Int,w:Channel) {
+		var o=socks[h];
+		if(o==null) { // closed, so there is nothing to wait for
+			if(Channel.hasSpace(w)) w.send(true);
+			return;
+		}
+		if(o.waiters==null) o.waiters=new Array<Channel>();
+		o.waiters.push(w);
+		#if (cpp || cs || java || neko)
+			if(waiting.indexOf(o)<0) waiting.push(o);
+		#end
+	}
+	static function wake(o:Dynamic) {
+		var ws:Array<Channel>=o.waiters;
+		if(ws==null) return;
+		o.waiters=null;
+		for(w in ws)
+			if(Channel.hasSpace(w)) w.send(true);
+	}
+
+	// tick wakes the waiters on the sockets that have changed, called by Scheduler.runAll()
+	public static function tick() {
+		#if (cpp || cs || java || neko)
+			if(waiting.length==0) return;
+			var ws=waiting;
+			waiting=[];
+			for(o in ws)
+				if(o.waiters!=null) {
+					if(poll(o)) wake(o);
+					else waiting.push(o);
+				}
+		#end
+	}
+
+	// drain moves the data that has arrived for a TCP socket into the []byte Slice, returning the number of bytes moved
+	static function drain(o:Dynamic,sl:Slice,n:Int):Int {
+		var c:Array<Dynamic>=o.chunks;
+		var r=0;
+		while(r<n && c.length>0) {
+			#if js
+				var b:Dynamic=c[0];
+			#else
+				var b:haxe.io.Bytes=c[0];
+			#end
+			var l:Int=b.length;
+			while(r<n && o.off<l) {
+				#if js
+					sl.itemAddr(r).store_uint8(b[o.off]);
+				#else
+					sl.itemAddr(r).store_uint8(b.get(o.off));
+				#end
+				r++;
+				o.off++;
+			}
+			if(o.off>=l) {
+				c.shift();
+				o.off=0;
+			}
+		}
+		return r;
+	}
+
+	#if js
+	static var net:Dynamic=null;
+	static var dgram:Dynamic=null;
+	static function nodeWrap(s:Dynamic,ready:Bool):Dynamic {
+		var o:Dynamic=sock(s,"tcp",ready);
+		s.on("data",function(b:Dynamic){o.chunks.push(b);wake(o);});
+		s.on("end",function(){o.eof=true;wake(o);});
+		s.on("error",function(e:Dynamic){o.err=true;wake(o);});
+		return o;
+	}
+	static function toBuffer(sl:Slice,n:Int):Dynamic {
+		var b:Dynamic=(untyped __js__("Buffer"))(n);
+		for(i in 0...n) b[i]=sl.itemAddr(i).load_uint8();
+		return b;
+	}
+	#else
+	static function toBytes(sl:Slice,n:Int):haxe.io.Bytes {
+		var b=haxe.io.Bytes.alloc(n);
+		for(i in 0...n) b.set(i,sl.itemAddr(i).load_uint8());
+		return b;
+	}
+	#end
+
+	#if (cpp || cs || java || neko)
+	static var waiting:Array<Dynamic>=[]; // the sockets with waiters, which tick() polls
+
+	// poll reads ahead from a socket with waiters, returning true if it has changed, so that they should be woken
+	static function poll(o:Dynamic):Bool {
+		if(o.err) return true;
+		if(o.connecting!=null) {
+			var q:HostNetDeque<String>=o.connecting;
+			var r=q.pop(false);
+			if(r==null) return false;
+			o.connecting=null;
+			if(r=="") {
+				o.s.setBlocking(false);
+				o.ready=true;
+			} else
+				o.err=true;
+			return true;
+		}
+		if(o.blockedWrite) { // there is no way to tell when a write would succeed, so let it try again
+			o.blockedWrite=false;
+			return true;
+		}
+		switch(o.kind) {
+			case "tcp":
+				if(o.eof) return true;
+				var b=haxe.io.Bytes.alloc(4096);
+				try {
+					var n=o.s.input.readBytes(b,0,b.length);
+					if(n<=0) return false;
+					o.chunks.push(b.sub(0,n));
+				} catch(e:haxe.io.Eof) {
+					o.eof=true;
+				} catch(e:haxe.io.Error) {
+					switch(e) {
+						case Blocked: return false;
+						default: o.err=true;
+					}
+				} catch(e:Dynamic) {
+					o.err=true;
+				}
+				return true;
+			case "server":
+				try {
+					var c:sys.net.Socket=o.s.accept();
+					c.setBlocking(false);
+					o.pending.push(add(sock(c,"tcp",true)));
+					return true;
+				} catch(e:Dynamic) {}
+				return false;
+			#if (cpp || neko)
+			case "udp":
+				var b=haxe.io.Bytes.alloc(65536);
+				var a=new sys.net.Address();
+				try {
+					var n=o.s.readFrom(b,0,b.length,a);
+					o.packets.push({m:b.sub(0,n),host:a.getHost().toString(),port:a.port});
+					return true;
+				} catch(e:Dynamic) {} // no packet is waiting
+				return false;
+			#end
+		}
+		return true;
+	}
+	#end
+
+	public static function available():Bool {
+		#if (cpp || cs || java || neko)
+			return true;
+		#elseif js
+			if(net==null && untyped __js__("typeof process!=='undefined' && process.versions!=null && process.versions.node!=null")) {
+				net=untyped __js__("require('net')");
+				dgram=untyped __js__("require('dgram')");
+			}
+			return net!=null;
+		#else
+			return false;
+		#end
+	}
+
+	public static function tcpConnect(host:String,port:Int):Int {
+		try {
+			#if (cpp || cs || java || neko)
+				var s=new sys.net.Socket();
+				var q=new HostNetDeque<String>(); // "" once connected, otherwise the error
+				HostNetThread.create(function() { // as connecting blocks
+					try {
+						s.connect(new sys.net.Host(host),port);
+						q.add("");
+					} catch(e:Dynamic) {
+						q.add("error: "+Std.string(e));
+					}
+				});
+				var o=sock(s,"tcp",false);
+				o.connecting=q;
+				return add(o);
+			#elseif js
+				var o=nodeWrap(net.connect(port,host),false);
+				o.s.on("connect",function(){o.ready=true;wake(o);});
+				return add(o);
+			#end
+		} catch(e:Dynamic) {}
+		return -2;
+	}
+
+	public static function tcpListen(host:String,port:Int,backlog:Int):Int {
+		try {
+			#if (cpp || cs || java || neko)
+				var s=new sys.net.Socket();
+				s.bind(new sys.net.Host(host),port);
+				s.listen(backlog);
+				s.setBlocking(false);
+				return add(sock(s,"server",true));
+			#elseif js
+				var o:Dynamic=sock(null,"server",false);
+				o.s=net.createServer(function(c:Dynamic){o.pending.push(add(nodeWrap(c,true)));wake(o);});
+				o.s.on("listening",function(){o.ready=true;wake(o);});
+				o.s.on("error",function(e:Dynamic){o.err=true;wake(o);});
+				o.s.listen(port,host,backlog);
+				return add(o);
+			#end
+		} catch(e:Dynamic) {}
+		return -2;
+	}
+
+	public static function udpBind(host:String,port:Int):Int {
+		try {
+			#if (cpp || neko)
+				var s=new sys.net.UdpSocket();
+				s.bind(new sys.net.Host(host),port);
+				s.setBlocking(false);
+				return add(sock(s,"udp",true));
+			#elseif js
+				var o:Dynamic=sock(dgram.createSocket("udp4"),"udp",false);
+				o.s.on("message",function(m:Dynamic,r:Dynamic){o.packets.push({m:m,host:r.address,port:r.port});wake(o);});
+				o.s.on("listening",function(){o.ready=true;wake(o);});
+				o.s.on("error",function(e:Dynamic){o.err=true;wake(o);});
+				o.s.bind(port,host);
+				return add(o);
+			#end
+		} catch(e:Dynamic) {}
+		return -2;
+	}
+
+	// status returns 1 once the socket is connected, listening or bound, -1 before then, or -2 on error
+	public static function status(h:Int):Int {
+		var o=socks[h];
+		#if (cpp || cs || java || neko)
+			if(o!=null && o.connecting!=null) poll(o);
+		#end
+		if(o==null || o.err) return -2;
+		return o.ready ? 1 : -1;
+	}
+
+	// getAddr puts the local or remote address of the socket into addrHost and addrPort
+	public static function getAddr(h:Int,remote:Bool):Bool {
+		var o=socks[h];
+		if(o==null) return false;
+		try {
+			#if (cpp || cs || java || neko)
+				var a:{host:sys.net.Host,port:Int}=remote?o.s.peer():o.s.host();
+				addrHost=a.host.toString();
+				addrPort=a.port;
+				return true;
+			#elseif js
+				if(remote) {
+					addrHost=o.s.remoteAddress;
+					addrPort=o.s.remotePort;
+				} else {
+					var a:Dynamic=o.s.address();
+					addrHost=a.address;
+					addrPort=a.port;
+				}
+				return addrHost!=null;
+			#end
+		} catch(e:Dynamic) {}
+		return false;
+	}
+
+	public static function accept(h:Int):Int {
+		var o=socks[h];
+		if(o==null || o.err) return -2;
+		var p:Array<Int>=o.pending;
+		if(p.length>0) return p.shift(); // accepted by poll() or a node callback
+		#if (cpp || cs || java || neko)
+			try {
+				var c:sys.net.Socket=o.s.accept();
+				c.setBlocking(false);
+				return add(sock(c,"tcp",true));
+			} catch(e:Dynamic) {}
+			return -1; // no connection is waiting
+		#elseif js
+			return -1;
+		#else
+			return -2;
+		#end
+	}
+
+	// read returns the number of bytes read into the []byte Slice, or 0 at the end of the stream
+	public static function read(h:Int,sl:Slice):Int {
+		var o=socks[h];
+		if(o==null) return -2;
+		var n=Slice.nullLen(sl);
+		var r=drain(o,sl,n);
+		if(r>0) return r;
+		if(o.err) return -2;
+		if(o.eof) return 0;
+		#if (cpp || cs || java || neko)
+			var b=haxe.io.Bytes.alloc(n);
+			try {
+				r=o.s.input.readBytes(b,0,n);
+			} catch(e:haxe.io.Eof) {
+				return 0;
+			} catch(e:haxe.io.Error) {
+				switch(e) {
+					case Blocked: return -1;
+					default: return -2;
+				}
+			} catch(e:Dynamic) {
+				return -2;
+			}
+			if(r==0) return -1;
+			for(i in 0...r) sl.itemAddr(i).store_uint8(b.get(i));
+			return r;
+		#elseif js
+			return -1;
+		#else
+			return -2;
+		#end
+	}
+
+	// write returns the number of bytes written from the []byte Slice
+	public static function write(h:Int,sl:Slice):Int {
+		var o=socks[h];
+		if(o==null || o.err) return -2;
+		var n=Slice.nullLen(sl);
+		#if (cpp || cs || java || neko)
+			try {
+				return o.s.output.writeBytes(toBytes(sl,n),0,n);
+			} catch(e:haxe.io.Error) {
+				switch(e) {
+					case Blocked:
+						o.blockedWrite=true;
+						return -1;
+					default: return -2;
+				}
+			} catch(e:Dynamic) {}
+			return -2;
+		#elseif js
+			o.s.write(toBuffer(sl,n)); // node buffers the data
+			return n;
+		#else
+			return -2;
+		#end
+	}
+
+	// recvFrom reads a packet into the []byte Slice, returning its length, with the sender in addrHost and addrPort
+	public static function recvFrom(h:Int,sl:Slice):Int {
+		var o=socks[h];
+		if(o==null || o.err) return -2;
+		var n=Slice.nullLen(sl);
+		var p:Array<Dynamic>=o.packets;
+		if(p.length>0) { // received by poll() or a node callback
+			var m=p.shift();
+			var l:Int=m.m.length;
+			var r=l<n ? l : n;
+			for(i in 0...r) {
+				#if js
+					sl.itemAddr(i).store_uint8(m.m[i]);
+				#else
+					sl.itemAddr(i).store_uint8(m.m.get(i));
+				#end
+			}
+			addrHost=m.host;
+			addrPort=m.port;
+			return r;
+		}
+		#if (cpp || neko)
+			var b=haxe.io.Bytes.alloc(n);
+			var a=new sys.net.Address();
+			var r=0;
+			try {
+				r=o.s.readFrom(b,0,n,a);
+			} catch(e:haxe.io.Error) {
+				switch(e) {
+					case Blocked: return -1;
+					default: return -2;
+				}
+			} catch(e:Dynamic) {
+				return -1; // some targets throw when no packet is waiting
+			}
+			for(i in 0...r) sl.itemAddr(i).store_uint8(b.get(i));
+			addrHost=a.getHost().toString();
+			addrPort=a.port;
+			return r;
+		#elseif js
+			return -1;
+		#else
+			return -2;
+		#end
+	}
+
+	public static function sendTo(h:Int,sl:Slice,host:String,port:Int):Int {
+		var o=socks[h];
+		if(o==null || o.err) return -2;
+		var n=Slice.nullLen(sl);
+		try {
+			#if (cpp || neko)
+				var a=new sys.net.Address();
+				a.host=new sys.net.Host(host).ip;
+				a.port=port;
+				return o.s.sendTo(toBytes(sl,n),0,n,a);
+			#elseif js
+				o.s.send(toBuffer(sl,n),0,n,port,host);
+				return n;
+			#end
+		} catch(e:Dynamic) {}
+		return -2;
+	}
+
+	public static function shutdown(h:Int,read:Bool,write:Bool) {
+		var o=socks[h];
+		if(o==null || o.kind!="tcp") return;
+		try {
+			#if (cpp || cs || java || neko)
+				o.s.shutdown(read,write);
+			#elseif js
+				if(write) o.s.end();
+			#end
+		} catch(e:Dynamic) {}
+	}
+
+	public static function close(h:Int) {
+		var o=socks[h];
+		if(o==null) return;
+		socks[h]=null;
+		wake(o); // so that any waiting goroutine finds the socket closed
+		try {
+			#if js
+				if(o.kind=="tcp") o.s.end(); else o.s.close();
+			#else
+				o.s.close();
+			#end
+		} catch(e:Dynamic) {}
+	}
+}
+`
+}
+
+// The HostProc class gives package syscall the ability to start host processes, see syscall/exec_host_haxe.go.
+// It is available for the sys targets (cpp, cs, java, neko) and for js when running under node.
+// Processes are identified by a handle, an index into procs. As reading from or writing to a process blocks
+// on the sys targets, each process there has threads to do that, which pass the data through a Deque.
+// None of these functions block, instead they return -1 if the operation should be tried again later, and -2 on error.
+// A goroutine that has to wait adds a Go channel as a waiter on the process, which is sent true when it changes:
+// under node by the event callbacks, on the sys targets by tick(), which polls the Deques from Scheduler.runAll().
+// On the sys targets a process with its own environment or directory is started by /bin/sh, except on Windows,
+// where they cannot be set, and a signal other than SIGKILL is sent using the host kill command.
+func hostProcClass() string {
+	return `
+
+#if cpp
+typedef HostProcThread = cpp.vm.Thread;
+typedef HostProcDeque<T> = cpp.vm.Deque<T>;
+#elseif neko
+typedef HostProcThread = neko.vm.Thread;
+typedef HostProcDeque<T> = neko.vm.Deque<T>;
+#elseif java
+typedef HostProcThread = java.vm.Thread;
+typedef HostProcDeque<T> = java.vm.Deque<T>;
+#elseif cs
+typedef HostProcThread = cs.vm.Thread;
+typedef HostProcDeque<T> = cs.vm.Deque<T>;
+#end
+
+class HostProc {
+	static var procs:Array<Dynamic>=[];
+	static var args:Array<String>=[];
+	static var envs:Array<String>=[];
+
+	#if js
+	static var cp:Dynamic=null;
+	#end
+
+	// addWaiter makes the next change to the process or its output send true to the Go channel w,
+	// see hostProc.wait() in package syscall
+	public static function addWaiter(h:Int,w:Channel) {
+		var o=procs[h];
+		if(o==null) { // released, so there is nothing to wait for
+			if(Channel.hasSpace(w)) w.send(true);
+			return;
+		}
+		if(o.waiters==null) o.waiters=new Array<Channel>();
+		o.waiters.push(w);
+		#if (cpp || cs || java || neko)
+			if(waiting.indexOf(o)<0) waiting.push(o);
+		#end
+	}
+	static function wake(o:Dynamic) {
+		var ws:Array<Channel>=o.waiters;
+		if(ws==null) return;
+		o.waiters=null;
+		for(w in ws)
+			if(Channel.hasSpace(w)) w.send(true);
+	}
+
+	// tick wakes the waiters on the processes that have changed, called by Scheduler.runAll()
+	public static function tick() {
+		#if (cpp || cs || java || neko)
+			if(waiting.length==0) return;
+			var ws=waiting;
+			waiting=[];
+			for(o in ws)
+				if(o.waiters!=null) {
+					if(poll(o)) wake(o);
+					else waiting.push(o);
+				}
+		#end
+	}
+
+	public static function available():Bool {
+		#if (cpp || cs || java || neko)
+			return true;
+		#elseif js
+			if(cp==null && untyped __js__("typeof process!=='undefined' && process.versions!=null && process.versions.node!=null"))
+				cp=untyped __js__("require('child_process')");
+			return cp!=null;
+		#else
+			return false;
+		#end
+	}
+
+	// the arguments and environment for the next start() are added one at a time, to avoid passing a Go []string
+	public static function clear() {
+		args=[];
+		envs=[];
+	}
+	public static function addArg(a:String) {
+		args.push(a);
+	}
+	public static function addEnv(kv:String) {
+		envs.push(kv);
+	}
+
+	#if (cpp || cs || java || neko)
+	static var waiting:Array<Dynamic>=[]; // the processes with waiters, which tick() polls
+
+	// poll takes what the threads have passed on for a process with waiters, returning true if there is anything
+	static function poll(o:Dynamic):Bool {
+		var changed=false;
+		for(s in [o.out,o.err])
+			if(s.chunk==null && !s.eof) {
+				var q:HostProcDeque<haxe.io.Bytes>=s.q;
+				var b=q.pop(false);
+				if(b!=null) {
+					if(b.length==0) s.eof=true;
+					else {
+						s.chunk=b;
+						s.off=0;
+					}
+					changed=true;
+				}
+			}
+		if(o.code<0) {
+			var q:HostProcDeque<Int>=o.status;
+			var c:Null<Int>=q.pop(false);
+			if(c!=null) {
+				o.code=c;
+				changed=true;
+			}
+		}
+		return changed;
+	}
+
+	// command gives the host command and arguments that start cmd in dir, with the variables in envs added to the environment,
+	// using a shell so as not to change the environment or current directory of this program, which other threads use
+	static function command(cmd:String,dir:String):{cmd:String,args:Array<String>} {
+		if(envs.length==0 && dir=="") return {cmd:cmd,args:args};
+		if(Sys.systemName()=="Windows") { // there is no portable wrapper, so the environment and directory cannot be set
+			return {cmd:cmd,args:args};
+		}
+		var a=["-c","cd -- \"$0\" && exec \"$@\"",dir==""?".":dir];
+		if(envs.length>0) {
+			a.push("env");
+			for(kv in envs) a.push(kv);
+		}
+		a.push(cmd);
+		return {cmd:"/bin/sh",args:a.concat(args)};
+	}
+
+	static function reader(inp:haxe.io.Input):Dynamic {
+		var o:Dynamic={q:new HostProcDeque<haxe.io.Bytes>(),chunk:null,off:0,eof:false};
+		var q:HostProcDeque<haxe.io.Bytes>=o.q;
+		HostProcThread.create(function() {
+			var b=haxe.io.Bytes.alloc(4096);
+			try {
+				while(true) {
+					var n=inp.readBytes(b,0,b.length);
+					if(n>0) q.add(b.sub(0,n));
+				}
+			} catch(e:Dynamic) {}
+			q.add(haxe.io.Bytes.alloc(0)); // end of file
+		});
+		return o;
+	}
+	#end
+
+	// start returns the handle of the new process, or -2 if it could not be started
+	public static function start(cmd:String,dir:String):Int {
+		var o:Dynamic=null;
+		try {
+			#if (cpp || cs || java || neko)
+				var c=command(cmd,dir);
+				var p:sys.io.Process=null;
+				try {
+					p=new sys.io.Process(c.cmd,c.args);
+				} catch(e:Dynamic) {}
+				if(p==null) return -2;
+				o={p:p,status:new HostProcDeque<Int>(),code:-1,out:reader(p.stdout),err:reader(p.stderr),
+					inq:new HostProcDeque<haxe.io.Bytes>(),inClosed:false,waiters:null};
+				var status:HostProcDeque<Int>=o.status;
+				HostProcThread.create(function() {
+					var c=255;
+					try {
+						c=p.exitCode();
+					} catch(e:Dynamic) {}
+					status.add((c&0xff)<<8);
+				});
+				var inq:HostProcDeque<haxe.io.Bytes>=o.inq;
+				HostProcThread.create(function() {
+					try {
+						while(true) {
+							var b=inq.pop(true);
+							if(b.length==0) break;
+							p.stdin.writeFullBytes(b,0,b.length);
+							p.stdin.flush();
+						}
+					} catch(e:Dynamic) {}
+					try p.stdin.close() catch(e:Dynamic) {}
+				});
+			#elseif js
+				var env:Dynamic={};
+				var penv:Dynamic=untyped __js__("process.env");
+				for(k in Reflect.fields(penv)) Reflect.setField(env,k,Reflect.field(penv,k));
+				for(kv in envs) {
+					var i=kv.indexOf("=");
+					if(i>0) Reflect.setField(env,kv.substr(0,i),kv.substr(i+1));
+				}
+				var opts:Dynamic={env:env};
+				if(dir!="") opts.cwd=dir;
+				var c:Dynamic=cp.spawn(cmd,args,opts);
+				if(c.pid==null) return -2; // the process could not be started
+				o={c:c,code:-1,inClosed:false,waiters:null,
+					out:{chunks:[],off:0,eof:false},err:{chunks:[],off:0,eof:false}};
+				c.on("error",function(e:Dynamic){});
+				c.stdin.on("error",function(e:Dynamic){o.inClosed=true;});
+				c.stdout.on("data",function(b:Dynamic){o.out.chunks.push(b);wake(o);});
+				c.stdout.on("end",function(){o.out.eof=true;wake(o);});
+				c.stderr.on("data",function(b:Dynamic){o.err.chunks.push(b);wake(o);});
+				c.stderr.on("end",function(){o.err.eof=true;wake(o);});
+				c.on("exit",function(code:Dynamic,sig:Dynamic) {
+					if(code!=null) o.code=(code&0xff)<<8;
+					else {
+						var n:Dynamic=Reflect.field(untyped __js__("require('os').constants.signals"),sig);
+						o.code=n==null?9:n;
+					}
+					wake(o);
+				});
+			#end
+		} catch(e:Dynamic) {
+			return -2;
+		}
+		for(i in 0...procs.length)
+			if(procs[i]==null) {
+				procs[i]=o;
+				return i;
+			}
+		procs.push(o);
+		return procs.length-1;
+	}
+
+	// read copies output from the process into the []byte Slice, which=1 for stdout or 2 for stderr,
+	// returning the number of bytes read, 0 at the end of the output or -1 if there is nothing to read yet
+	public static function read(h:Int,which:Int,sl:Slice):Int {
+		var o=procs[h];
+		if(o==null) return -2;
+		var s:Dynamic=which==2?o.err:o.out;
+		var n=Slice.nullLen(sl);
+		#if (cpp || cs || java || neko)
+			if(s.chunk==null) {
+				if(s.eof) return 0;
+				var q:HostProcDeque<haxe.io.Bytes>=s.q;
+				var b=q.pop(false);
+				if(b==null) return -1;
+				if(b.length==0) {
+					s.eof=true;
+					return 0;
+				}
+				s.chunk=b;
+				s.off=0;
+			}
+			var b:haxe.io.Bytes=s.chunk;
+			var r=b.length-s.off;
+			if(r>n) r=n;
+			for(i in 0...r) sl.itemAddr(i).store_uint8(b.get(s.off+i));
+			s.off+=r;
+			if(s.off>=b.length) s.chunk=null;
+			return r;
+		#elseif js
+			var chunks:Array<Dynamic>=s.chunks;
+			if(chunks.length==0) return s.eof?0:-1;
+			var b:Dynamic=chunks[0];
+			var l:Int=b.length;
+			var r=l-s.off;
+			if(r>n) r=n;
+			for(i in 0...r) sl.itemAddr(i).store_uint8(b[s.off+i]);
+			s.off+=r;
+			if(s.off>=l) {
+				chunks.shift();
+				s.off=0;
+			}
+			return r;
+		#else
+			return -2;
+		#end
+	}
+
+	// write sends the []byte Slice to the input of the process, which never blocks
+	public static function write(h:Int,sl:Slice):Int {
+		var o=procs[h];
+		if(o==null || o.inClosed) return -2;
+		var n=Slice.nullLen(sl);
+		try {
+			#if (cpp || cs || java || neko)
+				var b=haxe.io.Bytes.alloc(n);
+				for(i in 0...n) b.set(i,sl.itemAddr(i).load_uint8());
+				if(n>0) o.inq.add(b);
+				return n;
+			#elseif js
+				var b:Dynamic=(untyped __js__("Buffer"))(n);
+				for(i in 0...n) b[i]=sl.itemAddr(i).load_uint8();
+				o.c.stdin.write(b);
+				return n;
+			#end
+		} catch(e:Dynamic) {}
+		return -2;
+	}
+
+	public static function closeStdin(h:Int) {
+		var o=procs[h];
+		if(o==null || o.inClosed) return;
+		o.inClosed=true;
+		try {
+			#if (cpp || cs || java || neko)
+				o.inq.add(haxe.io.Bytes.alloc(0));
+			#elseif js
+				o.c.stdin.end();
+			#end
+		} catch(e:Dynamic) {}
+	}
+
+	// status returns -1 while the process is running, then its exit code<<8, or the number of the signal that ended it
+	public static function status(h:Int):Int {
+		var o=procs[h];
+		if(o==null) return -2;
+		#if (cpp || cs || java || neko)
+			if(o.code<0) {
+				var q:HostProcDeque<Int>=o.status;
+				var c:Null<Int>=q.pop(false);
+				if(c!=null) o.code=c;
+			}
+		#end
+		return o.code;
+	}
+
+	public static function kill(h:Int,sig:Int):Bool {
+		var o=procs[h];
+		if(o==null) return false;
+		try {
+			#if (cpp || cs || java || neko)
+				if(sig!=9 && Sys.systemName()!="Windows") { // sys.io.Process can only kill, so use the host kill command
+					var k=new sys.io.Process("kill",["-"+sig,Std.string(o.p.getPid())]);
+					var c=k.exitCode();
+					k.close();
+					return c==0;
+				}
+				o.p.kill(); // the signal cannot be chosen on Windows
+				return true;
+			#elseif js
+				return o.c.kill(sig);
+			#end
+		} catch(e:Dynamic) {}
+		return false;
+	}
+
+	public static function release(h:Int) {
+		closeStdin(h);
+		var o=procs[h];
+		procs[h]=null;
+		if(o!=null) wake(o); // so that any waiting goroutine finds the process released
+	}
+}
+`
+}
+
+// The HostClock class gives the Go runtime and packages time and syscall the host clocks and time zone.
+// The wall clock is given in seconds, and the monotonic clock in nanoseconds from its first use,
+// both as Floats to keep as much precision as the target gives.
+func hostClockClass() string {
+	return `
+
+class HostClock {
+	public static inline var noOffset:Int=-1000000; // result of offsetAt() when the offset is not known
+	static var monoBase:Float=-1.0;
+
+	// now returns the wall clock time in seconds since 1970
+	public static function now():Float {
+		#if js
+			return untyped __js__("Date.now()")/1000.0;
+		#elseif sys
+			return Sys.time();
+		#else
+			return Date.now().getTime()/1000.0;
+		#end
+	}
+
+	// mono returns a monotonic clock in seconds, from an arbitrary start
+	static function mono():Float {
+		#if js
+			if(untyped __js__("typeof process!=='undefined' && process.hrtime!=null")) {
+				var t:Array<Float>=untyped __js__("process.hrtime()");
+				return t[0]+t[1]/1000000000.0;
+			}
+			if(untyped __js__("typeof performance!=='undefined' && performance.now!=null"))
+				return untyped __js__("performance.now()")/1000.0;
+			return haxe.Timer.stamp();
+		#elseif java
+			return untyped __java__("(double)java.lang.System.nanoTime()")/1000000000.0;
+		#elseif cs
+			return untyped __cs__("(double)System.Diagnostics.Stopwatch.GetTimestamp()/System.Diagnostics.Stopwatch.Frequency");
+		#else
+			return haxe.Timer.stamp(); // hxcpp uses a monotonic clock for this
+		#end
+	}
+
+	public static function monoNano():Float {
+		var t=mono();
+		if(monoBase<0) monoBase=t;
+		return (t-monoBase)*1000000000.0;
+	}
+
+	// zoneName returns the name of the host time zone, if it can be found, for example "Europe/London"
+	public static function zoneName():String {
+		try {
+			#if sys
+				var tz=Sys.getEnv("TZ");
+				if(tz!=null && tz!="") return StringTools.startsWith(tz,":")?tz.substr(1):tz;
+			#end
+			#if js
+				var tz:Dynamic=untyped __js__("(typeof process!=='undefined' && process.env!=null) ? process.env.TZ : null");
+				if(tz!=null && tz!="") return StringTools.startsWith(tz,":")?tz.substr(1):tz;
+				tz=untyped __js__("(typeof Intl!=='undefined' && Intl.DateTimeFormat!=null) ? Intl.DateTimeFormat().resolvedOptions().timeZone : null");
+				if(tz!=null) return tz;
+			#elseif java
+				return untyped __java__("java.util.TimeZone.getDefault().getID()");
+			#elseif cs
+				return untyped __cs__("System.TimeZoneInfo.Local.Id");
+			#end
+		} catch(e:Dynamic) {}
+		return "";
+	}
+
+	static function daysFromCivil(y:Int,m:Int,d:Int):Int { // m is 1 to 12
+		if(m<=2) y--;
+		var era=Std.int((y>=0?y:y-399)/400);
+		var yoe=y-era*400;
+		var doy=Std.int((153*(m>2?m-3:m+9)+2)/5)+d-1;
+		var doe=yoe*365+Std.int(yoe/4)-Std.int(yoe/100)+doy;
+		return era*146097+doe-719468;
+	}
+
+	// offsetAt returns the offset of local time from UTC in seconds east, at the time sec seconds since 1970
+	public static function offsetAt(sec:Float):Int {
+		try {
+			var d=Date.fromTime(sec*1000.0);
+			#if js
+				return -Std.int(untyped d.getTimezoneOffset())*60;
+			#else
+				var local:Float=daysFromCivil(d.getFullYear(),d.getMonth()+1,d.getDate())*86400.0+
+					d.getHours()*3600+d.getMinutes()*60+d.getSeconds();
+				return Std.int(local-Math.ffloor(sec));
+			#end
+		} catch(e:Dynamic) {}
+		return noOffset;
+	}
+
+	// zoneAbbr returns the abbreviated name of the zone in use at the time sec seconds since 1970, or ""
+	public static function zoneAbbr(sec:Float):String {
+		#if js
+			try {
+				var fmt:Dynamic=untyped __js__("(typeof Intl!=='undefined' && Intl.DateTimeFormat!=null) ? new Intl.DateTimeFormat('en-US',{timeZoneName:'short'}) : null");
+				if(fmt!=null && fmt.formatToParts!=null) {
+					var parts:Array<Dynamic>=fmt.formatToParts(Date.fromTime(sec*1000.0));
+					for(p in parts)
+						if(p.type=="timeZoneName") return p.value;
+				}
+			} catch(e:Dynamic) {}
+		#end
+		return "";
+	}
+}
+`
+}
+
+// The HostStdio class binds the standard input, output and error of package syscall to those of the host,
+// see syscall/stdio_haxe.go. Output to stdout is buffered, by line if it is a terminal,
+// and flushed before stdin is read and when the program exits; output to stderr is not buffered.
+// Without a host stream, as in a browser, output is traced a line at a time and stdin is empty.
+// Reading stdin never blocks, instead read returns -1 if it should be tried again later.
+func hostStdioClass() string {
+	return `
+
+class HostStdio {
+	static var outBuf:haxe.io.BytesBuffer=new haxe.io.BytesBuffer();
+	static var errBuf:haxe.io.BytesBuffer=new haxe.io.BytesBuffer(); // only used when tracing
+	static var ttyChecked:Array<Bool>=[false,false,false];
+	static var tty:Array<Bool>=[false,false,false];
+
+	#if js
+	static var nodeFS:Dynamic=null;
+	static function node():Dynamic {
+		if(nodeFS==null && untyped __js__("typeof process!=='undefined' && process.versions!=null && process.versions.node!=null"))
+			nodeFS=untyped __js__("require('fs')");
+		return nodeFS;
+	}
+	static var inChunks:Array<Dynamic>=null; // when reading stdin using events
+	static var inOff:Int=0;
+	static var inEOF:Bool=false;
+	#elseif (cpp || cs || java || neko)
+	static var inQ:HostProc.HostProcDeque<haxe.io.Bytes>=null;
+	static var inChunk:haxe.io.Bytes=null;
+	static var inOff:Int=0;
+	static var inEOF:Bool=false;
+	#end
+
+	static function toBytes(sl:Slice):haxe.io.Bytes {
+		var n=Slice.nullLen(sl);
+		var b=haxe.io.Bytes.alloc(n);
+		for(i in 0...n) b.set(i,sl.itemAddr(i).load_uint8());
+		return b;
+	}
+
+	// put writes the bytes to the host stream for fd, 1 or 2
+	static function put(fd:Int,b:haxe.io.Bytes) {
+		if(b.length==0) return;
+		try {
+			#if (cpp || cs || java || neko || php || python)
+				var o=fd==2?Sys.stderr():Sys.stdout();
+				o.writeFullBytes(b,0,b.length);
+				o.flush();
+				return;
+			#elseif js
+				if(node()!=null) {
+					var buf:Dynamic=(untyped __js__("Buffer"))(b.length);
+					for(i in 0...b.length) buf[i]=b.get(i);
+					var off=0;
+					while(off<b.length) {
+						try {
+							off+=node().writeSync(fd,buf,off,b.length-off);
+						} catch(e:Dynamic) {
+							if(e.code!="EAGAIN") return;
+						}
+					}
+					return;
+				}
+			#end
+		} catch(e:Dynamic) {
+			return;
+		}
+		// trace complete lines, keeping any remainder until the next write or flush
+		var lb=fd==2?errBuf:outBuf;
+		lb.add(b);
+		var all=lb.getBytes();
+		var start=0;
+		for(i in 0...all.length)
+			if(all.get(i)==10) {
+				haxe.Log.trace(all.getString(start,i-start));
+				start=i+1;
+			}
+		if(fd==2) {
+			errBuf=new haxe.io.BytesBuffer();
+			errBuf.addBytes(all,start,all.length-start);
+		} else {
+			outBuf=new haxe.io.BytesBuffer();
+			outBuf.addBytes(all,start,all.length-start);
+		}
+	}
+
+	static function hasHost():Bool {
+		#if (cpp || cs || java || neko || php || python)
+			return true;
+		#elseif js
+			return node()!=null;
+		#else
+			return false;
+		#end
+	}
+
+	// write writes the []byte Slice to fd, 1 or 2, returning the number of bytes written
+	public static function write(fd:Int,sl:Slice):Int {
+		var b=toBytes(sl);
+		if(fd==2 || !hasHost()) {
+			if(fd==2) flush(); // keep the order of output
+			put(fd,b);
+			return b.length;
+		}
+		outBuf.add(b);
+		if(outBuf.length>=4096) flush();
+		else if(isatty(1))
+			for(i in 0...b.length)
+				if(b.get(i)==10) {
+					flush();
+					break;
+				}
+		return b.length;
+	}
+
+	// flush writes any buffered output for stdout, and any partial line being traced
+	public static function flush() {
+		if(hasHost()) {
+			var b=outBuf.getBytes();
+			outBuf=new haxe.io.BytesBuffer();
+			put(1,b);
+		} else {
+			for(lb in [outBuf,errBuf]) {
+				var b=lb.getBytes();
+				if(b.length>0) haxe.Log.trace(b.getString(0,b.length));
+			}
+			outBuf=new haxe.io.BytesBuffer();
+			errBuf=new haxe.io.BytesBuffer();
+		}
+	}
+
+	// read reads from stdin into the []byte Slice, returning the number of bytes read, 0 at the end,
+	// -1 if there is nothing to read yet or -2 on error
+	public static function read(sl:Slice):Int {
+		flush();
+		var n=Slice.nullLen(sl);
+		if(n==0) return 0;
+		try {
+			#if (cpp || cs || java || neko)
+				if(inQ==null) { // a thread reads stdin, a line at a time, as reads block
+					inQ=new HostProc.HostProcDeque<haxe.io.Bytes>();
+					var q=inQ;
+					HostProc.HostProcThread.create(function() {
+						var inp=Sys.stdin();
+						var line=new haxe.io.BytesBuffer();
+						try {
+							while(true) {
+								var c=inp.readByte();
+								line.addByte(c);
+								if(c==10 || line.length>=4096) {
+									q.add(line.getBytes());
+									line=new haxe.io.BytesBuffer();
+								}
+							}
+						} catch(e:Dynamic) {}
+						if(line.length>0) q.add(line.getBytes());
+						q.add(haxe.io.Bytes.alloc(0)); // end of file
+					});
+				}
+				if(inChunk==null) {
+					if(inEOF) return 0;
+					var b=inQ.pop(false);
+					if(b==null) return -1;
+					if(b.length==0) {
+						inEOF=true;
+						return 0;
+					}
+					inChunk=b;
+					inOff=0;
+				}
+				var r=inChunk.length-inOff;
+				if(r>n) r=n;
+				for(i in 0...r) sl.itemAddr(i).store_uint8(inChunk.get(inOff+i));
+				inOff+=r;
+				if(inOff>=inChunk.length) inChunk=null;
+				return r;
+			#elseif (php || python)
+				var inp=Sys.stdin(); // blocks the whole program
+				var r=0;
+				try {
+					while(r<n) {
+						var c=inp.readByte();
+						sl.itemAddr(r).store_uint8(c);
+						r++;
+						if(c==10) break;
+					}
+				} catch(e:Dynamic) {}
+				return r;
+			#elseif js
+				if(node()==null) return 0;
+				if(inChunks==null && BrowserStore.eventLoop) { // read using events, as the JS event loop is running
+					inChunks=[];
+					var stdin:Dynamic=untyped __js__("process.stdin");
+					stdin.on("data",function(b:Dynamic){inChunks.push(b);});
+					stdin.on("end",function(){inEOF=true;});
+				}
+				if(inChunks!=null) {
+					if(inChunks.length==0) return inEOF?0:-1;
+					var b:Dynamic=inChunks[0];
+					var l:Int=b.length;
+					var r=l-inOff;
+					if(r>n) r=n;
+					for(i in 0...r) sl.itemAddr(i).store_uint8(b[inOff+i]);
+					inOff+=r;
+					if(inOff>=l) {
+						inChunks.shift();
+						inOff=0;
+					}
+					return r;
+				}
+				var buf:Dynamic=(untyped __js__("Buffer"))(n);
+				var r:Int=0;
+				try {
+					r=node().readSync(0,buf,0,n,null); // blocks the whole program
+				} catch(e:Dynamic) {
+					if(e.code=="EAGAIN") return -1;
+					if(e.code=="EOF") return 0;
+					return -2;
+				}
+				for(i in 0...r) sl.itemAddr(i).store_uint8(buf[i]);
+				return r;
+			#else
+				return 0;
+			#end
+		} catch(e:Dynamic) {}
+		return -2;
+	}
+
+	// isatty returns true if fd (0, 1 or 2) is a terminal, as far as the host can tell
+	public static function isatty(fd:Int):Bool {
+		if(fd<0 || fd>2) return false;
+		if(ttyChecked[fd]) return tty[fd];
+		ttyChecked[fd]=true;
+		try {
+			#if js
+				if(node()!=null) tty[fd]=untyped __js__("require('tty')").isatty(fd);
+			#elseif java
+				tty[fd]=untyped __java__("java.lang.System.console()!=null");
+			#elseif cs
+				tty[fd]=fd==0 ? !untyped __cs__("System.Console.IsInputRedirected") :
+					fd==1 ? !untyped __cs__("System.Console.IsOutputRedirected") : !untyped __cs__("System.Console.IsErrorRedirected");
+			#elseif (cpp || neko)
+				if(Sys.systemName()!="Windows") { // where is the fd pointing?
+					var p=sys.FileSystem.fullPath("/proc/self/fd/"+fd);
+					tty[fd]=p!=null && (StringTools.startsWith(p,"/dev/pts/") || StringTools.startsWith(p,"/dev/tty") || p=="/dev/console");
+				}
+			#end
+		} catch(e:Dynamic) {}
+		return tty[fd];
+	}
+}
+`
+}
+
+// The HostOS class ends the program with an exit status, and delivers host signals to package os/signal,
+// see os/signal/signal_haxe.go, by sending true to the Go channel in ready when one arrives, from tick() for cs and java,
+// where the signals arrive on another thread. Signals can only be caught where the host allows it:
+// SIGINT and SIGTERM under node while the JS event loop runs, SIGINT on cs, and on java either of them,
+// as a shutdown hook which waits a short while for the Go program to exit by itself.
+func hostOSClass() string {
+	return `
+
+class HostOS {
+	static inline var sigINT:Int=2;
+	static inline var sigTERM:Int=15;
+	static var exiting:Bool=false;
+	static var wanted:Array<Bool>=[];
+	static var hooked:Array<Bool>=[];
+	public static var ready:Channel=null; // set by package os/signal
+	static var queued:Array<Int>=[]; // the signals to be delivered
+	#if (cs || java)
+	static var pending:HostProc.HostProcDeque<Int>=new HostProc.HostProcDeque<Int>(); // from other threads
+	#end
+	#if java
+	static var inHook:Bool=false;
+	#end
+
+	// exit flushes stdout and ends the program with the given status, if the target allows it, otherwise it returns
+	public static function exit(code:Int) {
+		HostStdio.flush();
+		exiting=true;
+		#if java
+			if(inHook) untyped __java__("java.lang.Runtime.getRuntime()").halt(code); // System.exit() would block
+		#end
+		#if (cpp || cs || java || macro || neko || php || python)
+			Sys.exit(code);
+		#elseif js
+			if(untyped __js__("typeof process!=='undefined' && process.exit!=null"))
+				untyped __js__("process").exit(code);
+		#end
+	}
+
+	// mainDone is called when the Go main function returns, ending the program unless the JS event loop is in use
+	public static function mainDone() {
+		HostStdio.flush();
+		if(!BrowserStore.eventLoop) exit(0);
+	}
+
+	// raise records that the host has sent sig, returning true if the Go program is waiting for it
+	public static function raise(sig:Int):Bool {
+		if(exiting || wanted[sig]!=true) return false;
+		#if (cs || java)
+			pending.add(sig);
+		#else
+			queued.push(sig);
+			tick();
+		#end
+		return true;
+	}
+
+	// tick wakes the goroutine waiting for signals if there are any to deliver, called by Scheduler.runAll()
+	public static function tick() {
+		#if (cs || java)
+			var s:Null<Int>=pending.pop(false);
+			while(s!=null) {
+				queued.push(s);
+				s=pending.pop(false);
+			}
+		#end
+		if(queued.length>0 && ready!=null && Channel.hasSpace(ready)) ready.send(true);
+	}
+
+	// nextSignal returns the next signal to be delivered, or -1 if there is none
+	public static function nextSignal():Int {
+		return queued.length==0 ? -1 : queued.shift();
+	}
+
+	// notify starts (on=true) or stops delivering sig to the Go program, returning false if it cannot be caught
+	public static function notify(sig:Int,on:Bool):Bool {
+		if(sig!=sigINT && sig!=sigTERM) return false;
+		if(!on) {
+			wanted[sig]=false;
+			#if js
+				if(hooked[sig]==true) {
+					untyped __js__("process").removeAllListeners(sig==sigINT?"SIGINT":"SIGTERM");
+					hooked[sig]=false;
+				}
+			#end
+			return true;
+		}
+		try {
+			#if js
+				// a listener stops node ending the program, so only add one if the event loop can call it
+				if(!BrowserStore.eventLoop || untyped __js__("typeof process==='undefined' || process.on==null")) return false;
+				if(hooked[sig]!=true) {
+					untyped __js__("process").on(sig==sigINT?"SIGINT":"SIGTERM",function(){raise(sig);});
+					hooked[sig]=true;
+				}
+			#elseif java
+				if(hooked[0]!=true) { // one hook for both signals, as java cannot tell them apart
+					untyped __java__("java.lang.Runtime.getRuntime().addShutdownHook(new java.lang.Thread(){public void run(){haxe.root.HostOS.shutdownHook();}})");
+					hooked[0]=true;
+				}
+			#elseif cs
+				if(sig!=sigINT) return false;
+				if(hooked[sig]!=true) { // if the signal is not delivered, the program is cancelled as usual
+					untyped __cs__("System.Console.CancelKeyPress+=(s,e)=>{e.Cancel=haxe.root.HostOS.raise(2);}");
+					hooked[sig]=true;
+				}
+			#else
+				return false;
+			#end
+			wanted[sig]=true;
+			return true;
+		} catch(e:Dynamic) {}
+		return false;
+	}
+
+	#if java
+	// shutdownHook runs as the JVM shuts down, giving the Go program up to 5 seconds to exit by itself
+	public static function shutdownHook() {
+		inHook=true;
+		if(!raise(wanted[sigTERM]==true ? sigTERM : sigINT)) return;
+		var t=Sys.time();
+		while(!exiting && Sys.time()-t<5.0) Sys.sleep(0.01);
+	}
+	#end
+}
+`
+}
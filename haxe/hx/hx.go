@@ -2,7 +2,8 @@
 // It follows that these functions act more like macros than functions, so some parameters must be constant strings.
 //
 // This package provides untyped access to Haxe, which is far from ideal.
-// The next stage of development will be to provide a typed Go overlay - the gohaxelib approach (as yet incomplete).
+// The next stage of development is a typed Go overlay - the gohaxelib approach:
+// the haxe2go command (in haxe/haxe2go) generates typed Go wrappers for Haxe classes, which call these functions.
 // The final stage will be to use Haxe types directly...
 //
 package hx
@@ -33,6 +34,18 @@ func Complex(x uintptr) complex128 { return 0 + 0i }
 // Int64 provides a cast from haxe Dynamic type
 func Int64(x uintptr) int64 { return 0 }
 
+// ToHaxe converts a Go value to its natural Haxe form, to pass to the other functions of this package:
+// a slice becomes an Array, a map with string or integer keys a Map, a struct an anonymous object of its exported fields
+// and a string a Haxe String, converting the values they hold in the same way.
+// Pointers to, and values of, struct types exported with //tardisgo:export become their Haxe facade class.
+// Other values are passed as they are, for example a Go []string becomes an Array<String>, but a chan stays a Channel.
+func ToHaxe(v interface{}) uintptr { return 0 }
+
+// FromHaxe reverses ToHaxe, converting a Haxe value to the type of the Go variable that ptr points to, for example:
+//   var fields []string
+//   hx.FromHaxe(hx.CallDynamic("", "Reflect.fields", 1, obj), &fields)
+func FromHaxe(v uintptr, ptr interface{}) {}
+
 // Source places the contents into a classname.hx file in the haxe output directory at compile time.
 func Source(classname, contents string) {}
 
@@ -41,9 +54,15 @@ func Source(classname, contents string) {}
 // resTyp = a constant string giving the Go name of the type of the data to be returned as an interface. "" if nothing is returned.
 // NOTE: the returned values are not converted from Haxe format, e.g. String values will not be converted back to UTF-8 on UTF-16 targets.
 // code = must be a constant string containing a well-formed Haxe statement, probably terminated with a ";".
-// args = whatever aguments are passed (as interfaces), typical haxe code to access the value of an argument is "_a[3].val".
+// args = whatever aguments are passed (as interfaces), typical haxe code to access the value of an argument is "_a.param(3).val".
+// The code may instead refer to the arguments as {0}, {1}... by position, or as {name} where the argument is written as name in the Go call.
+// These placeholders are replaced by the argument converted to its Haxe form according to its Go type, as ToHaxe() would,
+// so strings, ints, slices and maps arrive as Haxe String, Int, Array and Map values, while Dynamic (uintptr) values are passed as they are.
+// Each argument is converted once, however many times it is used. Placeholders inside Haxe string literals and comments
+// are left as they are, as is other text in braces, such as {a:1}.
 // Try the Go code:
 //   hx.Code("","trace('HAXE trace:',_a.itemAddr(0).load().val,_a.itemAddr(1).load().val);", 42,43)
+//   hx.Code("","trace('HAXE trace:',{0},{name});", 42,name)
 func Code(ifLogic, code string, args ...interface{}) {}
 
 // CodeIface - same as Code() but returns an interface.
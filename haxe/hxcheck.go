@@ -0,0 +1,480 @@
+// Copyright 2014 Elliott Stoneham and The TARDIS Go Authors
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package haxe
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"go/constant"
+	"go/types"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// HxTypesFile is the name of a Haxe type dump, as written by "haxe -xml", to check the targets of hx pseudo-function calls against.
+// It is set by the tardisgo -hxtypes flag; if it is empty the targets are not checked.
+var HxTypesFile string
+
+// hxTypeInfo describes a Haxe class, interface, enum or abstract in the type dump.
+type hxTypeInfo struct {
+	fields  map[string]bool // by name, static and instance fields, or enum constructors
+	extends []string        // the paths of the super class and any interfaces
+}
+
+// hxTypeDump holds the types read from HxTypesFile.
+type hxTypeDump struct {
+	types    map[string]*hxTypeInfo // by Haxe path
+	packages map[string]bool        // the packages of the types
+}
+
+// readHxTypes reads the type dump written by "haxe -xml" into fileName.
+func readHxTypes(fileName string) (*hxTypeDump, error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	dump := &hxTypeDump{types: make(map[string]*hxTypeInfo), packages: make(map[string]bool)}
+	dec := xml.NewDecoder(f)
+	var stack []string // the element names from the top
+	var current *hxTypeInfo
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return dump, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			stack = append(stack, t.Name.Local)
+			switch len(stack) {
+			case 2: // a type
+				current = nil
+				path := xmlAttr(t, "path")
+				if path == "" {
+					break
+				}
+				current = &hxTypeInfo{fields: make(map[string]bool)}
+				if dump.types[path] == nil { // there can be several entries for different platforms
+					dump.types[path] = current
+				} else {
+					current = dump.types[path]
+				}
+				if dot := strings.LastIndex(path, "."); dot >= 0 {
+					dump.packages[path[:dot]] = true
+				}
+			case 3: // a field, or the super class or interfaces
+				if current == nil {
+					break
+				}
+				switch t.Name.Local {
+				case "extends", "implements":
+					current.extends = append(current.extends, xmlAttr(t, "path"))
+				case "meta", "haxe_doc", "impl", "this", "to", "from":
+				default:
+					current.fields[t.Name.Local] = true
+				}
+			}
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		}
+	}
+}
+
+func xmlAttr(t xml.StartElement, name string) string {
+	for _, a := range t.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// hasField returns true if the Haxe type at path, or a type it extends, has the field.
+func (d *hxTypeDump) hasField(path, field string) bool {
+	t := d.types[path]
+	if t == nil {
+		return false
+	}
+	if t.fields[field] {
+		return true
+	}
+	for _, e := range t.extends {
+		if d.hasField(e, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkType returns an error if path is in a package of the dump, but is not a type there.
+// Types in other packages, including the TARDIS Go runtime classes, are not checked.
+func (d *hxTypeDump) checkType(path string) error {
+	if d.types[path] != nil {
+		return nil
+	}
+	if dot := strings.LastIndex(path, "."); dot >= 0 && d.packages[path[:dot]] {
+		return fmt.Errorf("%s is not a Haxe type in %s", path, HxTypesFile)
+	}
+	return nil
+}
+
+// checkStatic returns an error if target, in the form "pkg.Type.field", does not name a field of a known type.
+// Anything after the field, as in "js.Browser.window.alert", is not checked.
+func (d *hxTypeDump) checkStatic(target string) error {
+	parts := strings.Split(target, ".")
+	for i := len(parts) - 1; i > 0; i-- {
+		path := strings.Join(parts[:i], ".")
+		if d.types[path] != nil {
+			if !d.hasField(path, parts[i]) {
+				return fmt.Errorf("Haxe type %s has no field %s, see %s", path, parts[i], HxTypesFile)
+			}
+			return nil
+		}
+	}
+	return d.checkType(strings.Join(parts[:len(parts)-1], "."))
+}
+
+// checkField returns an error if haxeType is known, but has no field called name.
+func (d *hxTypeDump) checkField(haxeType, name string) error {
+	if err := d.checkType(haxeType); err != nil {
+		return err
+	}
+	if d.types[haxeType] != nil && !d.hasField(haxeType, name) {
+		return fmt.Errorf("Haxe type %s has no field %s, see %s", haxeType, name, HxTypesFile)
+	}
+	return nil
+}
+
+// hxTypes returns the type dump to check hx pseudo-function calls against, or nil if there is none.
+func (l langType) hxTypes(errorInfo string) *hxTypeDump {
+	if HxTypesFile == "" || l.hc.hxTypesFailed {
+		return nil
+	}
+	if l.hc.hxTypes == nil {
+		dump, err := readHxTypes(HxTypesFile)
+		if err != nil {
+			l.PogoComp().LogError(errorInfo, "Haxe", fmt.Errorf("hx: unable to read the Haxe type dump: %v", err))
+			l.hc.hxTypesFailed = true
+			return nil
+		}
+		l.hc.hxTypes = dump
+	}
+	return l.hc.hxTypes
+}
+
+// checkIfLogic returns an error if s is not a well-formed Haxe conditional compilation expression,
+// made from defines, numbers and strings, with the operators ! && || == != < <= > >= and brackets.
+func checkIfLogic(s string) error {
+	p := &ifLogicParser{s: s}
+	p.next()
+	if err := p.or(); err != nil {
+		return err
+	}
+	if p.tok != "" {
+		return p.fail("unexpected " + p.tok)
+	}
+	return nil
+}
+
+type ifLogicParser struct {
+	s   string
+	pos int    // the position after tok in s
+	tok string // the current token, "" at the end
+	err error  // any error found while reading the tokens
+}
+
+func (p *ifLogicParser) fail(what string) error {
+	if p.err != nil {
+		return p.err
+	}
+	return fmt.Errorf("ifLogic %q: %s at offset %d", p.s, what, p.pos)
+}
+
+// next reads the next token into tok.
+func (p *ifLogicParser) next() {
+	for p.pos < len(p.s) && (p.s[p.pos] == ' ' || p.s[p.pos] == '\t') {
+		p.pos++
+	}
+	start := p.pos
+	if p.pos >= len(p.s) {
+		p.tok = ""
+		return
+	}
+	c := p.s[p.pos]
+	switch {
+	case isIdentByte(c, true):
+		for p.pos < len(p.s) && (isIdentByte(p.s[p.pos], false) || p.s[p.pos] == '.') {
+			p.pos++
+		}
+	case c == '"' || c == '\'':
+		p.pos++
+		for p.pos < len(p.s) && p.s[p.pos] != c {
+			p.pos++
+		}
+		if p.pos >= len(p.s) {
+			p.err = p.fail("unterminated string")
+			p.tok = ""
+			return
+		}
+		p.pos++
+	default:
+		for _, op := range []string{"&&", "||", "==", "!=", "<=", ">=", "!", "<", ">", "(", ")"} {
+			if strings.HasPrefix(p.s[p.pos:], op) {
+				p.pos += len(op)
+				p.tok = op
+				return
+			}
+		}
+		p.pos++
+		p.err = p.fail(fmt.Sprintf("unexpected character %q", c))
+		p.tok = ""
+		return
+	}
+	p.tok = p.s[start:p.pos]
+}
+
+func isIdentByte(c byte, first bool) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || (!first && c == '-')
+}
+
+func (p *ifLogicParser) or() error {
+	if err := p.and(); err != nil {
+		return err
+	}
+	for p.tok == "||" {
+		p.next()
+		if err := p.and(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *ifLogicParser) and() error {
+	if err := p.unary(); err != nil {
+		return err
+	}
+	for p.tok == "&&" {
+		p.next()
+		if err := p.unary(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *ifLogicParser) unary() error {
+	if p.tok == "!" {
+		p.next()
+		return p.unary()
+	}
+	if err := p.primary(); err != nil {
+		return err
+	}
+	switch p.tok {
+	case "==", "!=", "<", "<=", ">", ">=":
+		p.next()
+		return p.primary()
+	}
+	return nil
+}
+
+func (p *ifLogicParser) primary() error {
+	switch {
+	case p.err != nil:
+		return p.err
+	case p.tok == "(":
+		p.next()
+		if err := p.or(); err != nil {
+			return err
+		}
+		if p.tok != ")" {
+			return p.fail("missing )")
+		}
+		p.next()
+		return nil
+	case p.tok == "":
+		return p.fail("missing operand")
+	case isIdentByte(p.tok[0], true) || p.tok[0] == '"' || p.tok[0] == '\'':
+		p.next()
+		return p.err
+	}
+	return p.fail("unexpected " + p.tok)
+}
+
+// variadicLen returns the number of values passed as the variadic arguments v of a call,
+// or -1 if that is not known at compile time, as for f(x...).
+func variadicLen(v ssa.Value) int {
+	switch v := v.(type) {
+	case *ssa.Const:
+		if v.IsNil() {
+			return 0
+		}
+	case *ssa.Slice:
+		if a, ok := v.X.(*ssa.Alloc); ok && v.Low == nil && v.High == nil {
+			if pt, ok := a.Type().(*types.Pointer); ok {
+				if at, ok := pt.Elem().Underlying().(*types.Array); ok {
+					return int(at.Len())
+				}
+			}
+		}
+	}
+	return -1
+}
+
+// variadicValues returns the values stored into the variadic arguments v of a call, so that their Go types are known,
+// or nil if they are not known at compile time; an entry is nil if its value cannot be found.
+func variadicValues(v ssa.Value) []ssa.Value {
+	n := variadicLen(v)
+	if n <= 0 {
+		return nil
+	}
+	vals := make([]ssa.Value, n)
+	s, isSlice := v.(*ssa.Slice)
+	if !isSlice {
+		return vals
+	}
+	a := s.X.(*ssa.Alloc)
+	for _, ref := range *a.Referrers() {
+		ia, isIA := ref.(*ssa.IndexAddr)
+		if !isIA || ia.X != a {
+			continue
+		}
+		idx, isConst := ia.Index.(*ssa.Const)
+		if !isConst {
+			continue
+		}
+		i := int(idx.Int64())
+		if i < 0 || i >= n {
+			continue
+		}
+		for _, iaRef := range *ia.Referrers() {
+			if st, isStore := iaRef.(*ssa.Store); isStore && st.Addr == ia {
+				vals[i] = st.Val
+			}
+		}
+	}
+	return vals
+}
+
+// pseudoName returns the Go name of an hx pseudo-function from its Haxe name, for example "CallInt" from "CCallIInt".
+func pseudoName(fnToCall string) string {
+	name := ""
+	for i := 0; i < len(fnToCall); i++ {
+		name += fnToCall[i : i+1]
+		if fnToCall[i] >= 'A' && fnToCall[i] <= 'Z' && i+1 < len(fnToCall) && fnToCall[i+1] == fnToCall[i] {
+			i++
+		}
+	}
+	return name
+}
+
+func constString(v ssa.Value) (string, bool) {
+	if c, ok := v.(*ssa.Const); ok && c.Value != nil && c.Value.Kind() == constant.String {
+		return constant.StringVal(c.Value), true
+	}
+	return "", false
+}
+
+// checkHxCall reports the errors it can find at compile time in a call to an hx pseudo-function,
+// where off is the index in args of the first argument after ifLogic, and resTyp if there is one.
+// It checks that the ifLogic is well formed, that nargs is a constant giving the number of arguments that follow,
+// and, if there is a Haxe type dump, that the Haxe types and fields used exist.
+func (l langType) checkHxCall(fnToCall string, args []ssa.Value, off int, errorInfo string) {
+	name := "hx." + pseudoName(fnToCall) + "()"
+	logErr := func(err error) {
+		l.PogoComp().LogError(errorInfo, "Haxe", fmt.Errorf("%s %v", name, err))
+	}
+	if ifLogic, ok := constString(args[0]); ok && ifLogic != "" {
+		if err := checkIfLogic(ifLogic); err != nil {
+			logErr(err)
+		}
+	}
+	if fnToCall == "MMethIIface" {
+		return // its arguments are not in the same order as the other Meth functions
+	}
+	if err := checkNargs(fnToCall, args, off); err != nil {
+		logErr(err)
+	}
+
+	d := l.hxTypes(errorInfo)
+	if d == nil {
+		return
+	}
+	str := func(i int) string {
+		if i < len(args) {
+			if s, ok := constString(args[i]); ok {
+				return s
+			}
+		}
+		return ""
+	}
+	var err error
+	switch {
+	case strings.HasPrefix(fnToCall, "CCall"), strings.HasPrefix(fnToCall, "GGet"), strings.HasPrefix(fnToCall, "SSet"):
+		if target := str(off); isHaxePath(target) { // Get can also be given an expression
+			err = d.checkStatic(target)
+		}
+	case strings.HasPrefix(fnToCall, "NNew"):
+		if target := str(off); isHaxePath(target) {
+			err = d.checkType(target)
+		}
+	case strings.HasPrefix(fnToCall, "MMeth"), strings.HasPrefix(fnToCall, "FFget"), strings.HasPrefix(fnToCall, "FFset"):
+		if haxeType := str(off + 1); haxeType != "" {
+			err = d.checkField(haxeType, str(off+2))
+		}
+	}
+	if err != nil {
+		logErr(err)
+	}
+}
+
+// checkNargs returns an error if the nargs argument of a call to an hx pseudo-function is not a constant,
+// or does not give the number of arguments that follow it, where off is as for checkHxCall.
+func checkNargs(fnToCall string, args []ssa.Value, off int) error {
+	nargsAt := -1
+	switch {
+	case strings.HasPrefix(fnToCall, "CCall"), strings.HasPrefix(fnToCall, "NNew"):
+		nargsAt = off + 1
+	case strings.HasPrefix(fnToCall, "MMeth"):
+		nargsAt = off + 3
+	}
+	if nargsAt < 0 || nargsAt+1 >= len(args) {
+		return nil
+	}
+	c, ok := args[nargsAt].(*ssa.Const)
+	if !ok || c.Value == nil || c.Value.Kind() != constant.Int {
+		return fmt.Errorf("nargs is not a constant: %s", args[nargsAt].String())
+	}
+	if n := variadicLen(args[nargsAt+1]); n >= 0 && c.Int64() != int64(n) {
+		return fmt.Errorf("nargs is %d, but %d arguments are given", c.Int64(), n)
+	}
+	return nil
+}
+
+// isHaxePath returns true if s is a dotted Haxe path, rather than an expression.
+func isHaxePath(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, part := range strings.Split(s, ".") {
+		if part == "" || !isIdentByte(part[0], true) || (part[0] >= '0' && part[0] <= '9') {
+			return false
+		}
+		for i := 1; i < len(part); i++ {
+			if !isIdentByte(part[i], true) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,250 @@
+// Copyright 2014 Elliott Stoneham and The TARDIS Go Authors
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package haxe
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+)
+
+func TestCheckIfLogic(t *testing.T) {
+	for _, s := range []string{
+		"js",
+		"!js",
+		"js || flash",
+		"(cpp && !neko) || cs",
+		"!(js || (cs && java))",
+		"haxe_ver >= 3.2",
+		`target == "js" || target != 'cs'`,
+		"flash.version < 11",
+		"js-es5 && nodejs",
+	} {
+		if err := checkIfLogic(s); err != nil {
+			t.Errorf("checkIfLogic(%q) = %v, want no error", s, err)
+		}
+	}
+	for _, tt := range []struct{ s, err string }{
+		{"", "missing operand"},
+		{"js &&", "missing operand"},
+		{"!", "missing operand"},
+		{"(js", "missing )"},
+		{"js)", "unexpected )"},
+		{"js cs", "unexpected cs"},
+		{"== js", "unexpected =="},
+		{"js | cs", "unexpected character '|'"},
+		{"js & cs", "unexpected character '&'"},
+		{"js + 1", "unexpected character '+'"},
+		{`target == "js`, "unterminated string"},
+	} {
+		err := checkIfLogic(tt.s)
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("checkIfLogic(%q) = %v, want an error containing %q", tt.s, err, tt.err)
+		}
+	}
+}
+
+func TestPseudoName(t *testing.T) {
+	for _, tt := range []struct{ in, out string }{
+		{"CCall", "Call"},
+		{"CCallIInt", "CallInt"},
+		{"MMethIIface", "MethIface"},
+		{"FFgetSString", "FgetString"},
+		{"NNew", "New"},
+		{"CCodeDDynamic", "CodeDynamic"},
+	} {
+		if got := pseudoName(tt.in); got != tt.out {
+			t.Errorf("pseudoName(%q) = %q, want %q", tt.in, got, tt.out)
+		}
+	}
+}
+
+func TestHxCodeReplace(t *testing.T) {
+	repl := func(ph string) string { return "<" + ph[1:len(ph)-1] + ">" }
+	for _, tt := range []struct{ in, out string }{
+		{"{0}+{name}+{a.b};", "<0>+<name>+<a.b>;"},
+		{"({a:{n}}).a;", "({a:<n>}).a;"},
+		{"'{0}'+{0};", "'{0}'+<0>;"},
+		{`"{0}"+{1}+'{2}';`, `"{0}"+<1>+'{2}';`},
+		{`'it\'s {0}'+{0}+"\"{1}\""+{1};`, `'it\'s {0}'+<0>+"\"{1}\""+<1>;`},
+		{`"'"+{0}+"'";`, `"'"+<0>+"'";`},
+		{"{0}; // {1} isn't\n{1};", "<0>; // {1} isn't\n<1>;"},
+		{"{0} /* {1} */ {1} /*", "<0> /* {1} */ <1> /*"},
+		{"{0}+'unterminated {1}", "<0>+'unterminated {1}"},
+		{"{0}/{1}", "<0>/<1>"},
+	} {
+		if got := hxCodeReplace(tt.in, repl); got != tt.out {
+			t.Errorf("hxCodeReplace(%q) = %q, want %q", tt.in, got, tt.out)
+		}
+	}
+}
+
+func TestIsHaxePath(t *testing.T) {
+	for _, tt := range []struct {
+		s    string
+		want bool
+	}{
+		{"Math", true},
+		{"Math.PI", true},
+		{"js.Browser.window", true},
+		{"haxe.io._UInt8Array", true},
+		{"", false},
+		{"a..b", false},
+		{".a", false},
+		{"a.", false},
+		{"1abc", false},
+		{"a.1", false},
+		{"a-b", false},
+		{"f()", false},
+		{"x + 1", false},
+		{"_a.param(0)", false},
+	} {
+		if got := isHaxePath(tt.s); got != tt.want {
+			t.Errorf("isHaxePath(%q) = %v, want %v", tt.s, got, tt.want)
+		}
+	}
+}
+
+// nargsSrc has functions with the signatures of some of the hx pseudo-functions, and calls to them,
+// each commented with the error checkNargs should give, if any.
+const nargsSrc = `package p
+
+func Call(ifLogic, target string, nargs int, args ...interface{})                        {}
+func CallIface(ifLogic, resTyp, target string, nargs int, args ...interface{}) interface{} { return nil }
+func Meth(ifLogic string, object uintptr, haxeType, method string, nargs int, args ...interface{}) {}
+func New(ifLogic, target string, nargs int, args ...interface{}) uintptr                  { return 0 }
+func Get(ifLogic, name string) uintptr                                                    { return 0 }
+
+func f(n int, xs []interface{}) {
+	Call("", "A.b", 0)
+	Call("", "A.b", 2, 1, "x")
+	Call("", "A.b", 1)            // nargs is 1, but 0 arguments are given
+	Call("", "A.b", 1, 1, 2)      // nargs is 1, but 2 arguments are given
+	Call("", "A.b", n, 1)         // nargs is not a constant
+	Call("", "A.b", 3, xs...)
+	CallIface("", "Int", "A.b", 1, 1)
+	CallIface("", "Int", "A.b", 2, 1) // nargs is 2, but 1 arguments are given
+	Meth("", 0, "A", "m", 1, 1.5)
+	Meth("", 0, "A", "m", 0, 1.5)  // nargs is 0, but 1 arguments are given
+	New("", "A", 2, 1, nil)
+	New("", "A", 2)                // nargs is 2, but 0 arguments are given
+	Get("", "A.b")
+}
+`
+
+// TestCheckNargs checks that checkNargs, and so variadicLen, find the number of arguments given in each call in nargsSrc.
+func TestCheckNargs(t *testing.T) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "p.go", nargsSrc, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, _, err := ssautil.BuildPackage(&types.Config{}, fset, types.NewPackage("p", ""), []*ast.File{f}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := make(map[int]string) // the expected errors by line
+	for _, cg := range f.Comments {
+		for _, c := range cg.List {
+			want[fset.Position(c.Pos()).Line] = strings.TrimSpace(strings.TrimPrefix(c.Text, "//"))
+		}
+	}
+	fnToCall := map[string]string{"Call": "CCall", "CallIface": "CCallIIface", "Meth": "MMeth", "New": "NNew", "Get": "GGet"}
+
+	calls := 0
+	for _, b := range pkg.Func("f").Blocks {
+		for _, instr := range b.Instrs {
+			call, ok := instr.(*ssa.Call)
+			if !ok {
+				continue
+			}
+			callee := call.Call.StaticCallee().Name()
+			off := 1
+			if callee == "CallIface" {
+				off = 2
+			}
+			line := fset.Position(call.Pos()).Line
+			got := ""
+			if err := checkNargs(fnToCall[callee], call.Call.Args, off); err != nil {
+				got = err.Error()
+			}
+			if !strings.HasPrefix(got, want[line]) || (got == "") != (want[line] == "") {
+				t.Errorf("line %d: checkNargs of the call to %s returned %q, want %q", line, callee, got, want[line])
+			}
+			calls++
+		}
+	}
+	if calls != 13 {
+		t.Errorf("found %d calls, want 13", calls)
+	}
+}
+
+func TestHxTypes(t *testing.T) {
+	if _, err := readHxTypes("testdata/nonexistent.xml"); err == nil {
+		t.Error("readHxTypes of a missing file did not fail")
+	}
+	d, err := readHxTypes("testdata/hxtypes.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		target string
+		ok     bool
+	}{
+		{"js.Browser.alert", true},
+		{"js.Browser.window", true},
+		{"js.Browser.window.alert", true}, // only the field of the type is checked
+		{"js.Browser.nothing", false},
+		{"js.Nothing.alert", false}, // js is a package of the dump
+		{"Math.PI", true},
+		{"Math.E", false},
+		{"haxe.io.Error.Blocked", true},
+		{"haxe.io.Error.Custom", true},
+		{"haxe.io.Bytes.alloc", true},
+		{"haxe.io.Bytes.ofString", true}, // from another platform's entry for the type
+		{"other.Type.field", true},       // an unknown package is not checked
+		{"Go.haxegoruntime.run", true},
+	} {
+		if err := d.checkStatic(tt.target); (err == nil) != tt.ok {
+			t.Errorf("checkStatic(%q) = %v, want ok %v", tt.target, err, tt.ok)
+		}
+	}
+	for _, tt := range []struct {
+		haxeType, field string
+		ok              bool
+	}{
+		{"js.html.Window", "alert", true},
+		{"js.html.Window", "addEventListener", true}, // from the super class
+		{"js.html.Window", "nothing", false},
+		{"js.html.Nothing", "alert", false},
+		{"tardis.Unknown", "x", true},
+	} {
+		if err := d.checkField(tt.haxeType, tt.field); (err == nil) != tt.ok {
+			t.Errorf("checkField(%q, %q) = %v, want ok %v", tt.haxeType, tt.field, err, tt.ok)
+		}
+	}
+	for _, tt := range []struct {
+		path string
+		ok   bool
+	}{
+		{"haxe.io.UInt8Array", true},
+		{"haxe.io.Error", true},
+		{"haxe.io.Nothing", false},
+		{"haxe.io._UInt8Array.UInt8Array_Impl_", true}, // not in a package of the dump
+	} {
+		if err := d.checkType(tt.path); (err == nil) != tt.ok {
+			t.Errorf("checkType(%q) = %v, want ok %v", tt.path, err, tt.ok)
+		}
+	}
+	if d.types["haxe.io.UInt8Array"] != nil && len(d.types["haxe.io.UInt8Array"].fields) != 0 {
+		t.Errorf("the abstract haxe.io.UInt8Array has fields %v, want none", d.types["haxe.io.UInt8Array"].fields)
+	}
+}
@@ -4,10 +4,14 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"regexp"
 	"strconv"
 	"strings"
 
+	"go/ast"
 	"go/constant"
+	"go/token"
+	"go/types"
 
 	"golang.org/x/tools/go/ssa"
 
@@ -16,7 +20,7 @@ import (
 
 var pseudoFnPrefix = tgoutil.MakeID("github.com/tardisgo/tardisgo/haxe/hx_")
 
-func (l langType) hxPseudoFuncs(fnToCall string, args []ssa.Value, errorInfo string) string {
+func (l langType) hxPseudoFuncs(fnToCall string, args []ssa.Value, pos token.Pos, errorInfo string) string {
 	//fmt.Println("DEBUG l.hxPseudoFuncs()", fnToCall, args, errorInfo)
 	fnToCall = strings.TrimPrefix(fnToCall, pseudoFnPrefix)
 
@@ -48,6 +52,10 @@ func (l langType) hxPseudoFuncs(fnToCall string, args []ssa.Value, errorInfo str
 		return "cast(" + l.IndirectValue(args[0], errorInfo) + ",Complex);"
 	case "IInt64":
 		return "new GOint64(" + l.IndirectValue(args[0], errorInfo) + ");"
+	case "TToHHaxe":
+		return l.hxToHaxe(args[0], errorInfo)
+	case "FFromHHaxe":
+		return l.hxFromHaxe(args[0], args[1], errorInfo)
 	case "CCallbackFFunc":
 		// NOTE there will be a preceeding MakeInterface call that is made redundant by this code
 		if len(args) == 1 {
@@ -71,6 +79,12 @@ func (l langType) hxPseudoFuncs(fnToCall string, args []ssa.Value, errorInfo str
 		return ""
 	}
 
+	if strings.HasSuffix(fnToCall, "IIface") {
+		l.checkHxCall(fnToCall, args, 2, errorInfo)
+	} else {
+		l.checkHxCall(fnToCall, args, 1, errorInfo)
+	}
+
 	argOff := 1 // because of the ifLogic
 	wrapStart := ""
 	wrapEnd := ""
@@ -86,7 +100,7 @@ func (l langType) hxPseudoFuncs(fnToCall string, args []ssa.Value, errorInfo str
 			defVal = "false"
 		}
 		if strings.HasSuffix(fnToCall, "Int") {
-			defVal = "0"
+			defVal = l.intResult("0")
 		}
 		if strings.HasSuffix(fnToCall, "Float") {
 			defVal = "0.0"
@@ -105,6 +119,13 @@ func (l langType) hxPseudoFuncs(fnToCall string, args []ssa.Value, errorInfo str
 		wrapEnd = "});" + wrapEnd
 	}
 
+	if l.intIs64() && strings.HasSuffix(fnToCall, "IInt") &&
+		!strings.HasPrefix(fnToCall, "FFset") &&
+		!strings.HasPrefix(fnToCall, "SSet") { // the Haxe Int result must be held as a 64-bit Go int
+		wrapStart += " GOint64.ofInt({"
+		wrapEnd = "});" + wrapEnd
+	}
+
 	if strings.HasSuffix(fnToCall, "IIface") {
 		argOff = 2
 		wrapStart += "new Interface(TypeInfo.getId(" + l.IndirectValue(args[1], errorInfo) + "),{"
@@ -128,6 +149,9 @@ func (l langType) hxPseudoFuncs(fnToCall string, args []ssa.Value, errorInfo str
 		tcode := strings.Trim(givenConst.Value.String(), `"`) // trim quotes
 		tcode = strings.Replace(tcode, "\\\"", "\"", -1)      // replace backslash quote with quote
 		//println("DEBUG string=", tcode)
+		if strings.HasPrefix(fnToCall, "CCode") {
+			tcode = l.hxTemplate(tcode, args[argOff+1], l.hxCallArgs(pos, argOff+1), errorInfo)
+		}
 		code += tcode
 	} else {
 		code += strings.Trim(l.IndirectValue(args[argOff], errorInfo), `"`) // trim quotes if it has any
@@ -147,20 +171,31 @@ func (l langType) hxPseudoFuncs(fnToCall string, args []ssa.Value, errorInfo str
 		} else {
 			code += "("
 		}
-		textLen := l.IndirectValue(args[argOff], errorInfo) // see Const() for format
-		aLen, err := strconv.ParseUint(textLen, 0, 64)
+		var aLen uint64
+		var err error
+		if c, isConst := args[argOff].(*ssa.Const); isConst && c.Value != nil { // whatever the size of int
+			aLen = c.Uint64()
+		} else {
+			textLen := l.IndirectValue(args[argOff], errorInfo) // see Const() for format
+			aLen, err = strconv.ParseUint(textLen, 0, 64)
+		}
 		if err != nil {
 			code += " ERROR Go ParseUint on number of arguments to hx.Meth() or hx.Call() - " + err.Error() + "! "
 		} else {
 			if aLen == 0 {
 				usesArgs = false
 			}
+			vals := variadicValues(args[argOff+1])
 			for i := uint64(0); i < aLen; i++ {
 				if i > 0 {
 					code += ","
 				}
+				var val ssa.Value
+				if i < uint64(len(vals)) {
+					val = vals[i]
+				}
 				//code += fmt.Sprintf("Force.toHaxeParam(_a.itemAddr(%d).load())", i)
-				code += fmt.Sprintf("Force.toHaxeParam(_a.param(%d))", i)
+				code += l.haxeArgParam(fmt.Sprintf("_a.param(%d)", i), val)
 			}
 		}
 		code += ");"
@@ -171,7 +206,7 @@ func (l langType) hxPseudoFuncs(fnToCall string, args []ssa.Value, errorInfo str
 	}
 	if strings.HasPrefix(fnToCall, "SSet") {
 		argOff++
-		code = code + "=" + l.IndirectValue(args[argOff], errorInfo) + ";"
+		code = code + "=" + l.haxeIntParam(args[argOff], errorInfo) + ";"
 		usesArgs = false
 	}
 	if strings.HasPrefix(fnToCall, "FFget") {
@@ -188,7 +223,7 @@ func (l langType) hxPseudoFuncs(fnToCall string, args []ssa.Value, errorInfo str
 			code = "cast(" + code + "," + l.tgoString(l.IndirectValue(args[argOff], errorInfo), errorInfo) + ")"
 		}
 		code += "." + l.tgoString(l.IndirectValue(args[argOff+1], errorInfo), errorInfo) +
-			"=Force.toHaxeParam(" + l.IndirectValue(args[argOff+2], errorInfo) + "); "
+			"=Force.toHaxeParam(" + l.haxeIntParam(args[argOff+2], errorInfo) + "); "
 		usesArgs = false
 	}
 
@@ -199,6 +234,112 @@ func (l langType) hxPseudoFuncs(fnToCall string, args []ssa.Value, errorInfo str
 	return ret + wrapStart + code + wrapEnd + " }"
 }
 
+var hxPlaceholder = regexp.MustCompile(`\{([0-9]+|[A-Za-z_][A-Za-z0-9_.]*)\}`)
+
+// hxTemplate replaces the placeholders in the code of an hx.Code() call with the Haxe form of its arguments:
+// {0}, {1}... by position, or {name} for an argument written as name in the Go source.
+// Each argument used is converted once, according to its Go type as hx.ToHaxe() would, into a local _arg0, _arg1...
+// declared before the code. Other text in braces, and placeholders in string literals or comments, are left as they are.
+func (l langType) hxTemplate(code string, variadic ssa.Value, argExprs []ast.Expr, errorInfo string) string {
+	vals := variadicValues(variadic)
+	n := variadicLen(variadic)
+	decls := ""
+	declared := make(map[int]bool)
+	code = hxCodeReplace(code, func(ph string) string {
+		name := ph[1 : len(ph)-1]
+		i, err := strconv.Atoi(name)
+		if err != nil {
+			i = -1
+			for a, expr := range argExprs {
+				if types.ExprString(expr) == name {
+					i = a
+					break
+				}
+			}
+			if i < 0 {
+				return ph
+			}
+		} else if n >= 0 && i >= n {
+			l.PogoComp().LogError(errorInfo, "Haxe",
+				fmt.Errorf("hx.Code() placeholder %s is beyond the %d arguments", ph, n))
+			return ph
+		}
+		local := fmt.Sprintf("_arg%d", i)
+		if declared[i] {
+			return local
+		}
+		declared[i] = true
+		param := fmt.Sprintf("_a.param(%d)", i)
+		conv := "Force.toHaxeParam(" + param + ")" // also makes Go closures callable from Haxe
+		if i < len(vals) {
+			if mi, isMI := vals[i].(*ssa.MakeInterface); isMI {
+				if c := l.exports().toHaxe(mi.X.Type(), param+".val", 0); c != param+".val" {
+					conv = c
+				}
+			}
+		}
+		decls += "var " + local + "=" + conv + "; "
+		return local
+	})
+	return decls + code
+}
+
+// hxCodeReplace returns the Haxe code with the placeholders outside its string literals and comments replaced by repl.
+func hxCodeReplace(code string, repl func(string) string) string {
+	ret := ""
+	start := 0 // of the code not yet copied to ret
+	for i := 0; i < len(code); i++ {
+		end := -1 // of the string literal or comment starting at i, if there is one
+		switch {
+		case code[i] == '\'' || code[i] == '"':
+			end = len(code)
+			for j := i + 1; j < len(code); j++ {
+				if code[j] == '\\' {
+					j++
+				} else if code[j] == code[i] {
+					end = j + 1
+					break
+				}
+			}
+		case strings.HasPrefix(code[i:], "//"):
+			end = len(code)
+			if nl := strings.IndexByte(code[i:], '\n'); nl >= 0 {
+				end = i + nl
+			}
+		case strings.HasPrefix(code[i:], "/*"):
+			end = len(code)
+			if e := strings.Index(code[i+2:], "*/"); e >= 0 {
+				end = i + 2 + e + 2
+			}
+		}
+		if end >= 0 {
+			ret += hxPlaceholder.ReplaceAllStringFunc(code[start:i], repl) + code[i:end]
+			start = end
+			i = end - 1
+		}
+	}
+	return ret + hxPlaceholder.ReplaceAllStringFunc(code[start:], repl)
+}
+
+// hxCallArgs returns the Go expressions of the arguments of the call at pos in the current function, from the first given,
+// or nil if they cannot be found or are passed as a slice, as in f(x...).
+func (l langType) hxCallArgs(pos token.Pos, first int) []ast.Expr {
+	if l.hc.currentfn == nil || l.hc.currentfn.Syntax() == nil || !pos.IsValid() {
+		return nil
+	}
+	var args []ast.Expr
+	ast.Inspect(l.hc.currentfn.Syntax(), func(n ast.Node) bool {
+		if call, isCall := n.(*ast.CallExpr); isCall && call.Lparen == pos {
+			if !call.Ellipsis.IsValid() && len(call.Args) > first {
+				args = call.Args[first:]
+			}
+			return false
+		}
+		return args == nil
+	})
+	return args
+}
+
 func (l langType) tgoString(s, errorInfo string) string {
 	bits := strings.Split(s, `"`)
 	if len(bits) < 2 {
@@ -207,3 +348,31 @@ func (l langType) tgoString(s, errorInfo string) string {
 	}
 	return bits[1]
 }
+
+// haxeArgParam gives the Haxe value of an argument of an hx.Call(), hx.Meth() or hx.New() call, from the interface
+// param holding it, where val is the value put in that interface, if known.
+// A Go int or uint held in a GOint64 is passed as a Haxe Int, as for haxeIntParam.
+func (l langType) haxeArgParam(param string, val ssa.Value) string {
+	if !l.intIs64() {
+		return "Force.toHaxeParam(" + param + ")"
+	}
+	if mi, isMI := val.(*ssa.MakeInterface); isMI {
+		if bt, isBasic := mi.X.Type().Underlying().(*types.Basic); isBasic &&
+			(bt.Kind() == types.Int || bt.Kind() == types.Uint) {
+			return "Force.toInt(" + param + ")"
+		}
+		return "Force.toHaxeParam(" + param + ")"
+	}
+	// not known, so check the dynamic type of the interface
+	return "Force.toHaxeIntParam(" + param + "," + l.PogoComp().LogTypeUse(types.Typ[types.Int]) + "," +
+		l.PogoComp().LogTypeUse(types.Typ[types.Uint]) + ")"
+}
+
+// haxeIntParam gives the value to pass to Haxe, where a Go int held in a GOint64 is passed as a Haxe Int.
+func (l langType) haxeIntParam(v ssa.Value, errorInfo string) string {
+	if bt, isBasic := v.Type().Underlying().(*types.Basic); isBasic && l.intIs64() &&
+		(bt.Kind() == types.Int || bt.Kind() == types.Uint) {
+		return "GOint64.toInt(" + l.IndirectValue(v, errorInfo) + ")"
+	}
+	return l.IndirectValue(v, errorInfo)
+}
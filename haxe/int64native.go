@@ -0,0 +1,196 @@
+// Copyright 2014 Elliott Stoneham and The TARDIS Go Authors
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package haxe
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Native 64-bit integers for the targets that have them (cpp, java & cs).
+// On these targets GOint64 is an abstract over the native type, with the same static API as the emulated version,
+// so the generated code is unchanged but each operation compiles to one or two native instructions.
+// The emulation in HaxeInt64abs is still used for the other targets, or if -D emulateint64 is given to Haxe.
+
+// native64 gives a Haxe expression that injects the target code for each of cpp, java & cs,
+// where {0} and {1} are the parameters.
+// The parameters of these inline functions may be any expression, so each use of them is bracketed.
+func native64(cpp, java, cs string, params ...string) string {
+	ps := ""
+	if len(params) > 0 {
+		ps = "," + strings.Join(params, ",")
+	}
+	for i := range params {
+		p := fmt.Sprintf("{%d}", i)
+		cpp = strings.Replace(cpp, p, "("+p+")", -1)
+		java = strings.Replace(java, p, "("+p+")", -1)
+		cs = strings.Replace(cs, p, "("+p+")", -1)
+	}
+	return "#if cpp untyped __cpp__(\"" + cpp + "\"" + ps + ")" +
+		" #elseif java untyped __java__(\"" + java + "\"" + ps + ")" +
+		" #else untyped __cs__(\"" + cs + "\"" + ps + ") #end"
+}
+
+// native64binary gives the native code for a binary operator whose result may overflow,
+// in cpp signed overflow is undefined behaviour, so the calculation is done with unsigned values.
+func native64binary(op string) string {
+	return native64(
+		"(cpp::Int64)((cpp::UInt64){0}"+op+"(cpp::UInt64){1})",
+		"({0}"+op+"{1})",
+		"unchecked({0}"+op+"{1})",
+		"x", "y")
+}
+
+// native64bitwise gives the native code for a bit-wise binary operator, which cannot overflow
+func native64bitwise(op string) string {
+	return native64("({0}"+op+"{1})", "({0}"+op+"{1})", "({0}"+op+"{1})", "x", "y")
+}
+
+func nativeInt64Class() string {
+	return `
+#if ((cpp || java || cs) && !emulateint64)
+
+typedef NativeInt64 = #if cpp cpp.Int64 #elseif java java.StdTypes.Int64 #else cs.StdTypes.Int64 #end ;
+
+abstract GOint64native(NativeInt64) from NativeInt64 to NativeInt64 {
+public inline function new(v:NativeInt64) this=v;
+
+@:from
+static public inline function fromHI64(v:haxe.Int64):GOint64native {
+	return make(haxe.Int64.getHigh(v),haxe.Int64.getLow(v));
+}
+@:to
+public inline function toHI64():haxe.Int64 {
+	return haxe.Int64.make(getHigh(this),getLow(this));
+}
+
+private static var minVal:GOint64native = make(0x80000000,0);
+private static var maxVal:GOint64native = make(0x7fffffff,0xffffffff);
+
+public static inline function make(h:Int,l:Int):GOint64native {
+	return ` + native64(
+		"(cpp::Int64)((((cpp::UInt64)(unsigned int){0})<<32)|((cpp::UInt64)(unsigned int){1}))",
+		"((((long){0})<<32)|(((long){1})&0xFFFFFFFFL))",
+		"unchecked((long)((((ulong)(uint){0})<<32)|((ulong)(uint){1})))",
+		"h", "l") + `;
+}
+public static inline function getLow(v:GOint64native):Int {
+	return ` + native64("((int){0})", "((int){0})", "unchecked((int){0})", "v") + `;
+}
+public static inline function getHigh(v:GOint64native):Int {
+	return ` + native64("((int)({0}>>32))", "((int)({0}>>32))", "unchecked((int)({0}>>32))", "v") + `;
+}
+public static inline function toInt(v:GOint64native):Int {
+	return getLow(v); // NOTE: does not throw an error if value overflows Int
+}
+public static inline function ofInt(v:Int):GOint64native {
+	return ` + native64("((cpp::Int64){0})", "((long){0})", "((long){0})", "v") + `;
+}
+public static inline function ofUInt(v:Int):GOint64native {
+	return ` + native64("((cpp::Int64)(unsigned int){0})", "(((long){0})&0xFFFFFFFFL)", "unchecked((long)(uint){0})", "v") + `;
+}
+public static inline function toFloat(v:GOint64native):Float {
+	return ` + native64("((double){0})", "((double){0})", "((double){0})", "v") + `;
+}
+public static function toUFloat(v:GOint64native):Float {
+	if(!isNeg(v)) return toFloat(v);
+	return toFloat(or(ushr(v,1),and(v,ofInt(1))))*2.0; // keep the rounding bit
+}
+public static function ofFloat(v:Float):GOint64native { // the out-of-range results match the emulated version
+	if(Math.isNaN(v)) return minVal; // largest -ve number is returned by Go in this situation
+	if(v>=9223372036854775807.0) return maxVal;
+	if(v<=-9223372036854775808.0) return minVal;
+	return ` + native64("((cpp::Int64){0})", "((long){0})", "unchecked((long){0})", "v") + `; // truncates towards zero
+}
+public static function ofUFloat(v:Float):GOint64native {
+	if(Math.isNaN(v)) return minVal;
+	if(v<=0.0) return ofInt(0); // -ve values are invalid here, so return 0
+	if(v>=18446744073709551615.0) return make(0xffffffff,0xffffffff); // largest unsigned number
+	if(v<9223372036854775808.0) return ofFloat(v);
+	return or(ofFloat(v-9223372036854775808.0),minVal); // set the top bit
+}
+public static inline function toString(v:GOint64native):String {
+	return haxe.Int64.toStr(v);
+}
+public static inline function toStr(v:GOint64native):String {
+	return haxe.Int64.toStr(v);
+}
+public static inline function neg(v:GOint64native):GOint64native {
+	return ` + native64("(cpp::Int64)(((cpp::UInt64)0)-(cpp::UInt64){0})", "(-{0})", "unchecked(-{0})", "v") + `;
+}
+public static inline function isZero(v:GOint64native):Bool {
+	return ` + native64("({0}==0)", "({0}==0L)", "({0}==0L)", "v") + `;
+}
+public static inline function isNeg(v:GOint64native):Bool {
+	return ` + native64("({0}<0)", "({0}<0L)", "({0}<0L)", "v") + `;
+}
+public static inline function add(x:GOint64native,y:GOint64native):GOint64native {
+	return ` + native64binary("+") + `;
+}
+public static inline function sub(x:GOint64native,y:GOint64native):GOint64native {
+	return ` + native64binary("-") + `;
+}
+public static inline function mul(x:GOint64native,y:GOint64native):GOint64native {
+	return ` + native64binary("*") + `;
+}
+public static inline function and(x:GOint64native,y:GOint64native):GOint64native {
+	return ` + native64bitwise("&") + `;
+}
+public static inline function or(x:GOint64native,y:GOint64native):GOint64native {
+	return ` + native64bitwise("|") + `;
+}
+public static inline function xor(x:GOint64native,y:GOint64native):GOint64native {
+	return ` + native64bitwise("^") + `;
+}
+public static inline function compare(x:GOint64native,y:GOint64native):Int {
+	return ` + native64("({0}<{1}?-1:({0}>{1}?1:0))", "({0}<{1}?-1:({0}>{1}?1:0))", "({0}<{1}?-1:({0}>{1}?1:0))", "x", "y") + `;
+}
+public static inline function ucompare(x:GOint64native,y:GOint64native):Int {
+	return compare(xor(x,minVal),xor(y,minVal)); // flipping the top bits gives the unsigned order
+}
+public static function shl(x:GOint64native,y:Int):GOint64native {
+	if(y<0 || y>=64) return ofInt(0);
+	return ` + native64("(cpp::Int64)(((cpp::UInt64){0})<<{1})", "({0}<<{1})", "({0}<<{1})", "x", "y") + `;
+}
+public static function shr(x:GOint64native,y:Int):GOint64native { // sign extends
+	if(y<0 || y>=64) return isNeg(x)?ofInt(-1):ofInt(0);
+	return ` + native64("({0}>>{1})", "({0}>>{1})", "({0}>>{1})", "x", "y") + `;
+}
+public static function ushr(x:GOint64native,y:Int):GOint64native { // does not sign extend
+	if(y<0 || y>=64) return ofInt(0);
+	return ` + native64("(cpp::Int64)(((cpp::UInt64){0})>>{1})", "({0}>>>{1})", "unchecked((long)(((ulong){0})>>{1}))", "x", "y") + `;
+}
+private static function checkDiv(y:GOint64native) {
+	if(isZero(y))
+		Scheduler.panicFromHaxe( "attempt to divide 64-bit value by 0");
+}
+public static function div(x:GOint64native,y:GOint64native,isSigned:Bool):GOint64native {
+	checkDiv(y);
+	if(isSigned) {
+		if(compare(y,ofInt(-1))==0) return neg(x); // also handles the special case in the Go spec, without overflow
+		return ` + native64("({0}/{1})", "({0}/{1})", "({0}/{1})", "x", "y") + `;
+	}
+	if(isNeg(y)) // y is at least 2**63, so the answer can only be 0 or 1
+		return ucompare(x,y)>=0?ofInt(1):ofInt(0);
+	if(!isNeg(x))
+		return ` + native64("({0}/{1})", "({0}/{1})", "({0}/{1})", "x", "y") + `;
+	// see Hacker's Delight: divide x/2 signed, then correct the answer by at most 1
+	var q:GOint64native = shl(` + native64("({0}/{1})", "({0}/{1})", "({0}/{1})", "ushr(x,1)", "y") + `,1);
+	if(ucompare(sub(x,mul(q,y)),y)>=0) q=add(q,ofInt(1));
+	return q;
+}
+public static function mod(x:GOint64native,y:GOint64native,isSigned:Bool):GOint64native {
+	checkDiv(y);
+	if(isSigned) {
+		if(compare(y,ofInt(-1))==0) return ofInt(0); // special case in the Go spec
+		return ` + native64("({0}%{1})", "({0}%{1})", "({0}%{1})", "x", "y") + `;
+	}
+	return sub(x,mul(div(x,y,false),y));
+}
+}
+
+#end
+`
+}
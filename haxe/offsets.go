@@ -9,24 +9,40 @@ import (
 	"go/types"
 )
 
-var haxeStdSizes = types.StdSizes{
-	WordSize: 4, // word size in bytes - must be >= 4 (32bits)
-	MaxAlign: 8, // maximum alignment in bytes - must be >= 1
+// intIs64 returns true if the Go int and uint types are 64 bits wide, as requested by the tardisgo -intsize=64 flag.
+func (l langType) intIs64() bool {
+	return l.hc.sizes.WordSize == 8
 }
 
-func fieldOffset(str *types.Struct, fldNum int) int64 {
+// isGOint64Kind returns true for the basic kinds that are held in a Haxe GOint64.
+func (l langType) isGOint64Kind(k types.BasicKind) bool {
+	switch k {
+	case types.Int64, types.Uint64:
+		return true
+	case types.Int, types.Uint:
+		return l.intIs64()
+	}
+	return false
+}
+
+func (l langType) fieldOffset(str *types.Struct, fldNum int) int64 {
 	fieldList := make([]*types.Var, str.NumFields())
 	for f := 0; f < str.NumFields(); f++ {
 		fieldList[f] = str.Field(f)
 	}
-	return haxeStdSizes.Offsetsof(fieldList)[fldNum]
+	return l.hc.sizes.Offsetsof(fieldList)[fldNum]
 }
 
-func arrayOffsetCalc(ele types.Type) string {
+// arrayElementOffset gives the distance between array elements, allowing for alignment
+func (l langType) arrayElementOffset(ele types.Type) int64 {
 	ent := types.NewVar(0, nil, "___temp", ele)
 	fieldList := []*types.Var{ent, ent}
-	off := haxeStdSizes.Offsetsof(fieldList)[1] // to allow for word alignment
-	//off := haxeStdSizes.Sizeof(ele) // ?? or should it be the code above ?
+	return l.hc.sizes.Offsetsof(fieldList)[1] // to allow for word alignment
+}
+
+func (l langType) arrayOffsetCalc(ele types.Type) string {
+	off := l.arrayElementOffset(ele)
+	//off := l.hc.sizes.Sizeof(ele) // ?? or should it be the code above ?
 	if off == 1 {
 		return ""
 	}
@@ -32,6 +32,13 @@ func (l langType) codeUnOp(regTyp types.Type, op string, v interface{}, CommaOK
 	case "*":
 		goTyp := v.(ssa.Value).Type().Underlying().(*types.Pointer).Elem().Underlying()
 
+		if a, slot, isScalar := l.PogoComp().ScalarSlot(v); isScalar {
+			return scalarSlotName(a, slot)
+		}
+		if a, isScalar := l.scalarAlloc(v); isScalar {
+			return l.scalarLoadAll(a, errorInfo)
+		}
+
 		//lt = l.LangType(goTyp, false, errorInfo)
 		iVal := "" + l.IndirectValue(v, errorInfo) + "" // need to cast it to pointer, when using -dce full and closures
 		//switch lt {
@@ -47,12 +54,15 @@ func (l langType) codeUnOp(regTyp types.Type, op string, v interface{}, CommaOK
 				panic(fmt.Sprintf("haxe.codeUnOp can't find oneUsePtr: %#v %s val %s=%s",
 					l.hc.map1usePtr, errorInfo, v.(ssa.Value).Name(), v.(ssa.Value).String()))
 			}
-			return oup.obj + ".get" + loadStoreSuffix(goTyp, true) + oup.off + ")"
+			if oup.slice != "" {
+				return oup.slice + ".getAt" + oup.sfx + "(" + oup.idx + ")"
+			}
+			return oup.obj + ".get" + l.loadStoreSuffix(goTyp, true) + oup.off + ")"
 		}
 		if l.PogoComp().DebugFlag {
 			iVal = "Pointer.check(" + iVal + ")"
 		}
-		return iVal + ".load" + loadStoreSuffix(goTyp, false) + ")" + fmt.Sprintf("/* %v */ ", goTyp)
+		return iVal + ".load" + l.loadStoreSuffix(goTyp, false) + ")" + fmt.Sprintf("/* %v */ ", goTyp)
 		//}
 	case "-":
 		if l.LangType(v.(ssa.Value).Type().Underlying(), false, errorInfo) == "Complex" {
@@ -211,7 +221,7 @@ func (l langType) codeBinOp(regTyp types.Type, op string, v1, v2 interface{}, er
 			}
 
 			if op == "<<" || op == ">>" {
-				v2string = wrapForceToUInt(v2string, v2.(ssa.Value).Type().Underlying().(*types.Basic).Kind())
+				v2string = l.wrapForceToUInt(v2string, v2.(ssa.Value).Type().Underlying().(*types.Basic).Kind())
 			}
 
 			switch op { // roughly in the order of the GOint64 api spec
@@ -273,11 +283,11 @@ func (l langType) codeBinOp(regTyp types.Type, op string, v1, v2 interface{}, er
 					if (v1.(ssa.Value).Type().Underlying().(*types.Basic).Info() & types.IsUnsigned) != 0 {
 						if v1.(ssa.Value).Type().Underlying().(*types.Basic).Kind() == types.Uintptr {
 							// could be comparing pointers cast to uintptr, so force to uint
-							v1string = wrapForceToUInt(v1string, v1.(ssa.Value).Type().Underlying().(*types.Basic).Kind())
+							v1string = l.wrapForceToUInt(v1string, v1.(ssa.Value).Type().Underlying().(*types.Basic).Kind())
 						}
 						if v2.(ssa.Value).Type().Underlying().(*types.Basic).Kind() == types.Uintptr {
 							// could be comparing pointers cast to uintptr, so force to uint
-							v2string = wrapForceToUInt(v2string, v2.(ssa.Value).Type().Underlying().(*types.Basic).Kind())
+							v2string = l.wrapForceToUInt(v2string, v2.(ssa.Value).Type().Underlying().(*types.Basic).Kind())
 						}
 						ret = "(Force.uintCompare(" + v1string + "," + v2string + ")" + op + "0)"
 					} else {
@@ -298,14 +308,14 @@ func (l langType) codeBinOp(regTyp types.Type, op string, v1, v2 interface{}, er
 				}
 			case ">>", "<<":
 				//v1string = wrapForceToUInt(v1string, v1.(ssa.Value).Type().Underlying().(*types.Basic).Kind())
-				v2string = wrapForceToUInt(v2string, v2.(ssa.Value).Type().Underlying().(*types.Basic).Kind())
+				v2string = l.wrapForceToUInt(v2string, v2.(ssa.Value).Type().Underlying().(*types.Basic).Kind())
 				switch v1.(ssa.Value).Type().Underlying().(*types.Basic).Kind() {
 				case types.Uint, types.Uint8, types.Uint16, types.Uint32, types.Uintptr: // unsigned bit shift
 					if op == ">>" {
 						op = ">>>" // logical right shift if unsigned
 					}
 				}
-				bitlenMinus1 := fmt.Sprintf("%d", (haxeStdSizes.Sizeof(v1.(ssa.Value).Type().Underlying())*8)-1)
+				bitlenMinus1 := fmt.Sprintf("%d", (l.hc.sizes.Sizeof(v1.(ssa.Value).Type().Underlying())*8)-1)
 				// TODO consider  putting this code in a Haxe function
 				ret = "({var _v1:Int=" + v1string + " ; var _v2:Int=" + v2string + " ; _v2==0?_v1" //NoOp if v2==0
 				// js requires this out-of-range test - TODO check other targets
@@ -45,12 +45,12 @@ func (l langType) PeepholeOpt(opt, register string, code []ssa.Instruction, erro
 			}
 			switch cod.(type) {
 			case *ssa.IndexAddr:
-				idxString := wrapForceToUInt(l.IndirectValue(cod.(*ssa.IndexAddr).Index, errorInfo),
+				idxString := l.wrapForceToUInt(l.IndirectValue(cod.(*ssa.IndexAddr).Index, errorInfo),
 					cod.(*ssa.IndexAddr).Index.(ssa.Value).Type().Underlying().(*types.Basic).Kind())
 				ele := cod.(*ssa.IndexAddr).X.Type().Underlying().(*types.Pointer).Elem().Underlying().(*types.Array).Elem().Underlying()
-				chainGang += "(" + idxString + arrayOffsetCalc(ele) + ")"
+				chainGang += "(" + idxString + l.arrayOffsetCalc(ele) + ")"
 			case *ssa.FieldAddr:
-				off := fieldOffset(cod.(*ssa.FieldAddr).X.Type().Underlying().(*types.Pointer).Elem().Underlying().(*types.Struct), cod.(*ssa.FieldAddr).Field)
+				off := l.fieldOffset(cod.(*ssa.FieldAddr).X.Type().Underlying().(*types.Pointer).Elem().Underlying().(*types.Struct), cod.(*ssa.FieldAddr).Field)
 				chainGang += fmt.Sprintf(`%d`, off)
 			}
 		}
@@ -85,7 +85,7 @@ func (l langType) PeepholeOpt(opt, register string, code []ssa.Instruction, erro
 		for _, cod := range code[1:] {
 			switch cod.(type) {
 			case *ssa.Index:
-				idx = wrapForceToUInt(l.IndirectValue(cod.(*ssa.Index).Index, errorInfo),
+				idx = l.wrapForceToUInt(l.IndirectValue(cod.(*ssa.Index).Index, errorInfo),
 					cod.(*ssa.Index).Index.Type().Underlying().(*types.Basic).Kind())
 				//if idx != "0" {
 				//	ret += fmt.Sprintf(".addr(%s%s)",
@@ -93,7 +93,7 @@ func (l langType) PeepholeOpt(opt, register string, code []ssa.Instruction, erro
 				//		arrayOffsetCalc(cod.(*ssa.Index).Type().Underlying()))
 				//}
 			case *ssa.Field:
-				fo := fieldOffset(cod.(*ssa.Field).X.Type().Underlying().(*types.Struct), cod.(*ssa.Field).Field)
+				fo := l.fieldOffset(cod.(*ssa.Field).X.Type().Underlying().(*types.Struct), cod.(*ssa.Field).Field)
 				idx = fmt.Sprintf("%d", fo)
 				//if idx != "0" {
 				//	ret += fmt.Sprintf(".fieldAddr(%d)", fo)
@@ -103,15 +103,24 @@ func (l langType) PeepholeOpt(opt, register string, code []ssa.Instruction, erro
 		suffix := ""
 		switch code[len(code)-1].(type) {
 		case *ssa.Index:
-			suffix = loadStoreSuffix(code[len(code)-1].(*ssa.Index).Type().Underlying(), true)
+			suffix = l.loadStoreSuffix(code[len(code)-1].(*ssa.Index).Type().Underlying(), true)
 			//ret += fmt.Sprintf(".load%s); // PEEPHOLE OPTIMIZATION loadObject (Index)\n",
 			//	loadStoreSuffix(code[len(code)-1].(*ssa.Index).Type().Underlying(), false))
 		case *ssa.Field:
-			suffix = loadStoreSuffix(code[len(code)-1].(*ssa.Field).Type().Underlying(), true)
+			suffix = l.loadStoreSuffix(code[len(code)-1].(*ssa.Field).Type().Underlying(), true)
 			//ret += fmt.Sprintf(".load%s); // PEEPHOLE OPTIMIZATION loadObject (Field)\n",
 			//	loadStoreSuffix(code[len(code)-1].(*ssa.Field).Type().Underlying(), false))
 		}
-		if l.is1usePtr(code[0].(*ssa.UnOp).X) {
+		if a, isScalar := l.scalarAlloc(code[0].(*ssa.UnOp).X); isScalar {
+			slot := 0
+			switch cod := code[len(code)-1].(type) {
+			case *ssa.Index:
+				slot = int(cod.Index.(*ssa.Const).Int64())
+			case *ssa.Field:
+				slot = cod.Field
+			}
+			ret += register + "=" + scalarSlotName(a, slot) + "; // PEEPHOLE OPTIMIZATION loadObject (scalar-replaced)\n"
+		} else if l.is1usePtr(code[0].(*ssa.UnOp).X) {
 			oup, found := l.hc.map1usePtr[code[0].(*ssa.UnOp).X]
 			if !found {
 				panic("unable to find virtual 1usePtr")
@@ -325,6 +334,7 @@ func blockContainsBreaks(b *ssa.BasicBlock, producer, consumer ssa.Instruction)
 type oneUsePtr struct {
 	obj, off, objOrig, offOrig string
 	varObj, varOff             bool
+	slice, idx, sfx            string // for typed Slice accessors, the slice, the index and the accessor suffix
 }
 
 func (l langType) reset1useMap() {
@@ -361,7 +371,7 @@ func (l langType) set1usePtr(v ssa.Value, oup oneUsePtr) string {
 		newOff = nam + "off"
 		madeVarOff = true
 	}
-	l.hc.map1usePtr[v] = oneUsePtr{newObj, newOff, oup.obj, oup.off, madeVarObj, madeVarOff}
+	l.hc.map1usePtr[v] = oneUsePtr{obj: newObj, off: newOff, objOrig: oup.obj, offOrig: oup.off, varObj: madeVarObj, varOff: madeVarOff}
 	return ret
 }
 
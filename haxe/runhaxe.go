@@ -34,7 +34,7 @@ func RunHaxe(allFlag *string, LoadTestZipFS bool, TestFS string) {
 			r := <-results
 			fmt.Println(r.output)
 			if (r.err != nil || len(strings.TrimSpace(r.output)) == 0) && *allFlag != "bench" {
-				os.Exit(1) // exit with an error if the test fails, but not for benchmarking
+				os.Exit(exitCode(r.err)) // exit with an error if the test fails, but not for benchmarking
 			}
 			r.backChan <- true
 		}
@@ -63,7 +63,7 @@ func RunHaxe(allFlag *string, LoadTestZipFS bool, TestFS string) {
 			r := <-results
 			fmt.Println(r.output)
 			if r.err != nil {
-				os.Exit(1) // exit with an error if the test fails
+				os.Exit(exitCode(r.err)) // exit with an error if the test fails
 			}
 			r.backChan <- true
 		}
@@ -116,7 +116,7 @@ func RunHaxe(allFlag *string, LoadTestZipFS bool, TestFS string) {
 		r := <-results
 		fmt.Println(r.output)
 		if r.err != nil {
-			os.Exit(1) // exit with an error if the test fails
+			os.Exit(exitCode(r.err)) // exit with an error if the test fails
 		}
 		r.backChan <- true
 
@@ -202,6 +202,19 @@ var allBenchmark = [][][]string{
 	//},
 }
 
+// exitCode returns the exit status of the command which failed with err, so that it is passed on,
+// or 1 if there is no such status, for example when the output was empty.
+func exitCode(err error) int {
+	if ee, ok := err.(*exec.ExitError); ok {
+		if ws, ok := ee.Sys().(interface {
+			ExitStatus() int
+		}); ok && ws.ExitStatus() > 0 {
+			return ws.ExitStatus()
+		}
+	}
+	return 1
+}
+
 type resChan struct {
 	output   string
 	err      error
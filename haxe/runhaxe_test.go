@@ -0,0 +1,37 @@
+// Copyright 2014 Elliott Stoneham and The TARDIS Go Authors
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package haxe
+
+import (
+	"errors"
+	"os/exec"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("no sh to run")
+	}
+	exit3 := exec.Command("sh", "-c", "exit 3").Run()
+	if _, ok := exit3.(*exec.ExitError); !ok {
+		t.Fatalf("running a command exiting with status 3 returned %v, want an *exec.ExitError", exit3)
+	}
+	killed := exec.Command("sh", "-c", "kill $$").Run()
+	for _, tt := range []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"exit status 3", exit3, 3},
+		{"killed by a signal", killed, 1},
+		{"nil, as when the output was empty", nil, 1},
+		{"not an ExitError", errors.New("not run"), 1},
+		{"not found", exec.Command("tardisgo-no-such-command").Run(), 1},
+	} {
+		if got := exitCode(tt.err); got != tt.code {
+			t.Errorf("exitCode of %s (%v) = %d, want %d", tt.name, tt.err, got, tt.code)
+		}
+	}
+}
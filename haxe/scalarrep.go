@@ -0,0 +1,85 @@
+// Copyright 2014 Elliott Stoneham and The TARDIS Go Authors
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package haxe
+
+import (
+	"fmt"
+	"go/types"
+
+	"github.com/tardisgo/tardisgo/pogo"
+	"golang.org/x/tools/go/ssa"
+)
+
+// Scalar replacement of non-escaping allocations, see pogo.ScalarReplaceable() for the rules.
+// Each field or array element of such an allocation is held in its own Haxe variable,
+// so no Object or Pointer is created, and loads and stores become plain variable accesses.
+
+// scalarSlotName gives the Haxe variable holding field or element i of a scalar-replaced allocation.
+func scalarSlotName(a *ssa.Alloc, i int) string {
+	return fmt.Sprintf("_%s_sr%d", a.Name(), i)
+}
+
+// scalarSlotOffset gives the offset that field or element i would have in the Object representation.
+func (l langType) scalarSlotOffset(a *ssa.Alloc, i int) int64 {
+	switch typ := a.Type().Underlying().(*types.Pointer).Elem().Underlying().(type) {
+	case *types.Struct:
+		return l.fieldOffset(typ, i)
+	case *types.Array:
+		return int64(i) * l.arrayElementOffset(typ.Elem().Underlying())
+	}
+	return 0
+}
+
+// scalarAlloc returns the allocation if v is one that has been scalar-replaced.
+func (l langType) scalarAlloc(v interface{}) (*ssa.Alloc, bool) {
+	a, isAlloc := v.(*ssa.Alloc)
+	if isAlloc && l.PogoComp().ScalarReplaceable(a) {
+		return a, true
+	}
+	return nil, false
+}
+
+// scalarDecls declares the Haxe variables for a scalar-replaced allocation.
+func (l langType) scalarDecls(a *ssa.Alloc, position string) string {
+	ret := ""
+	for i, st := range pogo.ScalarSlotTypes(a) {
+		ret += l.haxeVar(scalarSlotName(a, i), l.LangType(st, false, position),
+			"="+l.LangType(st, true, position), position, "FuncStart()") + "\n"
+	}
+	return ret
+}
+
+// scalarZero re-initialises the variables of a scalar-replaced allocation, as each execution of an Alloc gives a zero value.
+func (l langType) scalarZero(a *ssa.Alloc, errorInfo string) string {
+	ret := ""
+	for i, st := range pogo.ScalarSlotTypes(a) {
+		ret += scalarSlotName(a, i) + "=" + l.LangType(st, true, errorInfo) + "; "
+	}
+	return ret + "/* scalar-replaced " + a.Name() + " */"
+}
+
+// scalarLoadAll builds an Object from the variables of a scalar-replaced allocation, for when the whole value is loaded.
+func (l langType) scalarLoadAll(a *ssa.Alloc, errorInfo string) string {
+	ret := "{var _sr=" + l.allocNewObject(a.Type()) + ";"
+	for i, st := range pogo.ScalarSlotTypes(a) {
+		ret += fmt.Sprintf("_sr.set%s%d,%s);", l.loadStoreSuffix(st.Underlying(), true),
+			l.scalarSlotOffset(a, i), scalarSlotName(a, i))
+	}
+	return ret + "_sr;}"
+}
+
+// scalarStoreAll copies a whole value into the variables of a scalar-replaced allocation.
+func (l langType) scalarStoreAll(a *ssa.Alloc, v interface{}, errorInfo string) string {
+	if c, isConst := v.(*ssa.Const); isConst && c.Value == nil {
+		return l.scalarZero(a, errorInfo)
+	}
+	val := l.IndirectValue(v, errorInfo)
+	ret := ""
+	for i, st := range pogo.ScalarSlotTypes(a) {
+		ret += fmt.Sprintf("%s=%s.get%s%d); ", scalarSlotName(a, i), val,
+			l.loadStoreSuffix(st.Underlying(), true), l.scalarSlotOffset(a, i))
+	}
+	return ret + "/* scalar-replaced " + a.Name() + " */"
+}
@@ -0,0 +1,40 @@
+// The Go code for TestExports, with functions, methods and a struct type exported to Haxe.
+
+package main
+
+// Point is a point on a plane.
+//
+//tardisgo:export
+type Point struct {
+	X, Y   float64
+	Tags   []string
+	hidden int
+}
+
+// Scale multiplies the point by f.
+//tardisgo:export
+func (p *Point) Scale(f float64) { p.X *= f; p.Y *= f }
+
+//tardisgo:export Label
+func (p Point) String(prefix string) string { return prefix }
+
+// notExported is a method that Haxe code cannot call.
+func (p *Point) notExported() {}
+
+// Area gives the area of a rectangle.
+//tardisgo:export Geometry.area
+func Area(w, h float64) float64 { return w * h }
+
+//tardisgo:export Geometry.perimeter
+func Perimeter(w, h float64) float64 { return 2 * (w + h) }
+
+//tardisgo:export
+func Split(s string, counts map[string]int, in []int) ([]string, *Point, error) { return nil, nil, nil }
+
+//tardisgo:export
+func Origin() Point { return Point{} }
+
+//tardisgo:export
+func Clear(_ *Point, ptr []*Point, names map[int][]string) {}
+
+func main() {}
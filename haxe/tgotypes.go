@@ -3,6 +3,7 @@ package haxe
 import (
 	"fmt"
 	"reflect"
+	"strconv"
 	"unicode"
 	"unicode/utf8"
 
@@ -21,11 +22,126 @@ func escapedTypeString(s string) string {
 	buf := []byte(s)
 	r := ""
 	for _, ch := range buf {
-		r += fmt.Sprintf("\\x%x", ch)
+		r += fmt.Sprintf("\\x%02x", ch) // Haxe requires two hex digits
 	}
 	return r
 }
 
+// reflectTypeString returns the string for t that the gc compiler gives to reflect,
+// which differs from types.TypeString: package names rather than paths, byte and rune as uint8 and int32,
+// no parameter names and spaces inside struct and interface braces.
+// Encoding packages, such as encoding/gob, rely on these names.
+func reflectTypeString(t types.Type) string {
+	switch tt := t.(type) {
+	case *types.Basic:
+		switch tt.Kind() {
+		case types.Byte:
+			return "uint8"
+		case types.Rune:
+			return "int32"
+		case types.UnsafePointer:
+			return "unsafe.Pointer"
+		}
+		return tt.Name()
+	case *types.Named:
+		obj := tt.Obj()
+		if obj.Pkg() == nil {
+			return obj.Name() // error
+		}
+		return obj.Pkg().Name() + "." + obj.Name()
+	case *types.Pointer:
+		return "*" + reflectTypeString(tt.Elem())
+	case *types.Slice:
+		return "[]" + reflectTypeString(tt.Elem())
+	case *types.Array:
+		return fmt.Sprintf("[%d]", tt.Len()) + reflectTypeString(tt.Elem())
+	case *types.Map:
+		return "map[" + reflectTypeString(tt.Key()) + "]" + reflectTypeString(tt.Elem())
+	case *types.Chan:
+		elem := reflectTypeString(tt.Elem())
+		switch tt.Dir() {
+		case types.SendOnly:
+			return "chan<- " + elem
+		case types.RecvOnly:
+			return "<-chan " + elem
+		}
+		if ec, ok := tt.Elem().(*types.Chan); ok && ec.Dir() == types.RecvOnly {
+			elem = "(" + elem + ")"
+		}
+		return "chan " + elem
+	case *types.Signature:
+		return "func" + reflectSignatureString(tt)
+	case *types.Struct:
+		if tt.NumFields() == 0 {
+			return "struct {}"
+		}
+		r := "struct {"
+		for i := 0; i < tt.NumFields(); i++ {
+			if i > 0 {
+				r += ";"
+			}
+			fld := tt.Field(i)
+			r += " "
+			if !fld.Anonymous() {
+				r += fld.Name() + " "
+			}
+			r += reflectTypeString(fld.Type())
+			if tag := tt.Tag(i); tag != "" {
+				r += " " + strconv.Quote(tag)
+			}
+		}
+		return r + " }"
+	case *types.Interface:
+		if tt.NumMethods() == 0 {
+			return "interface {}"
+		}
+		r := "interface {"
+		for i := 0; i < tt.NumMethods(); i++ { // methods are sorted by Id
+			if i > 0 {
+				r += ";"
+			}
+			meth := tt.Method(i)
+			r += " "
+			if !meth.Exported() && meth.Pkg() != nil {
+				r += meth.Pkg().Name() + "."
+			}
+			r += meth.Name() + reflectSignatureString(meth.Type().(*types.Signature))
+		}
+		return r + " }"
+	case *types.Tuple:
+		return reflectTupleString(tt, false)
+	}
+	return t.String()
+}
+
+// reflectSignatureString returns the parameters and results of sig, as they follow "func" in reflectTypeString.
+func reflectSignatureString(sig *types.Signature) string {
+	r := reflectTupleString(sig.Params(), sig.Variadic())
+	switch sig.Results().Len() {
+	case 0:
+	case 1:
+		r += " " + reflectTypeString(sig.Results().At(0).Type())
+	default:
+		r += " " + reflectTupleString(sig.Results(), false)
+	}
+	return r
+}
+
+func reflectTupleString(tup *types.Tuple, variadic bool) string {
+	r := "("
+	for i := 0; i < tup.Len(); i++ {
+		if i > 0 {
+			r += ", "
+		}
+		if variadic && i == tup.Len()-1 {
+			r += "..." + reflectTypeString(tup.At(i).Type().(*types.Slice).Elem())
+		} else {
+			r += reflectTypeString(tup.At(i).Type())
+		}
+	}
+	return r + ")"
+}
+
 func synthTypesFor(t types.Type) {}
 
 func getTypeInfo(t types.Type, tname string) (kind reflect.Kind, name string) {
@@ -138,6 +254,17 @@ func (l langType) BuildTypeHaxe() string {
 	}
 	l.buildTBI()
 
+	l.hc.minimalTypes = make(map[int]bool)
+	for i, t := range l.hc.typesByID {
+		if i > 0 && !l.PogoComp().TypeHasReflectInfo(t) {
+			switch t.Underlying().(type) {
+			case *types.Basic, *types.Interface: // always given in full, as they are small and used by the runtime
+			default:
+				l.hc.minimalTypes[i] = true
+			}
+		}
+	}
+
 	ret := "class Tgotypes {\n"
 
 	for i, t := range l.hc.typesByID {
@@ -167,7 +294,7 @@ func (l langType) BuildTypeHaxe() string {
 }
 
 func (l langType) typeBuild(i int, t types.Type) string {
-	sizes := &haxeStdSizes
+	sizes := &l.hc.sizes
 	ret := fmt.Sprintf( // sizeof largest struct (funcType) is 76
 		"private static var type%dptr:Pointer=null; // %s\npublic static function type%d():Pointer { if(type%dptr==null) { type%dptr=Pointer.make(Object.make(80));",
 		i, t.String(), i, i, i)
@@ -187,7 +314,12 @@ func (l langType) typeBuild(i int, t types.Type) string {
 		t = t.(*types.Named).Underlying()
 	}
 
-	switch kind & kindMask {
+	fill := kind & kindMask
+	if l.hc.minimalTypes[i] {
+		fill = reflect.Invalid // only the rtype is given, as reflect cannot reach this type
+	}
+
+	switch fill {
 	case reflect.Invalid, reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
 		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
 		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128, reflect.String, reflect.UnsafePointer:
@@ -256,7 +388,8 @@ func (l langType) typeBuild(i int, t types.Type) string {
 			fret += "\n\t\t/*name:*/ \"" + name + "\",\n"
 			fret += "\t\t/*pkgPath:*/ \"" + path + "\",\n"
 			fret += fmt.Sprintf("\t\t/*typ:*/ type%d(),// %s\n", l.hc.pte.At(fldInfo.Type()), fldInfo.Type().String())
-			fret += "\t\t/*tag:*/ \"" + escapedTypeString(t.(*types.Struct).Tag(fld)) + "\", // " + t.(*types.Struct).Tag(fld) + "\n"
+			fret += "\t\t/*tag:*/ \"" + escapedTypeString(t.(*types.Struct).Tag(fld)) + "\", // " +
+				strconv.Quote(t.(*types.Struct).Tag(fld)) + "\n" // quoted, as a tag may contain a newline
 			fret += fmt.Sprintf("\t\t/*offset:*/ %d\n", offs[fld])
 
 			fret += "\t)"
@@ -355,12 +488,16 @@ func (l langType) rtypeBuild(i int, sizes types.Sizes, t types.Type, name string
 		alg = "true"
 	}
 	ret += fmt.Sprintf("\t/*comprable:*/ %s,\n", alg) // TODO change this to be the actual function
-	ret += fmt.Sprintf("\t/*string:*/ \"%s\", // %s\n", escapedTypeString(t.String()), t.String())
-	ret += fmt.Sprintf("\t/*uncommonType:*/ %s,\n", l.uncommonBuild(i, sizes, name, t))
+	ret += fmt.Sprintf("\t/*string:*/ \"%s\", // %s\n", escapedTypeString(reflectTypeString(t)), t.String())
+	uct := "null"
+	if !l.hc.minimalTypes[i] || l.PogoComp().TypeHasMethodInfo(t) {
+		uct = l.uncommonBuild(i, sizes, name, t) // required for method-by-name lookup, even if reflect cannot reach the type
+	}
+	ret += fmt.Sprintf("\t/*uncommonType:*/ %s,\n", uct)
 	ptt := "null"
 	for pti, pt := range l.hc.typesByID {
 		_, isPtr := pt.(*types.Pointer)
-		if isPtr {
+		if isPtr && !l.hc.minimalTypes[i] {
 			ele := l.hc.pte.At(pt.(*types.Pointer).Elem())
 			if ele != nil {
 				if i == ele.(int) {
@@ -375,18 +512,10 @@ func (l langType) rtypeBuild(i int, sizes types.Sizes, t types.Type, name string
 }
 
 func (l langType) uncommonBuild(i int, sizes types.Sizes, name string, t types.Type) string {
-	pkgPath := ""
-	tt := t
-	switch tt.(type) {
-	case *types.Pointer:
-		el, ok := tt.(*types.Pointer).Elem().(*types.Named)
-		if ok {
-			tt = el
-		}
-	}
-	switch tt.(type) {
+	pkgPath := "" // only named types have a package path, as in the gc compiler, so *T has none
+	switch t.(type) {
 	case *types.Named:
-		obj := tt.(*types.Named).Obj()
+		obj := t.(*types.Named).Obj()
 		if obj != nil {
 			pkg := obj.Pkg()
 			if pkg != nil {
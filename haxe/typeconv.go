@@ -0,0 +1,308 @@
+// Copyright 2014 Elliott Stoneham and The TARDIS Go Authors
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package haxe
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// Values are converted between their Go and natural Haxe representations at the boundary between the two,
+// both by the facade classes of exported Go code (see export.go) and by hx.ToHaxe() and hx.FromHaxe():
+// strings to String, int and uint to Int, slices to Array, maps with String or Int keys to Map,
+// structs to anonymous objects of their exported fields, and pointers to (and values of) exported struct types
+// to their facade class, converting any values they hold in the same way.
+// Other types are passed as they are held by the Go runtime, for example GOint64, Interface or Pointer,
+// as are recursive types where they recur.
+
+// A converter generates the Haxe code for those conversions.
+type converter struct {
+	l          langType
+	classes    map[*types.Named]string // the facade classes of the exported struct types
+	inProgress map[*types.Named]bool   // the types being converted, to stop at recursive types
+}
+
+// enter marks a named type as being converted, returning false if it already is,
+// in which case the value should be passed as it is; leave must be called when it returns true.
+func (c *converter) enter(t types.Type) bool {
+	if n, isNamed := t.(*types.Named); isNamed {
+		if c.inProgress[n] {
+			return false
+		}
+		if c.inProgress == nil {
+			c.inProgress = make(map[*types.Named]bool)
+		}
+		c.inProgress[n] = true
+	}
+	return true
+}
+
+func (c *converter) leave(t types.Type) {
+	if n, isNamed := t.(*types.Named); isNamed {
+		delete(c.inProgress, n)
+	}
+}
+
+// haxeType gives the Haxe type of the converted value.
+func (c *converter) haxeType(t types.Type) string {
+	if cl := c.structClass(t); cl != "" {
+		return cl
+	}
+	if !c.enter(t) {
+		return c.l.LangType(t.Underlying(), false, "converted type")
+	}
+	defer c.leave(t)
+	switch ut := t.Underlying().(type) {
+	case *types.Basic:
+		if c.l.isGOint64Kind(ut.Kind()) && (ut.Kind() == types.Int || ut.Kind() == types.Uint) {
+			return "Int" // still an Int in Haxe code when -intsize=64
+		}
+	case *types.Slice:
+		return "Array<" + c.haxeType(ut.Elem()) + ">"
+	case *types.Map:
+		if k := c.haxeType(ut.Key()); k == "String" || k == "Int" {
+			return "Map<" + k + "," + c.haxeType(ut.Elem()) + ">"
+		}
+	case *types.Struct:
+		fields := []string{}
+		for f := 0; f < ut.NumFields(); f++ {
+			if fld := ut.Field(f); convertedField(fld) {
+				fields = append(fields, fld.Name()+":"+c.haxeType(fld.Type()))
+			}
+		}
+		return "{" + strings.Join(fields, ", ") + "}"
+	}
+	return c.l.LangType(t.Underlying(), false, "converted type")
+}
+
+// convertible returns true if the Go and Haxe forms of values of the type differ, so that they are converted:
+// strings (and int and uint when they are GOint64), slices, maps with String or Int keys,
+// structs with converted fields, and the exported struct types.
+func (c *converter) convertible(t types.Type) bool {
+	if c.structClass(t) != "" {
+		return true
+	}
+	switch ut := t.Underlying().(type) {
+	case *types.Basic:
+		return ut.Kind() == types.String ||
+			(c.l.isGOint64Kind(ut.Kind()) && (ut.Kind() == types.Int || ut.Kind() == types.Uint))
+	case *types.Slice:
+		return true
+	case *types.Map:
+		return strings.HasPrefix(c.haxeType(t), "Map<")
+	case *types.Struct:
+		for f := 0; f < ut.NumFields(); f++ {
+			if convertedField(ut.Field(f)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// convertedField returns true if the struct field is held in the converted anonymous object.
+func convertedField(fld *types.Var) bool {
+	return fld.Exported() && !fld.Anonymous() && isHaxeIdent(fld.Name())
+}
+
+// structClass returns the facade class name for a pointer to, or a value of, an exported struct type.
+func (c *converter) structClass(t types.Type) string {
+	if p, isPtr := t.(*types.Pointer); isPtr {
+		t = p.Elem()
+	}
+	if n, isNamed := t.(*types.Named); isNamed {
+		return c.classes[n]
+	}
+	return ""
+}
+
+// toHaxe gives the Haxe code to convert the Go value of the expression.
+// Nested conversions use local variables with the depth as a suffix, so that their names are unique.
+func (c *converter) toHaxe(t types.Type, expr string, depth int) string {
+	d := fmt.Sprintf("%d", depth)
+	if cl := c.structClass(t); cl != "" {
+		if _, isPtr := t.(*types.Pointer); isPtr {
+			return "{var _p" + d + ":Pointer=" + expr + "; _p" + d + "==null?null:new " + cl + "(_p" + d + ");}"
+		}
+		return "new " + cl + "(Pointer.make(" + expr + "))"
+	}
+	ht := c.haxeType(t) // before entering the type, which would stop at it
+	if !c.enter(t) {
+		return expr
+	}
+	defer c.leave(t)
+	switch ut := t.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case ut.Kind() == types.String:
+			return "Force.toHaxeString(" + expr + ")"
+		case c.l.isGOint64Kind(ut.Kind()) && (ut.Kind() == types.Int || ut.Kind() == types.Uint):
+			return "GOint64.toInt(" + expr + ")"
+		}
+	case *types.Slice:
+		return "{var _s" + d + ":Slice=" + expr + "; var _a" + d + "=new " + ht + "();" +
+			" if(_s" + d + "!=null) for(_i" + d + " in 0..._s" + d + ".len()) _a" + d + ".push(" +
+			c.toHaxe(ut.Elem(), "_s"+d+".itemAddr(_i"+d+").load"+c.l.loadStoreSuffix(ut.Elem(), false)+")", depth+1) +
+			"); _a" + d + ";}"
+	case *types.Map:
+		if !strings.HasPrefix(ht, "Map<") {
+			break // a key type that a Haxe Map cannot hold
+		}
+		return "{var _m" + d + ":GOmap=" + expr + "; var _h" + d + "=new " + ht + "();" +
+			" if(_m" + d + "!=null) { var _r" + d + "=_m" + d + ".range(); var _n" + d + "=_r" + d + ".next();" +
+			" while(_n" + d + ".r0) { _h" + d + ".set(" + c.toHaxe(ut.Key(), "_n"+d+".r1", depth+1) + "," +
+			c.toHaxe(ut.Elem(), "_n"+d+".r2", depth+1) + "); _n" + d + "=_r" + d + ".next(); } } _h" + d + ";}"
+	case *types.Struct:
+		fields := []string{}
+		for f := 0; f < ut.NumFields(); f++ {
+			if fld := ut.Field(f); convertedField(fld) {
+				fields = append(fields, fld.Name()+":"+c.toHaxe(fld.Type(),
+					fmt.Sprintf("_p%s.fieldAddr(%d).load%s)", d, c.l.fieldOffset(ut, f), c.l.loadStoreSuffix(fld.Type(), false)),
+					depth+1))
+			}
+		}
+		return "{var _p" + d + "=Pointer.make(" + expr + "); var _o" + d + "={" + strings.Join(fields, ", ") + "}; _o" + d + ";}"
+	}
+	return expr
+}
+
+// toGo gives the Haxe code to convert the expression to the Go value of the type.
+func (c *converter) toGo(t types.Type, expr string, depth int) string {
+	d := fmt.Sprintf("%d", depth)
+	if cl := c.structClass(t); cl != "" {
+		if _, isPtr := t.(*types.Pointer); isPtr {
+			return "{var _c" + d + ":" + cl + "=" + expr + "; _c" + d + "==null?null:_c" + d + ".ptr;}"
+		}
+		return expr + ".ptr.load" + c.l.loadStoreSuffix(t, false) + ")"
+	}
+	ht := c.haxeType(t) // before entering the type, which would stop at it
+	if !c.enter(t) {
+		return expr
+	}
+	defer c.leave(t)
+	switch ut := t.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case ut.Kind() == types.String:
+			return "Force.fromHaxeString(" + expr + ")"
+		case c.l.isGOint64Kind(ut.Kind()) && (ut.Kind() == types.Int || ut.Kind() == types.Uint):
+			return "GOint64.ofInt(" + expr + ")"
+		}
+	case *types.Slice:
+		itemSize := "1" + c.l.arrayOffsetCalc(ut.Elem().Underlying())
+		return "{var _a" + d + ":" + ht + "=" + expr + "; var _s" + d + ":Slice=null;" +
+			" if(_a" + d + "!=null) { _s" + d + "=" +
+			newSliceCode("", "", "_a"+d+".length", "_a"+d+".length", "converted slice", itemSize, c.l.typedSliceKind(ut.Elem())) + ";" +
+			" for(_i" + d + " in 0..._a" + d + ".length) _s" + d + ".itemAddr(_i" + d + ").store" +
+			c.l.loadStoreSuffix(ut.Elem(), true) + c.toGo(ut.Elem(), "_a"+d+"[_i"+d+"]", depth+1) + "); } _s" + d + ";}"
+	case *types.Map:
+		if !strings.HasPrefix(ht, "Map<") {
+			break
+		}
+		return "{var _h" + d + ":" + ht + "=" + expr + "; var _m" + d + ":GOmap=null;" +
+			" if(_h" + d + "!=null) { _m" + d + "=" + c.l.LangType(ut, true, "converted map") + ";" +
+			" for(_k" + d + " in _h" + d + ".keys()) _m" + d + ".set(" + c.toGo(ut.Key(), "_k"+d, depth+1) + "," +
+			c.toGo(ut.Elem(), "_h"+d+".get(_k"+d+")", depth+1) + "); } _m" + d + ";}"
+	case *types.Struct:
+		ret := "{var _v" + d + ":Dynamic=" + expr + fmt.Sprintf("; var _o%s=Object.make(%d);", d, c.l.hc.sizes.Sizeof(ut)) +
+			" if(_v" + d + "!=null) { var _p" + d + "=Pointer.make(_o" + d + ");"
+		for f := 0; f < ut.NumFields(); f++ {
+			if fld := ut.Field(f); convertedField(fld) {
+				ret += fmt.Sprintf(" _p%s.fieldAddr(%d).store%s", d, c.l.fieldOffset(ut, f), c.l.loadStoreSuffix(fld.Type(), true)) +
+					c.toGo(fld.Type(), "_v"+d+"."+fld.Name(), depth+1) + ");"
+			}
+		}
+		return ret + " } _o" + d + ";}"
+	}
+	return expr
+}
+
+// convIndex returns the number of the TypeConv functions for a type, adding them if required.
+func (l langType) convIndex(t types.Type) int {
+	if idx := l.hc.convTypes.At(t); idx != nil {
+		return idx.(int)
+	}
+	l.hc.convTypes.Set(t, len(l.hc.convList))
+	l.hc.convList = append(l.hc.convList, t)
+	return len(l.hc.convList) - 1
+}
+
+// hxToHaxe gives the code for hx.ToHaxe(), converting the value directly if its type is known.
+func (l langType) hxToHaxe(v ssa.Value, errorInfo string) string {
+	if mi, isMI := v.(*ssa.MakeInterface); isMI {
+		return fmt.Sprintf("TypeConv.toHaxe%d(%s);", l.convIndex(mi.X.Type()), l.IndirectValue(mi.X, errorInfo))
+	}
+	l.hc.convDynamic = true
+	return "TypeConv.toHaxe(" + l.IndirectValue(v, errorInfo) + ");"
+}
+
+// hxFromHaxe gives the code for hx.FromHaxe(), converting the value directly if the type of the pointer is known.
+func (l langType) hxFromHaxe(v, ptr ssa.Value, errorInfo string) string {
+	if mi, isMI := ptr.(*ssa.MakeInterface); isMI {
+		pt, isPtr := mi.X.Type().Underlying().(*types.Pointer)
+		if !isPtr {
+			l.PogoComp().LogError(errorInfo, "Haxe", fmt.Errorf("hx.FromHaxe() needs a pointer, not %s", mi.X.Type()))
+			return ""
+		}
+		return fmt.Sprintf("%s.store%sTypeConv.toGo%d(%s));", l.IndirectValue(mi.X, errorInfo),
+			l.loadStoreSuffix(pt.Elem(), true), l.convIndex(pt.Elem()), l.IndirectValue(v, errorInfo))
+	}
+	l.hc.convDynamic = true
+	return "TypeConv.fromHaxe(" + l.IndirectValue(ptr, errorInfo) + "," + l.IndirectValue(v, errorInfo) + ");"
+}
+
+// typeConvClass writes the TypeConv class, with functions to convert each type used by hx.ToHaxe() and hx.FromHaxe().
+// If the type was not known when compiling a call, the run-time type of the interface value chooses the function,
+// so every convertible type of value held in an interface then needs the functions,
+// other values are passed as they are, and stored as they are by hx.FromHaxe().
+func (l langType) typeConvClass() {
+	if len(l.hc.convList) == 0 && !l.hc.convDynamic {
+		return
+	}
+	c := l.exports().converter
+	if l.hc.convDynamic {
+		for _, t := range l.hc.pteKeys {
+			if c.convertible(t) {
+				l.convIndex(t)
+			}
+			if pt, isPtr := t.Underlying().(*types.Pointer); isPtr && c.convertible(pt.Elem()) {
+				l.convIndex(pt.Elem()) // for hx.FromHaxe()
+			}
+		}
+	}
+	ret := "class TypeConv {\n"
+	for i, t := range l.hc.convList {
+		ret += fmt.Sprintf("public static function toHaxe%d(v:Dynamic):Dynamic { // %s\n\treturn %s;\n}\n",
+			i, t, c.toHaxe(t, "v", 0))
+		ret += fmt.Sprintf("public static function toGo%d(v:Dynamic):Dynamic { // %s\n\treturn %s;\n}\n",
+			i, t, c.toGo(t, "v", 0))
+	}
+	if l.hc.convDynamic {
+		ret += "public static function toHaxe(i:Interface):Dynamic {\n\tif(i==null) return null;\n\tswitch(i.typ){\n"
+		for _, t := range l.hc.pteKeys {
+			if idx := l.hc.convTypes.At(t); idx != nil {
+				ret += fmt.Sprintf("\tcase %d: return toHaxe%d(i.val); // %s\n", l.hc.pte.At(t), idx, t)
+			}
+		}
+		ret += "\t}\n\treturn Force.toHaxeParam(i);\n}\n"
+		ret += "public static function fromHaxe(i:Interface,v:Dynamic):Void {\n\tif(i!=null) switch(i.typ){\n"
+		for _, t := range l.hc.pteKeys {
+			if pt, isPtr := t.Underlying().(*types.Pointer); isPtr {
+				if idx := l.hc.convTypes.At(pt.Elem()); idx != nil {
+					ret += fmt.Sprintf("\tcase %d: cast(i.val,Pointer).store%stoGo%d(v)); return; // %s\n",
+						l.hc.pte.At(t), l.loadStoreSuffix(pt.Elem(), true), idx, t)
+				} else { // as passed by toHaxe()
+					ret += fmt.Sprintf("\tcase %d: cast(i.val,Pointer).store%sv); return; // %s\n",
+						l.hc.pte.At(t), l.loadStoreSuffix(pt.Elem(), true), t)
+				}
+			}
+		}
+		ret += "\t}\n\tScheduler.panicFromHaxe(\"hx.FromHaxe() needs a pointer, not \"+(i==null?\"nil\":TypeInfo.getName(i.typ)));\n}\n"
+	}
+	l.PogoComp().WriteAsClass("TypeConv", ret+"}\n")
+}
@@ -0,0 +1,73 @@
+// Copyright 2014 Elliott Stoneham and The TARDIS Go Authors
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package haxe
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+)
+
+// The element types of the most commonly used slices, which are given their own typed accessors on the Haxe Slice class.
+// Indexing these slices then compiles to a direct access to the underlying Object, without creating a Pointer.
+var typedSliceElems = []types.Type{
+	types.Typ[types.Uint8],                      // []byte
+	types.Typ[types.Int],                        // []int
+	types.Typ[types.Int32],                      // []rune
+	types.Typ[types.Float64],                    // []float64
+	types.Typ[types.String],                     // []string
+	types.NewInterfaceType(nil, nil).Complete(), // []interface{}
+}
+
+// typedSliceSuffix returns the suffix of the typed Slice accessor for the given element type, if there is one.
+func (l langType) typedSliceSuffix(ele types.Type) (string, bool) {
+	for _, te := range typedSliceElems {
+		if types.Identical(ele.Underlying(), te) {
+			return strings.TrimSuffix(l.loadStoreSuffix(te, true), "("), true
+		}
+	}
+	return "", false
+}
+
+// typedSliceKind returns the Haxe Object kind that gives the memory of new slices of the element type
+// a native typed array, or "0" for the general purpose storage.
+func (l langType) typedSliceKind(ele types.Type) string {
+	if b, ok := ele.Underlying().(*types.Basic); ok {
+		switch b.Kind() {
+		case types.Uint8:
+			return "Object.kindUint8"
+		case types.Int32:
+			return "Object.kindInt32"
+		case types.Int:
+			if !l.intIs64() {
+				return "Object.kindInt32"
+			}
+		case types.Float64:
+			return "Object.kindFloat64"
+		}
+	}
+	return "0"
+}
+
+// typedSliceAccessors returns the Haxe code for the typed accessors of the Slice class.
+// The offset of each item is calculated with the constant item size for that element type.
+func (l langType) typedSliceAccessors() string {
+	ret := "\t// typed accessors for the most common element types, these do not check the index as that is done separately\n"
+	done := make(map[string]bool)
+	for _, te := range typedSliceElems {
+		sfx, _ := l.typedSliceSuffix(te)
+		if done[sfx] { // int and int32 share accessors when int is 32 bits
+			continue
+		}
+		done[sfx] = true
+		ht := l.LangType(te, false, "typedSliceAccessors()")
+		off := "baseArray.off+((idx+start)" + l.arrayOffsetCalc(te) + ")"
+		ret += fmt.Sprintf("\tpublic inline function getAt%s(idx:Int):%s {\n\t\treturn baseArray.obj.get%s(%s);\n\t}\n",
+			sfx, ht, sfx, off)
+		ret += fmt.Sprintf("\tpublic inline function setAt%s(idx:Int,v:%s):Void {\n\t\tbaseArray.obj.set%s(%s,v);\n\t}\n",
+			sfx, ht, sfx, off)
+	}
+	return ret
+}
@@ -45,8 +45,19 @@ func (l langType) LangType(t types.Type, retInitVal bool, errorInfo string) stri
 					return "new Complex(0.0,0.0)"
 				}
 				return "Complex"
-			case types.Int, types.Int8, types.Int16, types.Int32, types.UntypedRune,
-				types.Uint8, types.Uint16, types.Uint, types.Uint32: // NOTE: untyped runes default to Int without a warning
+			case types.Int, types.Uint:
+				if l.intIs64() {
+					if retInitVal {
+						return "GOint64.ofInt(0)"
+					}
+					return "GOint64"
+				}
+				if retInitVal {
+					return "0"
+				}
+				return "Int"
+			case types.Int8, types.Int16, types.Int32, types.UntypedRune,
+				types.Uint8, types.Uint16, types.Uint32: // NOTE: untyped runes default to Int without a warning
 				if retInitVal {
 					return "0"
 				}
@@ -114,17 +125,17 @@ func (l langType) LangType(t types.Type, retInitVal bool, errorInfo string) stri
 			if retInitVal {
 				return "new Slice(Pointer.make(" +
 					"Object.make(0)" +
-					"),0,0,0," + "1" + arrayOffsetCalc(t.(*types.Slice).Elem().Underlying()) + ")"
+					"),0,0,0," + "1" + l.arrayOffsetCalc(t.(*types.Slice).Elem().Underlying()) + ")"
 			}
 			return "Slice"
 		case *types.Array:
 			if retInitVal {
-				return fmt.Sprintf("Object.make(%d)", haxeStdSizes.Sizeof(t))
+				return fmt.Sprintf("Object.make(%d)", l.hc.sizes.Sizeof(t))
 			}
 			return "Object"
 		case *types.Struct:
 			if retInitVal {
-				return fmt.Sprintf("Object.make(%d)", haxeStdSizes.Sizeof(t.(*types.Struct).Underlying()))
+				return fmt.Sprintf("Object.make(%d)", l.hc.sizes.Sizeof(t.(*types.Struct).Underlying()))
 			}
 			return "Object"
 		case *types.Tuple: // what is returned by a call and some other instructions, not in the Go language spec!
@@ -529,12 +540,31 @@ func (l langType) EmitTypeInfo() string {
 		}
 		ret += "];\n"
 	*/
+	// The compiled types are found by their names with full package paths, as the reflect strings only give package names,
+	// so are not unique; the types created by reflect at run time are found by their reflect strings.
 	ret += "public static function getId(name:String):Int {\n"
-	ret += "\tvar t:Int;\n"
+	ret += "\tswitch(name){\n"
+	idNames := make(map[string]bool)
+	for i, t := range l.hc.typesByID {
+		if i > 0 && !idNames[t.String()] { // the first of identical types is used
+			idNames[t.String()] = true
+			ret += fmt.Sprintf("\tcase \"%s\": return %d; // %s\n", escapedTypeString(t.String()), i, t.String())
+		}
+	}
+	ret += "\tdefault:\n"
 	//ret += "\ttry { t=typIDs[name];\n"
 	//ret += "\t} catch(x:Dynamic) { Scheduler.panicFromHaxe(\"TraceInfo.getId() not found:\"+name+x); t=-1; } ;\n"
-	ret += "\t" + `t = Go_haxegoruntime_getTTypeIIDD.callFromRT(0,name);` + "\n"
-	ret += "\treturn t;\n}\n"
+	ret += "\t\t" + `return Go_haxegoruntime_getTTypeIIDD.callFromRT(0,name);` + "\n"
+	ret += "\t}\n}\n"
+
+	// function to answer the question does the type only have an rtype, as reflect cannot reach it?
+	ret += "public static function isMinimal(id:Int):Bool {\nswitch(id){" + "\n"
+	for i := range l.hc.typesByID {
+		if l.hc.minimalTypes[i] {
+			ret += fmt.Sprintf("case %d: return true;\n", i)
+		}
+	}
+	ret += "default: return false;}}\n"
 
 	//function to answer the question is the type a concrete value?
 	ret += "public static function isConcrete(t:Int):Bool {\nswitch(t){" + "\n"
@@ -614,6 +644,8 @@ func (l langType) EmitTypeInfo() string {
 
 	l.PogoComp().WriteAsClass("TypeZero", ret)
 
+	l.typeConvClass()
+
 	return ""
 }
 
@@ -626,7 +658,7 @@ func fixKeyWds(w string) string {
 	}
 }
 
-func loadStoreSuffix(T types.Type, hasParameters bool) string {
+func (l langType) loadStoreSuffix(T types.Type, hasParameters bool) string {
 	if bt, ok := T.Underlying().(*types.Basic); ok {
 		switch bt.Kind() {
 		case types.Bool,
@@ -645,20 +677,26 @@ func loadStoreSuffix(T types.Type, hasParameters bool) string {
 		case types.Uint8: // to avoid "byte"
 			return "_uint8("
 		case types.Int, types.Int32: // for int and to avoid "rune"
+			if bt.Kind() == types.Int && l.intIs64() {
+				return "_int64("
+			}
 			return "_int32("
 		case types.Uint, types.Uint32:
+			if bt.Kind() == types.Uint && l.intIs64() {
+				return "_uint64("
+			}
 			return "_uint32("
 		}
 	}
 	if _, ok := T.Underlying().(*types.Array); ok {
-		ret := fmt.Sprintf("_object(%d", haxeStdSizes.Sizeof(T))
+		ret := fmt.Sprintf("_object(%d", l.hc.sizes.Sizeof(T))
 		if hasParameters {
 			ret += ","
 		}
 		return ret
 	}
 	if _, ok := T.Underlying().(*types.Struct); ok {
-		ret := fmt.Sprintf("_object(%d", haxeStdSizes.Sizeof(T))
+		ret := fmt.Sprintf("_object(%d", l.hc.sizes.Sizeof(T))
 		if hasParameters {
 			ret += ","
 		}
@@ -683,7 +721,7 @@ func (l langType) TypeStart(nt *types.Named, err string) string {
 	switch nt.Underlying().(type) {
 	case *types.Struct:
 		str := nt.Underlying().(*types.Struct)
-		ret += "inline public function new(){ super new(" + strconv.Itoa(int(haxeStdSizes.Sizeof(nt.Obj().Type()))) + "); }\n"
+		ret += "inline public function new(){ super new(" + strconv.Itoa(int(l.hc.sizes.Sizeof(nt.Obj().Type()))) + "); }\n"
 		flds := []string{}
 		for f := 0; f < str.NumFields(); f++ {
 			fName := str.Field(f).Name()
@@ -698,8 +736,8 @@ func (l langType) TypeStart(nt *types.Named, err string) string {
 			for f := 0; f < str.NumFields(); f++ {
 				if fName == str.Field(f).Name() {
 					haxeTyp := l.LangType(str.Field(f).Type(), false, nt.String())
-					fOff := fieldOffset(str, f)
-					sfx := loadStoreSuffix(str.Field(f).Type(), true)
+					fOff := l.fieldOffset(str, f)
+					sfx := l.loadStoreSuffix(str.Field(f).Type(), true)
 					ret += fmt.Sprintf("public var _%s(get,set):%s;\n", fName, haxeTyp)
 					ret += fmt.Sprintf("function get__%s():%s { return get%s%d); }\n",
 						fName, haxeTyp, sfx, fOff)
@@ -710,7 +748,7 @@ func (l langType) TypeStart(nt *types.Named, err string) string {
 			}
 		}
 	case *types.Array:
-		ret += "inline public function new(){ super new(" + strconv.Itoa(int(haxeStdSizes.Sizeof(nt.Obj().Type()))) + "); }\n"
+		ret += "inline public function new(){ super new(" + strconv.Itoa(int(l.hc.sizes.Sizeof(nt.Obj().Type()))) + "); }\n"
 	default: // TODO not yet sure how to handle named types that are not structs
 		ret += "inline public function new(v:" + hxTyp + ") { this = v; }\n"
 	}
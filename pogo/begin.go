@@ -20,12 +20,15 @@ func (comp *Compilation) Recycle() { LanguageList[comp.TargetLang] = LanguageEnt
 
 // Compile provides the entry point for the pogo package,
 // returning a pogo.Compilation structure and error
-func Compile(mainPkg *ssa.Package, debug, trace bool, langName, testFSname string) (*Compilation, error) {
+func Compile(mainPkg *ssa.Package, initialPkgs []*ssa.Package, debug, trace, fullReflect bool, wordSize int64, langName, testFSname string) (*Compilation, error) {
 	comp := &Compilation{
-		mainPackage: mainPkg,
-		rootProgram: mainPkg.Prog,
-		DebugFlag:   debug,
-		TraceFlag:   trace,
+		mainPackage:     mainPkg,
+		initialPackages: initialPkgs,
+		rootProgram:     mainPkg.Prog,
+		DebugFlag:       debug,
+		TraceFlag:       trace,
+		FullReflectFlag: fullReflect,
+		WordSize:        wordSize,
 	}
 
 	k, e := FindTargetLang(langName)
@@ -50,6 +53,7 @@ func Compile(mainPkg *ssa.Package, debug, trace bool, langName, testFSname strin
 	comp.setupPosHash()
 	comp.loadSpecialConsts()
 	comp.emitFileStart()
+	comp.findExports()
 	comp.emitFunctions()
 	comp.emitGoClass(comp.mainPackage)
 	comp.emitTypeInfo()
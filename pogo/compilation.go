@@ -7,9 +7,10 @@ import (
 
 // Compilation contains global variables for an individual pogo run
 type Compilation struct {
-	rootProgram *ssa.Program // pointer to the root datastructure
-	mainPackage *ssa.Package // pointer to the "main" package
-	TargetLang  int          // TargetLang holds the language currently being targeted, offset into LanguageList.
+	rootProgram     *ssa.Program   // pointer to the root datastructure
+	mainPackage     *ssa.Package   // pointer to the "main" package
+	initialPackages []*ssa.Package // the packages named on the command line, searched for the ExportDirective
+	TargetLang      int            // TargetLang holds the language currently being targeted, offset into LanguageList.
 
 	hxPkgName, headerText string
 	LibListNoDCE          []string
@@ -19,8 +20,13 @@ type Compilation struct {
 	PosHashFileList    []PosHashFileStruct // PosHashFileList holds the list of input go files with their posHash information
 	LatestValidPosHash PosHash             // LatestValidPosHash holds the latest valid PosHash value seen, for use when an invalid one requires a "near" reference.
 
+	posMarks                   []posMark // the Go positions of the code in the buffer, for the PositionMapFile
+	bytesCounted, linesCounted int       // how much of the buffer has been counted by bufferLine()
+
 	fnMap, grMap map[*ssa.Function]bool // which functions are used and if the functions use goroutines/channels
 
+	exports []Export // the functions, methods and types marked with the ExportDirective
+
 	inlineMap map[string]string
 	keysSeen  map[string]int
 
@@ -30,8 +36,15 @@ type Compilation struct {
 	NextTypeID               int          // NextTypeID is used to give each type we come across its own ID - entry zero is invalid
 	catchReferencedTypesSeen map[string]bool
 
+	reflectTypes, interfaceTypes typeutil.Map // the types which need full run-time type information, or their method tables
+	reflectTypesFound            bool         // set once reflectTypes and interfaceTypes have been found
+
+	scalarAllocs map[*ssa.Alloc]bool // cache of the ScalarReplaceable() escape analysis results
+
 	// flags
-	DebugFlag              bool // DebugFlag is used to signal if we are emitting debug information
-	TraceFlag              bool // TraceFlag is used to signal if we are emitting trace information (big)
-	hadErrors, stopOnError bool // TODO make stopOnError soft and default true
+	DebugFlag              bool  // DebugFlag is used to signal if we are emitting debug information
+	TraceFlag              bool  // TraceFlag is used to signal if we are emitting trace information (big)
+	FullReflectFlag        bool  // FullReflectFlag is used to signal that every type needs full run-time type information
+	WordSize               int64 // WordSize is the size in bytes of int, uint and uintptr, as given to the type checker
+	hadErrors, stopOnError bool  // TODO make stopOnError soft and default true
 }
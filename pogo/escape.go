@@ -0,0 +1,150 @@
+// Copyright 2014 Elliott Stoneham and The TARDIS Go Authors
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package pogo
+
+import (
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// MaxScalarSlots is the largest number of fields or elements that a non-escaping allocation can have
+// and still be replaced by individual local variables in the target language.
+const MaxScalarSlots = 8
+
+// ScalarReplaceable returns true if the ssa.Alloc does not escape the function that makes it,
+// and is small enough to be held as a set of target language local variables, one per field or array element.
+//
+// The rules are deliberately simple: the allocation must be on the stack (ssa.Alloc.Heap is false),
+// it must be a struct or array whose fields or elements are not themselves aggregates,
+// and its address may only be used by:
+//   - a FieldAddr, or an IndexAddr with a constant index, whose result is only loaded or stored through;
+//   - a load of the whole value (*alloc);
+//   - a store of a whole value into it (*alloc = v).
+//
+// Any other use, for example passing the address to a function, taking a slice of it or a DebugRef,
+// means that the address could escape, so the normal Object/Pointer representation is used.
+func (comp *Compilation) ScalarReplaceable(a *ssa.Alloc) bool {
+	if comp.scalarAllocs == nil {
+		comp.scalarAllocs = make(map[*ssa.Alloc]bool)
+	}
+	ok, seen := comp.scalarAllocs[a]
+	if !seen {
+		ok = scalarReplaceable(a)
+		comp.scalarAllocs[a] = ok
+	}
+	return ok
+}
+
+// ScalarSlot returns the scalar-replaced ssa.Alloc addressed by v, and the field or element number within it,
+// if v is a FieldAddr or IndexAddr into a ScalarReplaceable allocation.
+func (comp *Compilation) ScalarSlot(v interface{}) (a *ssa.Alloc, slot int, ok bool) {
+	switch v.(type) {
+	case *ssa.FieldAddr:
+		a, ok = v.(*ssa.FieldAddr).X.(*ssa.Alloc)
+		if ok && comp.ScalarReplaceable(a) {
+			return a, v.(*ssa.FieldAddr).Field, true
+		}
+	case *ssa.IndexAddr:
+		a, ok = v.(*ssa.IndexAddr).X.(*ssa.Alloc)
+		if ok && comp.ScalarReplaceable(a) {
+			return a, int(v.(*ssa.IndexAddr).Index.(*ssa.Const).Int64()), true
+		}
+	}
+	return nil, 0, false
+}
+
+// ScalarSlotTypes returns the types of the individual fields or elements of a ScalarReplaceable allocation.
+func ScalarSlotTypes(a *ssa.Alloc) []types.Type {
+	ret := []types.Type{}
+	switch typ := a.Type().Underlying().(*types.Pointer).Elem().Underlying().(type) {
+	case *types.Struct:
+		for f := 0; f < typ.NumFields(); f++ {
+			ret = append(ret, typ.Field(f).Type())
+		}
+	case *types.Array:
+		for e := int64(0); e < typ.Len(); e++ {
+			ret = append(ret, typ.Elem())
+		}
+	}
+	return ret
+}
+
+func scalarReplaceable(a *ssa.Alloc) bool {
+	if a.Heap {
+		return false
+	}
+	slots := ScalarSlotTypes(a)
+	if len(slots) == 0 || len(slots) > MaxScalarSlots {
+		return false
+	}
+	for _, st := range slots {
+		if !isScalarSlotType(st) {
+			return false
+		}
+	}
+	for _, ref := range *a.Referrers() {
+		switch ref.(type) {
+		case *ssa.FieldAddr:
+			if !onlyLoadedOrStored(ref.(*ssa.FieldAddr)) {
+				return false
+			}
+		case *ssa.IndexAddr:
+			idx, isConst := ref.(*ssa.IndexAddr).Index.(*ssa.Const)
+			if !isConst {
+				return false
+			}
+			if i := idx.Int64(); i < 0 || i >= int64(len(slots)) {
+				return false // let the normal code report the range error
+			}
+			if !onlyLoadedOrStored(ref.(*ssa.IndexAddr)) {
+				return false
+			}
+		case *ssa.UnOp:
+			if ref.(*ssa.UnOp).Op != token.MUL {
+				return false
+			}
+		case *ssa.Store:
+			if ref.(*ssa.Store).Addr != ssa.Value(a) {
+				return false // the address itself is being stored somewhere
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// onlyLoadedOrStored returns true if the address is only used to load or store the value it points to.
+func onlyLoadedOrStored(addr ssa.Value) bool {
+	for _, ref := range *addr.Referrers() {
+		switch ref.(type) {
+		case *ssa.UnOp:
+			if ref.(*ssa.UnOp).Op != token.MUL {
+				return false
+			}
+		case *ssa.Store:
+			if ref.(*ssa.Store).Addr != addr || ref.(*ssa.Store).Val == addr {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// isScalarSlotType returns true for types that can be held directly in a target language variable,
+// and whose zero value is a simple constant.
+func isScalarSlotType(t types.Type) bool {
+	switch t.Underlying().(type) {
+	case *types.Basic:
+		return t.Underlying().(*types.Basic).Info()&types.IsUntyped == 0
+	case *types.Pointer, *types.Signature, *types.Interface:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,216 @@
+// Copyright 2014 Elliott Stoneham and The TARDIS Go Authors
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package pogo
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// ExportDirective is the comment that publishes a Go function, method or struct type to the target language,
+// for example:
+//
+//	//tardisgo:export Geometry.area
+//	func Area(w, h float64) float64 { ... }
+//
+// The optional name gives the name in the target language, the Go name is used if it is absent.
+// For a function, the name may give the class to hold it, as above, otherwise the class is named after the Go package,
+// so that the functions of package main are in the class GoMain.
+// Only the packages named on the command line are searched for the directive.
+const ExportDirective = "//tardisgo:export"
+
+// An Export is a Go function, method or named struct type marked with the ExportDirective.
+type Export struct {
+	Name     string        // the name given in the directive, or the Go name
+	Func     *ssa.Function // the function, or the method of the pointer to the type; nil for a type
+	Type     *types.Named  // the type, or the receiver type of a method; nil for a function
+	Doc      []string      // the lines of the Go doc comment, without the directive
+	Position string        // the position of the directive in the Go source
+}
+
+// Exports returns the functions, methods and types marked with the ExportDirective, in source order.
+func (comp *Compilation) Exports() []Export {
+	return comp.exports
+}
+
+// findExports looks for the ExportDirective in the source of the packages named on the command line,
+// re-reading only those files that contain it, as the comments are not kept when the program is loaded.
+func (comp *Compilation) findExports() {
+	fset := token.NewFileSet()
+	pkgs := append([]*ssa.Package{}, comp.initialPackages...)
+	sort.Sort(PackageSorter(pkgs))
+	for _, pkg := range pkgs {
+		fileNames := make(map[string]bool)
+		for _, mem := range pkg.Members {
+			if name := comp.rootProgram.Fset.Position(mem.Pos()).Filename; name != "" {
+				fileNames[name] = true
+			}
+			if t, ok := mem.(*ssa.Type); ok { // methods may be declared in other files
+				mset := comp.rootProgram.MethodSets.MethodSet(types.NewPointer(t.Type()))
+				for i := 0; i < mset.Len(); i++ {
+					if name := comp.rootProgram.Fset.Position(mset.At(i).Obj().Pos()).Filename; name != "" {
+						fileNames[name] = true
+					}
+				}
+			}
+		}
+		sorted := make([]string, 0, len(fileNames))
+		for name := range fileNames {
+			sorted = append(sorted, name)
+		}
+		sort.Strings(sorted)
+		for _, name := range sorted {
+			src, err := ioutil.ReadFile(name)
+			if err != nil || !bytes.Contains(src, []byte(ExportDirective)) {
+				continue
+			}
+			file, err := parser.ParseFile(fset, name, src, parser.ParseComments)
+			if err != nil {
+				comp.LogError(name, "pogo", err)
+				continue
+			}
+			comp.fileExports(fset, pkg, file)
+		}
+	}
+	exportedTypes := make(map[*types.Named]bool)
+	for _, e := range comp.exports {
+		if e.Func == nil {
+			exportedTypes[e.Type] = true
+		}
+	}
+	for _, e := range comp.exports {
+		if e.Func != nil && e.Type != nil && !exportedTypes[e.Type] {
+			comp.LogError(e.Position, "pogo",
+				fmt.Errorf("method %s is exported, but its type %s is not", e.Func.Name(), e.Type.Obj().Name()))
+		}
+	}
+}
+
+// fileExports adds the exports declared in a file of a package.
+func (comp *Compilation) fileExports(fset *token.FileSet, pkg *ssa.Package, file *ast.File) {
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			name, doc, ok := exportDirective(d.Doc)
+			if !ok {
+				continue
+			}
+			e := Export{Name: name, Doc: doc, Position: fset.Position(d.Doc.Pos()).String()}
+			if e.Name == "" {
+				e.Name = d.Name.Name
+			}
+			if d.Recv == nil {
+				e.Func = pkg.Func(d.Name.Name)
+			} else {
+				e.Type = comp.exportedNamed(pkg, receiverName(d.Recv), e.Position)
+				if e.Type == nil {
+					continue
+				}
+				// the method of the pointer type, which may be a wrapper, so that it can always be called using a pointer
+				sel := comp.rootProgram.MethodSets.MethodSet(types.NewPointer(e.Type)).Lookup(pkg.Pkg, d.Name.Name)
+				if sel != nil {
+					e.Func = comp.rootProgram.MethodValue(sel)
+				}
+			}
+			if e.Func == nil {
+				comp.LogError(e.Position, "pogo", fmt.Errorf("cannot find the exported function %s", d.Name.Name))
+				continue
+			}
+			comp.exports = append(comp.exports, e)
+		case *ast.GenDecl:
+			if d.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range d.Specs {
+				ts := spec.(*ast.TypeSpec)
+				cg := ts.Doc
+				if cg == nil && len(d.Specs) == 1 {
+					cg = d.Doc
+				}
+				name, doc, ok := exportDirective(cg)
+				if !ok {
+					continue
+				}
+				e := Export{Name: name, Doc: doc, Position: fset.Position(cg.Pos()).String()}
+				if e.Name == "" {
+					e.Name = ts.Name.Name
+				}
+				e.Type = comp.exportedNamed(pkg, ts.Name.Name, e.Position)
+				if e.Type == nil {
+					continue
+				}
+				if _, isStruct := e.Type.Underlying().(*types.Struct); !isStruct {
+					comp.LogError(e.Position, "pogo", fmt.Errorf("only struct types can be exported, not %s", ts.Name.Name))
+					continue
+				}
+				comp.exports = append(comp.exports, e)
+			}
+		}
+	}
+}
+
+// exportedNamed returns the named type of a package, logging an error if it cannot be found.
+func (comp *Compilation) exportedNamed(pkg *ssa.Package, name, position string) *types.Named {
+	if t := pkg.Type(name); t != nil {
+		if named, ok := t.Type().(*types.Named); ok {
+			return named
+		}
+	}
+	comp.LogError(position, "pogo", fmt.Errorf("cannot find the exported type %s", name))
+	return nil
+}
+
+// receiverName returns the name of the type of a method receiver, without any pointer.
+func receiverName(recv *ast.FieldList) string {
+	if recv == nil || len(recv.List) == 0 {
+		return ""
+	}
+	typ := recv.List[0].Type
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+	if id, ok := typ.(*ast.Ident); ok {
+		return id.Name
+	}
+	return ""
+}
+
+// exportDirective looks for the ExportDirective in a doc comment,
+// returning the name it gives and the other lines of the comment.
+func exportDirective(cg *ast.CommentGroup) (name string, doc []string, found bool) {
+	if cg == nil {
+		return "", nil, false
+	}
+	for _, c := range cg.List {
+		if strings.HasPrefix(c.Text, ExportDirective) {
+			rest := c.Text[len(ExportDirective):]
+			if rest == "" || rest[0] == ' ' || rest[0] == '\t' {
+				name = strings.TrimSpace(rest)
+				found = true
+			}
+		}
+	}
+	if !found {
+		return "", nil, false
+	}
+	for _, line := range strings.Split(cg.Text(), "\n") {
+		if !strings.HasPrefix(line, ExportDirective[2:]) { // cg.Text() removes the comment markers
+			doc = append(doc, line)
+		}
+	}
+	for len(doc) > 0 && strings.TrimSpace(doc[len(doc)-1]) == "" {
+		doc = doc[:len(doc)-1]
+	}
+	return name, doc, true
+}
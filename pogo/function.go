@@ -45,7 +45,13 @@ func (comp *Compilation) emitFunctions() {
 			//fmt.Println("DEBUG exip nil for package: ",ex)
 		}
 	}
-	comp.fnMap, comp.grMap = tgossa.VisitedFunctions(comp.rootProgram, dceList, comp.IsOverloaded)
+	exportedFns := []*ssa.Function{} // exported functions may only be called from the target language
+	for _, e := range comp.exports {
+		if e.Func != nil {
+			exportedFns = append(exportedFns, e.Func)
+		}
+	}
+	comp.fnMap, comp.grMap = tgossa.VisitedFunctions(comp.rootProgram, dceList, exportedFns, comp.IsOverloaded)
 
 	/* NOTE non-working code below attempts to improve Dead Code Elimination,
 	//	but is unreliable so far, in part because the target lang runtime may use "unsafe" pointers
@@ -505,6 +511,11 @@ func (comp *Compilation) emitFuncStart(fn *ssa.Function, blks []*ssa.BasicBlock,
 	posStr := comp.CodePosition(fn.Pos())
 	pName, mName := comp.GetFnNameParts(fn)
 	isPublic := unicode.IsUpper(rune(mName[0])) // TODO check rules for non-ASCII 1st characters and fix
+	if fn.Pos().IsValid() {
+		comp.markPosition(comp.posHashNoUpdate(fn.Pos()))
+	} else {
+		comp.markPosition(NoPosHash)
+	}
 	fmt.Fprintln(&LanguageList[l].buffer,
 		LanguageList[l].FuncStart(pName, mName, fn, blks, posStr, isPublic, trackPhi, comp.grMap[fn] || mustSplitCode, canOptMap, reconstruct))
 }
@@ -512,6 +523,7 @@ func (comp *Compilation) emitFuncStart(fn *ssa.Function, blks []*ssa.BasicBlock,
 // Emit the end of a function.
 func (comp *Compilation) emitFuncEnd(fn *ssa.Function) {
 	l := comp.TargetLang
+	comp.markPosition(NoPosHash) // the end of the function's code is not from a Go line
 	fmt.Fprintln(&LanguageList[l].buffer, LanguageList[l].FuncEnd(fn))
 }
 
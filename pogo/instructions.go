@@ -29,8 +29,9 @@ func (comp *Compilation) emitInstruction(instruction interface{}, operands []*ss
 	_, isDebug := instruction.(*ssa.DebugRef)
 	if !isDebug { // Don't update the code position for debug refs
 		prev := comp.LatestValidPosHash
-		comp.MakePosHash(instruction.(ssa.Instruction).Pos()) // this so that we log the nearby position info
-		if prev != comp.LatestValidPosHash {                  // new info, so put out an update
+		ph := comp.MakePosHash(instruction.(ssa.Instruction).Pos()) // this so that we log the nearby position info
+		comp.markPosition(ph)                                       // and the position of the code, for the PositionMapFile
+		if prev != comp.LatestValidPosHash {                        // new info, so put out an update
 			if comp.DebugFlag { // but only in Debug mode
 				fmt.Fprintln(&LanguageList[l].buffer,
 					LanguageList[l].SetPosHash())
@@ -377,6 +378,9 @@ func (comp *Compilation) emitInstruction(instruction interface{}, operands []*ss
 					doRangeCheck = false
 				}
 			}
+			if _, _, isScalar := comp.ScalarSlot(instruction); isScalar {
+				doRangeCheck = false // constant index, already checked by ScalarReplaceable()
+			}
 			if doRangeCheck {
 				fmt.Fprintln(&LanguageList[l].buffer,
 					LanguageList[l].RangeCheck(instruction.(*ssa.IndexAddr).X, instruction.(*ssa.IndexAddr).Index, aLen, errorInfo)+
@@ -114,6 +114,7 @@ type LanguageEntry struct {
 type FileOutput struct {
 	filename string
 	data     []byte
+	marks    []posMark // the Go positions of the lines of data
 }
 
 // LanguageList holds the languages that can be targeted, and compilation run data
@@ -30,7 +30,7 @@ func (comp *Compilation) WriteAsClass(name, code string) {
 	}
 	var data = make([]byte, LanguageList[l].buffer.Len())
 	copy(data, LanguageList[l].buffer.Bytes())
-	LanguageList[l].files = append(LanguageList[l].files, FileOutput{name, data})
+	LanguageList[l].files = append(LanguageList[l].files, FileOutput{name, data, comp.takePosMarks()})
 	LanguageList[l].buffer.Reset()
 	comp.emitFileStart()
 }
@@ -64,6 +64,9 @@ func (comp *Compilation) writeFiles() {
 			}
 		}
 	}
+	if err == nil {
+		err = comp.writePositionMap()
+	}
 	if err != nil {
 		comp.LogError("Unable to write output file", "pogo", err)
 	}
@@ -168,7 +168,8 @@ func (comp *Compilation) peepholeFindOpt(instrs []ssa.Instruction) (optName, reg
 				if len(instrs) == 2 {
 					// we are at the first two in the load_object(UnOp*)+Index/Field sequence
 					if instrs[0].(*ssa.UnOp).Name() == indexOrFieldXName(instrs[1]) &&
-						indexOrFieldRefCount(instrs[1]) > 0 {
+						indexOrFieldRefCount(instrs[1]) > 0 &&
+						comp.canLoadObjectSlot(instrs[0].(*ssa.UnOp).X, instrs[1]) {
 						optName = "loadObject"
 						regName = comp.RegisterName(instrs[1].(ssa.Value))
 						return // success
@@ -233,3 +234,17 @@ func indexOrFieldRefCount(i ssa.Instruction) int {
 		return 0
 	}
 }
+
+// canLoadObjectSlot returns false if a scalar-replaced allocation is indexed by a variable,
+// as then there is no single local variable to read.
+func (comp *Compilation) canLoadObjectSlot(x ssa.Value, i ssa.Instruction) bool {
+	a, isAlloc := x.(*ssa.Alloc)
+	if !isAlloc || !comp.ScalarReplaceable(a) {
+		return true
+	}
+	if idx, isIndex := i.(*ssa.Index); isIndex {
+		_, isConst := idx.Index.(*ssa.Const)
+		return isConst
+	}
+	return true
+}
@@ -0,0 +1,169 @@
+// Copyright 2014 Elliott Stoneham and The TARDIS Go Authors
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package pogo
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/token"
+	"os"
+)
+
+// PositionMapFile is the name of the file, written to the target directory, that maps the lines of the generated code
+// to the Go source lines they were compiled from, for use by tools such as source map generators.
+const PositionMapFile = "tardisgo.posmap.json"
+
+// A PositionMap gives the Go source positions of the lines of each generated file.
+type PositionMap struct {
+	Sources []string             `json:"sources"` // the Go source files
+	Files   map[string][]LineMap `json:"files"`   // by generated file name, in line order
+}
+
+// A LineMap records that lines First to Last of a generated file, counting from 1, come from a Go source line.
+type LineMap struct {
+	First, Last int
+	Source      int // the index of the Go file in PositionMap.Sources
+	Line        int
+}
+
+// MarshalJSON writes a LineMap as the compact array [First, Last, Source, Line].
+func (lm LineMap) MarshalJSON() ([]byte, error) {
+	return json.Marshal([4]int{lm.First, lm.Last, lm.Source, lm.Line})
+}
+
+// UnmarshalJSON reads a LineMap written by MarshalJSON.
+func (lm *LineMap) UnmarshalJSON(b []byte) error {
+	var a [4]int
+	if err := json.Unmarshal(b, &a); err != nil {
+		return err
+	}
+	lm.First, lm.Last, lm.Source, lm.Line = a[0], a[1], a[2], a[3]
+	return nil
+}
+
+// Lookup returns the Go file and line of a line of a generated file, or ok false if it is not known.
+func (pm *PositionMap) Lookup(file string, line int) (source string, goLine int, ok bool) {
+	lms := pm.Files[file]
+	lo, hi := 0, len(lms)
+	for lo < hi { // find the first range that ends at or after the line
+		mid := (lo + hi) / 2
+		if lms[mid].Last < line {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	if lo == len(lms) || lms[lo].First > line || lms[lo].Source < 0 || lms[lo].Source >= len(pm.Sources) {
+		return "", 0, false
+	}
+	return pm.Sources[lms[lo].Source], lms[lo].Line, true
+}
+
+// posMark records that the code from a line of the target language buffer onwards comes from the code at a PosHash.
+type posMark struct {
+	line int
+	ph   PosHash // NoPosHash if the code does not come from a known Go line
+}
+
+// bufferLine returns the line of the target language buffer that the next code will be written to, counting from 1.
+func (comp *Compilation) bufferLine() int {
+	buf := LanguageList[comp.TargetLang].buffer.Bytes()
+	if comp.bytesCounted > len(buf) { // the buffer has been reset
+		comp.bytesCounted, comp.linesCounted = 0, 0
+	}
+	comp.linesCounted += bytes.Count(buf[comp.bytesCounted:], []byte{'\n'})
+	comp.bytesCounted = len(buf)
+	return comp.linesCounted + 1
+}
+
+// markPosition records that the code about to be written to the buffer comes from the Go code at the PosHash.
+func (comp *Compilation) markPosition(ph PosHash) {
+	if ph < NoPosHash {
+		return // code without a position continues the range of the code before it
+	}
+	line := comp.bufferLine()
+	if n := len(comp.posMarks); n > 0 {
+		if comp.posMarks[n-1].ph == ph {
+			return
+		}
+		if comp.posMarks[n-1].line == line { // nothing was written for the previous mark
+			comp.posMarks = comp.posMarks[:n-1]
+			comp.markPosition(ph)
+			return
+		}
+	} else if ph == NoPosHash {
+		return
+	}
+	comp.posMarks = append(comp.posMarks, posMark{line, ph})
+}
+
+// takePosMarks returns the marks for the file being written from the buffer, and starts those of the next file.
+func (comp *Compilation) takePosMarks() []posMark {
+	marks := comp.posMarks
+	comp.posMarks = nil
+	comp.bytesCounted, comp.linesCounted = 0, 0
+	return marks
+}
+
+// posHashFile returns the index in PosHashFileList of the file holding a PosHash, and the line in it.
+func (comp *Compilation) posHashFile(ph PosHash) (int, int, bool) {
+	for f := range comp.PosHashFileList {
+		base := comp.PosHashFileList[f].BasePosHash
+		if int(ph) > base && int(ph) <= base+comp.PosHashFileList[f].LineCount {
+			return f, int(ph) - base, true
+		}
+	}
+	return 0, 0, false
+}
+
+// writePositionMap writes the PositionMapFile for the generated files.
+func (comp *Compilation) writePositionMap() error {
+	l := comp.TargetLang
+	pm := PositionMap{Sources: []string{}, Files: make(map[string][]LineMap)}
+	sourceIdx := make(map[int]int) // PosHashFileList index to Sources index
+	for _, fo := range LanguageList[l].files {
+		lines := bytes.Count(fo.data, []byte{'\n'})
+		if len(fo.data) > 0 && fo.data[len(fo.data)-1] != '\n' {
+			lines++
+		}
+		var lms []LineMap
+		for m, mark := range fo.marks {
+			f, line, ok := comp.posHashFile(mark.ph)
+			if !ok {
+				continue
+			}
+			last := lines
+			if m+1 < len(fo.marks) {
+				last = fo.marks[m+1].line - 1
+			}
+			if last < mark.line {
+				continue
+			}
+			idx, seen := sourceIdx[f]
+			if !seen {
+				idx = len(pm.Sources)
+				sourceIdx[f] = idx
+				pm.Sources = append(pm.Sources, comp.PosHashFileList[f].FileName)
+			}
+			lms = append(lms, LineMap{First: mark.line, Last: last, Source: idx, Line: line})
+		}
+		if len(lms) > 0 {
+			pm.Files[fo.filename+LanguageList[l].FileTypeSuffix()] = lms
+		}
+	}
+	data, err := json.Marshal(pm)
+	if err != nil {
+		return err
+	}
+	return writeIfChanged(LanguageList[l].TgtDir+string(os.PathSeparator)+PositionMapFile, data)
+}
+
+// posHashNoUpdate returns the PosHash of a position without making it the LatestValidPosHash.
+func (comp *Compilation) posHashNoUpdate(pos token.Pos) PosHash {
+	latest := comp.LatestValidPosHash
+	ph := comp.MakePosHash(pos)
+	comp.LatestValidPosHash = latest
+	return ph
+}
@@ -0,0 +1,97 @@
+// Copyright 2014 Elliott Stoneham and The TARDIS Go Authors
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package pogo
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	var pm PositionMap
+	err := json.Unmarshal([]byte(`{"sources":["a.go","b.go"],"files":{"Go.hx":[[3,5,0,10],[6,6,1,20],[9,12,0,30],[13,13,2,40]]}}`), &pm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (LineMap{First: 3, Last: 5, Source: 0, Line: 10}); pm.Files["Go.hx"][0] != want {
+		t.Errorf("unmarshaled %v, want %v", pm.Files["Go.hx"][0], want)
+	}
+	if b, err := json.Marshal(pm.Files["Go.hx"][1]); err != nil || string(b) != "[6,6,1,20]" {
+		t.Errorf("marshaled %s, %v, want [6,6,1,20]", b, err)
+	}
+	for _, tt := range []struct {
+		file   string
+		line   int
+		source string
+		goLine int
+	}{
+		{"Go.hx", 0, "", 0},
+		{"Go.hx", 2, "", 0}, // before the first range
+		{"Go.hx", 3, "a.go", 10},
+		{"Go.hx", 4, "a.go", 10},
+		{"Go.hx", 5, "a.go", 10},
+		{"Go.hx", 6, "b.go", 20}, // a range of one line
+		{"Go.hx", 7, "", 0},      // between ranges
+		{"Go.hx", 8, "", 0},
+		{"Go.hx", 9, "a.go", 30},
+		{"Go.hx", 12, "a.go", 30},
+		{"Go.hx", 13, "", 0}, // an invalid source index
+		{"Go.hx", 14, "", 0}, // after the last range
+		{"Other.hx", 3, "", 0},
+	} {
+		source, goLine, ok := pm.Lookup(tt.file, tt.line)
+		if source != tt.source || goLine != tt.goLine || ok != (tt.source != "") {
+			t.Errorf("Lookup(%q, %d) = %q, %d, %v, want %q, %d", tt.file, tt.line, source, goLine, ok, tt.source, tt.goLine)
+		}
+	}
+}
+
+// TestMarkPosition writes code to the buffer of a target language without a Language, marking its positions as it goes.
+func TestMarkPosition(t *testing.T) {
+	LanguageList = append(LanguageList, LanguageEntry{})
+	l := len(LanguageList) - 1
+	defer func() { LanguageList = LanguageList[:l] }()
+	comp := &Compilation{TargetLang: l}
+	buf := &LanguageList[l].buffer
+
+	for i, step := range []struct {
+		code string // written before the mark
+		ph   PosHash
+		want []posMark
+	}{
+		{"", NoPosHash, nil}, // nothing to end at the start
+		{"", 5, []posMark{{1, 5}}},
+		{"a\nb\n", 5, []posMark{{1, 5}}}, // the same line continues
+		{"", 7, []posMark{{1, 5}, {3, 7}}},
+		{"", 8, []posMark{{1, 5}, {3, 8}}}, // no code was written for 7
+		{"", 5, []posMark{{1, 5}}},         // so 5 continues, as no code was written for 8
+		{"c\n", -1, []posMark{{1, 5}}},     // no position
+		{"", NoPosHash, []posMark{{1, 5}, {4, NoPosHash}}},
+		{"d", NoPosHash, []posMark{{1, 5}, {4, NoPosHash}}},
+		{"\n", 9, []posMark{{1, 5}, {4, NoPosHash}, {5, 9}}},
+		{"e\n", 9, []posMark{{1, 5}, {4, NoPosHash}, {5, 9}}},
+	} {
+		buf.WriteString(step.code)
+		comp.markPosition(step.ph)
+		if !reflect.DeepEqual(comp.posMarks, step.want) {
+			t.Fatalf("step %d: marks %v, want %v", i, comp.posMarks, step.want)
+		}
+	}
+
+	if marks := comp.takePosMarks(); len(marks) != 3 || comp.posMarks != nil {
+		t.Errorf("takePosMarks returned %v, leaving %v", marks, comp.posMarks)
+	}
+	buf.Reset()
+	buf.WriteString("f\n")
+	comp.markPosition(3)
+	if want := []posMark{{2, 3}}; !reflect.DeepEqual(comp.posMarks, want) {
+		t.Errorf("after takePosMarks, marks %v, want %v", comp.posMarks, want)
+	}
+	buf.Reset() // without takePosMarks, as when the buffer is discarded
+	if line := comp.bufferLine(); line != 1 {
+		t.Errorf("after a reset of the buffer, bufferLine() = %d, want 1", line)
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright 2014 Elliott Stoneham and The TARDIS Go Authors
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package pogo
+
+import (
+	"go/types"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// The run-time type information for each type is large, as it includes the names, fields and method tables used by reflect.
+// So full information is only given for the types that reflect could reach, and the method tables used for
+// method-by-name lookup only for the types that could be held in an interface; the rest are given a minimal entry.
+//
+// A concrete type can only get into an interface through an ssa.MakeInterface in a function that is used.
+// If the program does not use package reflect, that is all that is required.
+// Otherwise reflect can reach every type that makes up one of those types,
+// by following elements, fields, parameters, results, methods and pointers to them.
+// The types asserted to, including those of type switches, are also reached, as reflect may make a value of one of them
+// (for example with reflect.MakeSlice(reflect.SliceOf(t))) and must then find the compiled type to give it.
+
+// TypeHasReflectInfo returns true if full run-time type information is required for t, because reflect could reach it.
+func (comp *Compilation) TypeHasReflectInfo(t types.Type) bool {
+	comp.findReflectTypes()
+	if comp.FullReflectFlag {
+		return true
+	}
+	return comp.reflectTypes.At(t) != nil
+}
+
+// TypeHasMethodInfo returns true if the method table for t is required, because t could be held in an interface
+// and so its methods could be looked up by name at run time.
+func (comp *Compilation) TypeHasMethodInfo(t types.Type) bool {
+	comp.findReflectTypes()
+	if comp.FullReflectFlag {
+		return true
+	}
+	return comp.interfaceTypes.At(t) != nil || comp.reflectTypes.At(t) != nil
+}
+
+func (comp *Compilation) findReflectTypes() {
+	if comp.reflectTypesFound {
+		return
+	}
+	comp.reflectTypesFound = true
+	if len(comp.LibListNoDCE) > 0 {
+		comp.FullReflectFlag = true // the library code may reflect on anything
+	}
+
+	usesReflect := false
+	var roots []types.Type
+	for _, fn := range comp.fnMapSorted() {
+		if fn.Pkg != nil && fn.Pkg.Pkg.Path() == "reflect" {
+			usesReflect = true
+		}
+		for _, b := range fn.Blocks {
+			for _, instr := range b.Instrs {
+				switch instr := instr.(type) {
+				case *ssa.MakeInterface:
+					if comp.interfaceTypes.At(instr.X.Type()) == nil {
+						comp.interfaceTypes.Set(instr.X.Type(), true)
+						roots = append(roots, instr.X.Type())
+					}
+				case *ssa.TypeAssert: // type switches are made of these too
+					roots = append(roots, instr.AssertedType)
+				}
+			}
+		}
+	}
+	if usesReflect {
+		for _, t := range roots {
+			comp.addReflectType(t)
+		}
+	}
+}
+
+// addReflectType marks t, and all the types that reflect could reach from it, as needing full type information.
+func (comp *Compilation) addReflectType(t types.Type) {
+	if t == nil || comp.reflectTypes.At(t) != nil {
+		return
+	}
+	comp.reflectTypes.Set(t, true)
+
+	// reflect gives the pointer to a type, if it exists, and the methods of both
+	if pt := types.NewPointer(t); comp.TypesEncountered.At(pt) != nil {
+		comp.addReflectType(pt)
+	}
+	mset := comp.MethodSetFor(t)
+	for m := 0; m < mset.Len(); m++ {
+		comp.addReflectType(mset.At(m).Obj().Type())
+	}
+
+	switch tt := t.(type) {
+	case *types.Named:
+		comp.addReflectType(tt.Underlying())
+	case *types.Pointer:
+		comp.addReflectType(tt.Elem())
+	case *types.Array:
+		comp.addReflectType(tt.Elem())
+		if st := types.NewSlice(tt.Elem()); comp.TypesEncountered.At(st) != nil {
+			comp.addReflectType(st) // the slice type of an array is given to reflect
+		}
+	case *types.Slice:
+		comp.addReflectType(tt.Elem())
+	case *types.Chan:
+		comp.addReflectType(tt.Elem())
+	case *types.Map:
+		comp.addReflectType(tt.Key())
+		comp.addReflectType(tt.Elem())
+	case *types.Struct:
+		for f := 0; f < tt.NumFields(); f++ {
+			comp.addReflectType(tt.Field(f).Type())
+		}
+	case *types.Signature:
+		for i := 0; i < tt.Params().Len(); i++ {
+			comp.addReflectType(tt.Params().At(i).Type())
+		}
+		for o := 0; o < tt.Results().Len(); o++ {
+			comp.addReflectType(tt.Results().At(o).Type())
+		}
+	case *types.Interface:
+		for m := 0; m < tt.NumMethods(); m++ {
+			comp.addReflectType(tt.Method(m).Type())
+		}
+	}
+}
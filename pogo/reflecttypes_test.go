@@ -0,0 +1,110 @@
+// Copyright 2014 Elliott Stoneham and The TARDIS Go Authors
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package pogo
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+)
+
+const reflectSrc = `package p
+
+type Pt struct{ X int }
+type Holder struct {
+	P *Pt
+	F field
+}
+type field struct{ S []string }
+type Sig struct{}
+type argT struct{}
+type resT struct{}
+
+func (Sig) M(a argT) resT { return resT{} }
+
+type never struct{ B bool }
+
+func f(i interface{}) {
+	var h interface{} = Holder{}
+	var s interface{} = Sig{}
+	_ = i.([]int)
+	switch i.(type) {
+	case map[string]float64:
+	}
+	_, _ = h, s
+	_ = never{}
+}
+`
+
+// reflectComp returns a Compilation of reflectSrc as the package at path, where all the functions are used.
+// As the package reflect is used if any function is in it, the path "reflect" makes reflect used.
+func reflectComp(t *testing.T, path string, fullReflect bool) (*Compilation, *types.Package) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "p.go", reflectSrc, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, _, err := ssautil.BuildPackage(&types.Config{}, fset, types.NewPackage(path, "p"), []*ast.File{f}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	comp := &Compilation{rootProgram: pkg.Prog, fnMap: make(map[*ssa.Function]bool)}
+	comp.FullReflectFlag = fullReflect
+	for fn := range ssautil.AllFunctions(pkg.Prog) {
+		comp.fnMap[fn] = true
+	}
+	return comp, pkg.Pkg
+}
+
+func TestReflectTypes(t *testing.T) {
+	named := func(pkg *types.Package, name string) types.Type {
+		return pkg.Scope().Lookup(name).Type()
+	}
+	for _, tt := range []struct {
+		path        string
+		fullReflect bool
+		typ         func(*types.Package) types.Type
+		reflect     bool
+		methods     bool
+	}{
+		// without reflect, only the types put in interfaces need their method tables
+		{"main", false, func(p *types.Package) types.Type { return named(p, "Holder") }, false, true},
+		{"main", false, func(p *types.Package) types.Type { return named(p, "Sig") }, false, true},
+		{"main", false, func(p *types.Package) types.Type { return named(p, "Pt") }, false, false},
+		{"main", false, func(p *types.Package) types.Type { return types.NewSlice(types.Typ[types.Int]) }, false, false},
+		{"main", true, func(p *types.Package) types.Type { return named(p, "never") }, true, true},
+
+		// with reflect, the types reached from those in interfaces or asserted to
+		{"reflect", false, func(p *types.Package) types.Type { return named(p, "Holder") }, true, true},
+		{"reflect", false, func(p *types.Package) types.Type { return types.NewPointer(named(p, "Pt")) }, true, true},
+		{"reflect", false, func(p *types.Package) types.Type { return named(p, "Pt") }, true, true},
+		{"reflect", false, func(p *types.Package) types.Type { return named(p, "field") }, true, true},
+		{"reflect", false, func(p *types.Package) types.Type { return types.NewSlice(types.Typ[types.String]) }, true, true},
+		{"reflect", false, func(p *types.Package) types.Type { return named(p, "argT") }, true, true},
+		{"reflect", false, func(p *types.Package) types.Type { return named(p, "resT") }, true, true},
+		{"reflect", false, func(p *types.Package) types.Type { return types.NewSlice(types.Typ[types.Int]) }, true, true},
+		{"reflect", false, func(p *types.Package) types.Type {
+			return types.NewMap(types.Typ[types.String], types.Typ[types.Float64])
+		}, true, true},
+		{"reflect", false, func(p *types.Package) types.Type { return types.Typ[types.Float64] }, true, true},
+		{"reflect", false, func(p *types.Package) types.Type { return named(p, "never") }, false, false},
+		{"reflect", false, func(p *types.Package) types.Type { return types.Typ[types.Bool] }, false, false},
+		{"reflect", true, func(p *types.Package) types.Type { return named(p, "never") }, true, true},
+	} {
+		comp, pkg := reflectComp(t, tt.path, tt.fullReflect)
+		typ := tt.typ(pkg)
+		if got := comp.TypeHasReflectInfo(typ); got != tt.reflect {
+			t.Errorf("%s, full reflect %v: TypeHasReflectInfo(%s) = %v, want %v", tt.path, tt.fullReflect, typ, got, tt.reflect)
+		}
+		if got := comp.TypeHasMethodInfo(typ); got != tt.methods {
+			t.Errorf("%s, full reflect %v: TypeHasMethodInfo(%s) = %v, want %v", tt.path, tt.fullReflect, typ, got, tt.methods)
+		}
+	}
+}
@@ -72,6 +72,9 @@ var debugFlag = flag.Bool("debug", false, "Instrument the code to enable debuggi
 var traceFlag = flag.Bool("trace", false, "Output trace information for every block visited (warning: huge output)")
 var buidTags = flag.String("tags", "", "build tags separated by spaces")
 var tgoroot = flag.String("tgoroot", "", "set goroot to the given value")
+var fullReflectFlag = flag.Bool("fullreflect", false, "Emit full reflect type information for every type, rather than only for those types that reflect could reach (warning: increased code size)")
+var intSizeFlag = flag.Int("intsize", 32, "the size in bits of the Go int and uint types: 32 or 64 (64 is emulated on js and slower on other non-native targets), uintptr values stay 32 bits")
+var hxTypesFlag = flag.String("hxtypes", "", "check the targets of hx pseudo-function calls against this Haxe type dump, as written by haxe -xml")
 
 //var modeFlag = ssa.BuilderModeFlag(flag.CommandLine, "build", 0)
 var modeFlag = ssa.BuilderMode(0)
@@ -167,11 +170,19 @@ func doTestable(args []string) error {
 		wordSize = 4                 // TARDIS Go addition to force default int size to 32 bits
 		conf.Build.GOOS = "nacl"     // TARDIS Go addition - simplest OS-specific code to emulate?
 		conf.Build.GOARCH = langName // TARDIS Go addition
+		// TARDIS Go addition to optionally make int & uint 64 bits, uintptr values stay 32 bits but take a word
+		switch *intSizeFlag {
+		case 32:
+		case 64:
+			wordSize = 8
+		default:
+			return fmt.Errorf("-intsize must be 32 or 64, not %d", *intSizeFlag)
+		}
 	}
 
 	conf.Build.BuildTags = strings.Split(*buidTags, " ")
 
-	conf.TypeChecker.Sizes = &types.StdSizes{ // must equal haxe.haxeStdSizes when (!*runFlag)
+	conf.TypeChecker.Sizes = &types.StdSizes{ // must equal the sizes the haxe package derives from the word size when (!*runFlag)
 		MaxAlign: 8,
 		WordSize: wordSize,
 	}
@@ -331,7 +342,15 @@ func doTestable(args []string) error {
 	if *runFlag { // Run the golang.org/x/tools/go/ssa/interp interpreter.
 		interp.Interpret(main, interpMode, conf.TypeChecker.Sizes, main.Pkg.Path(), args)
 	} else {
-		comp, err := pogo.Compile(main, *debugFlag, *traceFlag, langName, testFSname) // TARDIS Go entry point, returns an error
+		// TARDIS Go addition, the Haxe type dump to check hx pseudo-function calls against
+		haxe.HxTypesFile = *hxTypesFlag
+		initial := []*ssa.Package{} // TARDIS Go addition, the packages to search for pogo.ExportDirective
+		for _, info := range iprog.InitialPackages() {
+			if pkg := prog.Package(info.Pkg); pkg != nil {
+				initial = append(initial, pkg)
+			}
+		}
+		comp, err := pogo.Compile(main, initial, *debugFlag, *traceFlag, *fullReflectFlag, wordSize, langName, testFSname) // TARDIS Go entry point, returns an error
 		if err != nil {
 			return err
 		}
@@ -3,10 +3,30 @@ package main
 import (
 	"os"
 	"os/exec"
+	"strings"
+	"syscall"
 	"testing"
 )
 
 func TestCore(t *testing.T) {
+	testCore(t)
+}
+
+// TestCore64 runs the core tests again with 64-bit int and uint types.
+func TestCore64(t *testing.T) {
+	*intSizeFlag = 64
+	defer func() { *intSizeFlag = 32 }()
+	testCore(t)
+}
+
+// TestCoreFullReflect runs the core tests again with full reflect information for every type.
+func TestCoreFullReflect(t *testing.T) {
+	*fullReflectFlag = true
+	defer func() { *fullReflectFlag = false }()
+	testCore(t)
+}
+
+func testCore(t *testing.T) {
 	err := os.Chdir("tests/core")
 	if err != nil {
 		t.Error(err)
@@ -34,4 +54,28 @@ func TestCore(t *testing.T) {
 	}
 }
 
+// TestPanicExit checks that an unrecovered panic ends the program with exit status 2, as Go does.
+func TestPanicExit(t *testing.T) {
+	err := os.Chdir("tests/panic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir("../..")
+
+	err = doTestable([]string{"panic.go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := exec.Command("haxe", "-main", "tardis.Go", "-cp", "tardis", "--interp").CombinedOutput()
+	if ee, ok := err.(*exec.ExitError); !ok || ee.Sys().(syscall.WaitStatus).ExitStatus() != 2 {
+		t.Errorf("the program returned %v, want exit status 2", err)
+	}
+	for _, s := range []string{"deferred", "Panic in goroutine", "unrecovered"} {
+		if !strings.Contains(string(out), s) {
+			t.Errorf("the output does not contain %q:\n%s", s, out)
+		}
+	}
+}
+
 // NOTE: main Travis CI standard library tests are in a shell script in goroot/...
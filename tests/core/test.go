@@ -10,7 +10,9 @@ package main
 import (
 	"errors"
 	"fmt"
+	"reflect"
 	"runtime"
+	"syscall"
 	"unicode"
 	"unicode/utf8"
 	"unsafe"
@@ -1790,6 +1792,161 @@ type unaligned7 struct {
 	k uint8
 }
 
+// the struct and array locals here do not escape, so they should be held as Haxe locals, see pogo.ScalarReplaceable()
+type scalarPair struct {
+	a int
+	b float64
+	s string
+	p *int
+	i interface{}
+}
+
+func scalarSum(n int) int {
+	var v [4]int
+	for i := 0; i < n; i++ {
+		v[0] += i
+		v[3] = v[0] * 2
+	}
+	return v[0] + v[3]
+}
+
+func testScalarReplace() {
+	var sp scalarPair
+	TEQ("scalar zero int", sp.a, 0)
+	TEQfloat("scalar zero float", sp.b, 0.0, 0.0001)
+	TEQ("scalar zero string", sp.s, "")
+	TEQ("scalar zero pointer", sp.p == nil, true)
+	TEQ("scalar zero interface", sp.i, nil)
+	x := 42
+	sp.a = 1
+	sp.b = 2.5
+	sp.s = "three"
+	sp.p = &x
+	sp.i = sp.s
+	TEQ("scalar int", sp.a, 1)
+	TEQfloat("scalar float", sp.b, 2.5, 0.0001)
+	TEQ("scalar string", sp.s, "three")
+	TEQ("scalar pointer", *sp.p, 42)
+	TEQ("scalar interface", sp.i, "three")
+	sp2 := sp // a load of the whole value
+	sp2.a++
+	TEQ("scalar copy is a copy", sp.a, 1)
+	TEQ("scalar copy", sp2.a, 2)
+	TEQ("scalar copy string", sp2.s, "three")
+	sp = scalarPair{a: 9} // a store of the whole value
+	TEQ("scalar store int", sp.a, 9)
+	TEQ("scalar store string", sp.s, "")
+	TEQ("scalar store pointer", sp.p == nil, true)
+	TEQ("scalar array", scalarSum(5), 30)
+	for i := 0; i < 3; i++ {
+		var loop [2]string // each time round the loop this must be a new zero value
+		TEQ("scalar loop zero", loop[0]+loop[1], "")
+		loop[i%2] = "x"
+		TEQ("scalar loop set", loop[0]+loop[1], "x")
+	}
+	var big [9]int // too big to be scalar-replaced, to compare
+	var small [2]int
+	big[8], small[1] = 7, 7
+	TEQ("scalar compare", big[8], small[1])
+}
+
+// the GOint64 operations, which are native on cpp, java and cs, with values that need all 64 bits
+func testInt64Native() {
+	var a, b int64 = 0x123456789, -0x7654321
+	var u, v uint64 = 0xFEDCBA9876543210, 0x100000001
+	TEQint64("int64 add", a+b, 0x11BE02468)
+	TEQint64("int64 sub", b-a, -0x12AAAAAAA)
+	TEQint64("int64 mul", a*b, -0x86A1C974E1833A9)
+	TEQint64("int64 mul overflow", int64_max*int64(three), 0x7FFFFFFFFFFFFFFD)
+	TEQint64("int64 neg", -a, -0x123456789)
+	TEQint64("int64 neg overflow", -int64_mostNeg, int64_mostNeg)
+	TEQuint64("uint64 add overflow", u+u, 0xFDB97530ECA86420)
+	TEQuint64("uint64 sub overflow", v-u, 0x0123456889ABCDF1)
+	TEQuint64("uint64 mul overflow", u*v, 0x7530ECA876543210)
+	TEQuint64("uint64 and", u&v, 0x0000000000000000)
+	TEQuint64("uint64 or", u|v, 0xFEDCBA9976543211)
+	TEQuint64("uint64 xor", u^0xFFFFFFFF00000000, 0x0123456776543210)
+	TEQint64("int64 div", a/b, -0x27)
+	TEQint64("int64 mod", a%b, 0x2D82D82)
+	TEQuint64("uint64 div", u/v, 0xFEDCBA97)
+	TEQuint64("uint64 mod", u%v, 0x77777779)
+	TEQuint64("uint64 div big", u/0x8000000000000000, 1)
+	TEQuint64("uint64 mod big", u%0x8000000000000000, 0x7EDCBA9876543210)
+	sh := uint(36)
+	TEQint64("int64 shl", a<<sh, 0x3456789000000000)
+	TEQint64("int64 shr", b>>sh, -1)
+	TEQint64("int64 shr small", a>>(sh-32), 0x12345678)
+	TEQuint64("uint64 shr", u>>sh, 0xFEDCBA9)
+	TEQuint64("uint64 shl 64", u<<(sh+28), 0)
+	TEQint64("int64 shr 64", b>>(sh+28), -1)
+	TEQ("int64 compare", a > b, true)
+	TEQ("int64 compare high", int64_mostNeg < b, true)
+	TEQ("uint64 compare", u > v, true)
+	TEQ("uint64 compare top bit", uint64(int64_mostNeg) > uint64(int64_max), true)
+	var i32 int32 = -2
+	var u32 uint32 = 0xFFFFFFFE
+	TEQint64("int64 of int32", int64(i32), -2)
+	TEQuint64("uint64 of uint32", uint64(u32), 0xFFFFFFFE)
+	TEQint32("int32 of int64", int32(a), 0x23456789)
+	TEQuint32("uint32 of uint64", uint32(u>>32), 0xFEDCBA98)
+	TEQfloat("float64 of int64", float64(b), -0x7654321, 0)
+	TEQfloat("float64 of uint64", float64(u), 18364758544493064720.0, 1e5)
+	f := 1e18
+	TEQint64("int64 of float64", int64(-f), -1000000000000000000)
+	TEQuint64("uint64 of float64", uint64(f*10), 10000000000000000000)
+}
+
+type typedFloats []float64
+
+func testTypedSlices() {
+	b := make([]byte, 3, 4)
+	b[0], b[2] = 255, 1
+	b[1] = b[0] + 2 // wraps
+	TEQbyteSlice("typed []byte", b, []byte{255, 1, 1})
+	b = append(b, "xyz"...) // grows, keeping the native storage
+	TEQbyteSlice("typed []byte append", b, []byte{255, 1, 1, 'x', 'y', 'z'})
+	TEQ("typed []byte string", string(b[3:]), "xyz")
+	var arr [4]byte
+	TEQ("typed []byte copy to array", copy(arr[:], b[2:]), 4)
+	TEQbyteSlice("typed []byte array", arr[:], []byte{1, 'x', 'y', 'z'})
+	TEQ("typed []byte copy from array", copy(b, arr[1:]), 3)
+	TEQbyteSlice("typed []byte copied", b, []byte{'x', 'y', 'z', 'x', 'y', 'z'})
+	copy(b[1:], b) // overlapping, forwards
+	TEQbyteSlice("typed []byte overlap up", b, []byte{'x', 'x', 'y', 'z', 'x', 'y'})
+	copy(b, b[2:]) // overlapping, backwards
+	TEQbyteSlice("typed []byte overlap down", b, []byte{'y', 'z', 'x', 'y', 'x', 'y'})
+
+	n := make([]int, 2)
+	n[1] = -7
+	n = append(n, n[:0]...) // append nothing
+	n = append(n, 1<<30, -1<<31)
+	TEQintSlice("typed []int", n, []int{0, -7, 1 << 30, -1 << 31})
+	n[0] = n[2] + n[2] // overflows when int is 32 bits
+	TEQint32("typed []int overflow", int32(n[0]), -1<<31)
+	r := make([]rune, 0, 1)
+	r = append(r, []rune("héllo")...)
+	TEQruneSlice("typed []rune", r, []rune{'h', 'é', 'l', 'l', 'o'})
+	TEQ("typed []rune string", string(r[1:3]), "él")
+
+	f := make(typedFloats, 3)
+	f[0], f[1] = 1.5, -0.25
+	f = append(f[:2], f...)
+	TEQfloat("typed []float64 0", f[0], 1.5, 0)
+	TEQfloat("typed []float64 3", f[3], -0.25, 0)
+	TEQfloat("typed []float64 4", f[4], 0, 0)
+	TEQ("typed []float64 len", len(f), 5)
+	f[4] += f[0] * 2
+	TEQfloat("typed []float64 sum", f[4], 3, 0)
+	fa := [2]float64{2.5, 3.5}
+	copy(f[1:], fa[:])
+	TEQfloat("typed []float64 from array", f[1]+f[2], 6, 0)
+	pf := &f[2] // a pointer into the native storage
+	*pf = 9
+	TEQfloat("typed []float64 pointer", f[2], 9, 0)
+	var i interface{} = f[1:2]
+	TEQfloat("typed []float64 interface", i.(typedFloats)[0], 2.5, 0)
+}
+
 func testUnaligned() {
 	var x [3]unaligned7
 	//y := interface{}(x)
@@ -1819,6 +1976,182 @@ func testUnaligned() {
 	}
 }
 
+type convPoint struct {
+	X, Y   int
+	Name   string
+	hidden bool
+}
+
+type convTree struct {
+	Label string
+	Kids  []convTree
+}
+
+// convToHaxe and convFromHaxe pass their values as interfaces, so that the run-time types choose the conversions
+func convToHaxe(v interface{}) uintptr      { return hx.ToHaxe(v) }
+func convFromHaxe(v uintptr, p interface{}) { hx.FromHaxe(v, p) }
+
+func testHxConvert() {
+	ss := []string{"a", "héllo", ""}
+	arr := hx.ToHaxe(ss)
+	TEQ("ToHaxe []string is Array", hx.CodeBool("", "Std.is(_a.param(0).val,Array);", arr), true)
+	TEQ("ToHaxe []string length", hx.CodeInt("", "_a.param(0).val.length;", arr), 3)
+	TEQ("ToHaxe []string item", hx.CodeBool("", "_a.param(0).val[1]=='héllo';", arr), true)
+	var back []string
+	hx.FromHaxe(arr, &back)
+	TEQ("FromHaxe Array<String> length", len(back), 3)
+	TEQ("FromHaxe Array<String> item", back[1], "héllo")
+	var ints []int
+	hx.FromHaxe(hx.CodeDynamic("", "[1,-2,3];"), &ints)
+	TEQintSlice("FromHaxe Array<Int>", ints, []int{1, -2, 3})
+
+	m := map[string]int{"one": 1, "two": 2}
+	hm := hx.ToHaxe(m)
+	TEQ("ToHaxe map[string]int get", hx.CodeInt("", "_a.param(0).val.get('two');", hm), 2)
+	TEQ("ToHaxe map[string]int exists", hx.CodeBool("", "_a.param(0).val.exists('three');", hm), false)
+	var m2 map[string]int
+	hx.FromHaxe(hm, &m2)
+	TEQ("FromHaxe Map<String,Int> len", len(m2), 2)
+	TEQ("FromHaxe Map<String,Int> item", m2["one"], 1)
+	hx.FromHaxe(hx.CodeDynamic("", "var m=new Map<String,Int>(); m.set('x',7); m;"), &m2)
+	TEQ("FromHaxe Haxe Map len", len(m2), 1)
+	TEQ("FromHaxe Haxe Map item", m2["x"], 7)
+
+	p := convPoint{X: 1, Y: -2, Name: "p", hidden: true}
+	hp := hx.ToHaxe(p)
+	TEQ("ToHaxe struct field", hx.CodeInt("", "_a.param(0).val.Y;", hp), -2)
+	TEQ("ToHaxe struct string field", hx.CodeBool("", "_a.param(0).val.Name=='p';", hp), true)
+	TEQ("ToHaxe struct unexported field", hx.CodeBool("", "Reflect.hasField(_a.param(0).val,'hidden');", hp), false)
+	var p2 convPoint
+	hx.FromHaxe(hp, &p2)
+	TEQ("FromHaxe anonymous object", p2.X == 1 && p2.Y == -2 && p2.Name == "p" && !p2.hidden, true)
+	hx.FromHaxe(hx.CodeDynamic("", "({X:5, Y:6, Name:'q'});"), &p2)
+	TEQ("FromHaxe Haxe anonymous object", p2.X == 5 && p2.Y == 6 && p2.Name == "q", true)
+
+	var ns []string
+	var nm map[string]int
+	TEQ("ToHaxe nil slice", hx.CodeInt("", "_a.param(0).val.length;", hx.ToHaxe(ns)), 0)
+	TEQ("ToHaxe nil map", hx.CodeBool("", "_a.param(0).val.keys().hasNext();", hx.ToHaxe(nm)), false)
+	hx.FromHaxe(hx.Null(), &back)
+	TEQ("FromHaxe null Array", back == nil, true)
+	hx.FromHaxe(hx.Null(), &m2)
+	TEQ("FromHaxe null Map", m2 == nil, true)
+
+	tr := convTree{Label: "root", Kids: []convTree{{Label: "kid", Kids: []convTree{{Label: "grandkid"}}}}}
+	ht := hx.ToHaxe(tr)
+	TEQ("ToHaxe recursive type", hx.CodeInt("", "_a.param(0).val.Kids.length;", ht), 1)
+	var tr2 convTree
+	hx.FromHaxe(ht, &tr2)
+	TEQ("FromHaxe recursive type", tr2.Label, "root")
+	TEQ("FromHaxe recursive type kid", tr2.Kids[0].Label, "kid")
+	TEQ("FromHaxe recursive type grandkid", tr2.Kids[0].Kids[0].Label, "grandkid")
+
+	back, m2, p2 = nil, nil, convPoint{}
+	convFromHaxe(convToHaxe(ss), &back)
+	TEQ("interface []string round trip", len(back) == 3 && back[1] == "héllo", true)
+	convFromHaxe(convToHaxe(m), &m2)
+	TEQ("interface map round trip", len(m2) == 2 && m2["two"] == 2, true)
+	convFromHaxe(convToHaxe(p), &p2)
+	TEQ("interface struct round trip", p2.X == 1 && p2.Name == "p" && !p2.hidden, true)
+	TEQ("interface string to Haxe", hx.CodeBool("", "_a.param(0).val=='héllo';", convToHaxe("héllo")), true)
+	n, f := 0, 0.0
+	convFromHaxe(convToHaxe(42), &n)
+	TEQ("interface int round trip", n, 42)
+	convFromHaxe(convToHaxe(-1.5), &f)
+	TEQ("interface float64 round trip", f, -1.5)
+}
+
+func testHxCodeTemplate() {
+	name := "héllo"
+	n, f := 42, -1.5
+	sl := []int{1, 2, 3}
+	d := hx.CodeDynamic("", "[7,8];")
+	TEQ("hx.Code {0} string", hx.CodeBool("", "{0}=='héllo';", name), true)
+	TEQ("hx.Code {name} string", hx.CodeBool("", "{name}+'!'=='héllo!';", name), true)
+	TEQ("hx.Code {n} int", hx.CodeInt("", "{n}+1;", n), 43)
+	TEQ("hx.Code {1} float", hx.CodeFloat("", "{1}*2;", n, f), -3.0)
+	TEQ("hx.Code {sl} slice", hx.CodeInt("", "{sl}.length+{sl}[2];", sl), 6)
+	TEQ("hx.Code {0} Dynamic", hx.CodeInt("", "{0}[1];", d), 8)
+	TEQ("hx.Code {d} Dynamic", hx.CodeBool("", "Std.is({d},Array);", d), true)
+	TEQ("hx.Code mixed", hx.CodeFloat("", "{n}+{1}+{f};", n, f), 39.0)
+	TEQ("hx.Code other braces", hx.CodeInt("", "({a:{n}}).a;", n), 42)
+	TEQ("hx.Code placeholder in a string", hx.CodeString("", "'{0}'+\"{n}\"+{n};", n), "{0}{n}42")
+	TEQ("hx.Code placeholder in a comment", hx.CodeInt("", "/* {0} */ {n};", n), 42)
+	TEQ("hx.Code converts once", hx.CodeInt("", "{sl}[0]=9; {sl}[0];", sl), 9)
+}
+
+type reflPt struct{ X int }
+
+type reflHolder struct {
+	P    *reflPt
+	Tags map[string]uint16
+}
+
+func (reflHolder) Scale(f float32) (reflPt, error) { return reflPt{}, nil }
+
+// testReflect reflects on types only reached through a pointer, a struct field or a method signature,
+// and makes values of compiled types that are only asserted to, so reflect must find those types.
+func testReflect() {
+	h := reflHolder{P: &reflPt{X: 3}}
+	v := reflect.ValueOf(h)
+	TEQint64("reflect through a pointer", v.Field(0).Elem().Field(0).Int(), 3)
+	TEQ("reflect pointer element type", v.Field(0).Type().Elem().Name(), "reflPt")
+	TEQ("reflect struct field type", v.Type().Field(1).Type.Key().Kind() == reflect.String, true)
+	TEQ("reflect struct field element type", v.Type().Field(1).Type.Elem().Kind() == reflect.Uint16, true)
+	m, found := v.Type().MethodByName("Scale")
+	TEQ("reflect method", found, true)
+	TEQ("reflect method parameter", m.Type.In(1).Kind() == reflect.Float32, true)
+	TEQ("reflect method result", m.Type.Out(0).Name(), "reflPt")
+	TEQ("reflect method result field", m.Type.Out(0).Field(0).Name, "X")
+	TEQ("reflect method error result", m.Type.Out(1).Name(), "error")
+
+	s, ok := reflect.MakeSlice(reflect.SliceOf(reflect.TypeOf(0)), 2, 2).Interface().([]int)
+	TEQ("reflect.SliceOf gives the compiled type", ok && len(s) == 2, true)
+	mp, ok := reflect.MakeMap(reflect.MapOf(reflect.TypeOf(""), reflect.TypeOf(true))).Interface().(map[string]bool)
+	TEQ("reflect.MapOf gives the compiled type", ok && len(mp) == 0, true)
+	var ch interface{} = reflect.MakeChan(reflect.ChanOf(reflect.BothDir, reflect.TypeOf(int8(0))), 1).Interface()
+	switch ch.(type) {
+	case chan int8:
+	default:
+		TEQ("reflect.ChanOf gives the compiled type", false, true)
+	}
+}
+
+type hxIntArg int
+
+// hxIsInt passes x to Haxe in an interface value, so its dynamic type decides how it is passed.
+func hxIsInt(x interface{}) bool {
+	return hx.CallBool("", "Std.is", 2, x, hx.CodeDynamic("", "Int;"))
+}
+
+// testHxIntArgs checks that Go int and uint values reach Haxe as an Int, whatever the -intsize.
+func testHxIntArgs() {
+	n, u, h := 42, uint(7), hxIntArg(-3)
+	intClass := hx.CodeDynamic("", "Int;")
+	TEQ("hx.CallInt int arg", hx.CallInt("", "Std.int", 1, n), 42)
+	TEQ("hx.CallBool int arg is Int", hx.CallBool("", "Std.is", 2, n, intClass), true)
+	TEQ("hx.CallBool uint arg is Int", hx.CallBool("", "Std.is", 2, u, intClass), true)
+	TEQ("hx.CallBool named int arg is Int", hx.CallBool("", "Std.is", 2, h, intClass), true)
+	TEQ("hx.CallString int args", hx.CallString("", "StringTools.hex", 2, 255, n/10), "00FF")
+	TEQ("hx.CallBool int arg in an interface is Int", hxIsInt(n), true)
+	TEQ("hx.CallBool uint arg in an interface is Int", hxIsInt(u), true)
+	TEQ("hx.CallBool string arg in an interface is not Int", hxIsInt("42"), false)
+}
+
+// testStdio checks that stdout is described as a character device or a pipe, that cannot seek.
+func testStdio() {
+	if runtime.GOOS != "nacl" { // the host stdout may be a regular file
+		return
+	}
+	var st syscall.Stat_t
+	err := syscall.Fstat(1, &st)
+	TEQ("syscall.Fstat(1) error", err, nil)
+	mode := st.Mode & syscall.S_IFMT
+	TEQ("syscall.Fstat(1) mode is a character device or a pipe", mode == syscall.S_IFCHR || mode == syscall.S_IFIFO, true)
+	_, err = syscall.Seek(1, 0, 0)
+	TEQ("syscall.Seek(1, 0, 0) error", err, error(syscall.ESPIPE))
+}
+
 func main() {
 	var array [4][5]int
 	array[3][2] = 12
@@ -1867,6 +2200,14 @@ func main() {
 	testObjMap()
 	testFloatConv()
 	testUnaligned()
+	testScalarReplace()
+	testTypedSlices()
+	testInt64Native()
+	testHxConvert()
+	testHxCodeTemplate()
+	testHxIntArgs()
+	testReflect()
+	testStdio()
 	//aGrWG.Wait()
 	TEQint32(""+" testManyGoroutines() (NOT sync/atomic) counter:", aGrCtr, 0)
 	if runtime.GOOS == "nacl" { // really a haxe emulation of nacl
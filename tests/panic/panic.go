@@ -0,0 +1,9 @@
+// An unrecovered panic, which must end the program with exit status 2, after the deferred code has run.
+package main
+
+import "fmt"
+
+func main() {
+	defer fmt.Println("deferred")
+	panic("unrecovered")
+}
@@ -25,7 +25,7 @@ type isOverloaded func(*ssa.Function) bool
 //
 // TODO(adonovan): test coverage.
 
-// VisitedFunctions finds only those functions visited from the main package, using the logic of:
+// VisitedFunctions finds only those functions visited from the given packages and root functions, using the logic of:
 // AllFunctions finds and returns the set of functions potentially
 // needed by program prog, as determined by a simple linker-style
 // reachability algorithm starting from the members and method-sets of
@@ -34,10 +34,11 @@ type isOverloaded func(*ssa.Function) bool
 //
 // Precondition: all packages are built.
 //
-func VisitedFunctions(prog *ssa.Program, packs []*ssa.Package, isOvl isOverloaded) (seen, usesGR map[*ssa.Function]bool) {
+func VisitedFunctions(prog *ssa.Program, packs []*ssa.Package, roots []*ssa.Function, isOvl isOverloaded) (seen, usesGR map[*ssa.Function]bool) {
 	visit := visitor{
 		prog:   prog,
 		packs:  packs, // new
+		roots:  roots, // new
 		seen:   make(map[*ssa.Function]bool),
 		usesGR: make(map[*ssa.Function]bool),
 	}
@@ -49,7 +50,8 @@ func VisitedFunctions(prog *ssa.Program, packs []*ssa.Package, isOvl isOverloade
 
 type visitor struct {
 	prog   *ssa.Program
-	packs  []*ssa.Package // new
+	packs  []*ssa.Package  // new
+	roots  []*ssa.Function // new, functions called from outside Go
 	seen   map[*ssa.Function]bool
 	usesGR map[*ssa.Function]bool // new
 }
@@ -71,6 +73,12 @@ func (visit *visitor) program(isOvl isOverloaded) {
 			}
 		}
 	}
+	for _, fn := range visit.roots {
+		visit.function(fn, isOvl)
+		if visit.usesGR[fn] {
+			visit.refsUseGR(fn.Referrers(), make(map[*ssa.Function]bool))
+		}
+	}
 	for _, T := range visit.prog.RuntimeTypes() {
 		mset := visit.prog.MethodSets.MethodSet(T)
 		for i, n := 0, mset.Len(); i < n; i++ {